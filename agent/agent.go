@@ -1,9 +1,16 @@
 package agent
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
 	"github.com/darkhz/bluetuith/ui"
 	"github.com/godbus/dbus/v5"
 	"github.com/godbus/dbus/v5/introspect"
@@ -26,25 +33,33 @@ const (
 var (
 	agent           *Agent
 	alwaysAuthorize bool
+	defaultAgent    bool
+
+	defaultAgentErr error
+
+	conflictingAgents []string
 )
 
 // Agent describes a bluez agent. It holds the dbus connection,
 // the pincode and passkey to be provided during authentication attempts.
 // This is mainly used to describe various authentication methods and export
-// them to the bluez DBus inteface.
+// them to the bluez DBus inteface. The cancel function cancels the prompt
+// which is currently shown to the user, if any.
 type Agent struct {
 	conn    *dbus.Conn
 	pinCode string
 	passKey uint32
+
+	cancel context.CancelFunc
+	lock   sync.Mutex
 }
 
-// NewAgent returns a new Agent.
-func NewAgent() (*Agent, error) {
+// NewAgent returns a new Agent, which uses the provided system bus connection.
+func NewAgent(conn *dbus.Conn) (*Agent, error) {
 	var ag *Agent
 
-	conn, err := dbus.SystemBus()
-	if err != nil {
-		return nil, err
+	if conn == nil {
+		return nil, errors.New("No system bus connection")
 	}
 
 	ag = &Agent{
@@ -61,7 +76,12 @@ func NewAgent() (*Agent, error) {
 func SetupAgent(conn *dbus.Conn) error {
 	var err error
 
-	agent, err = NewAgent()
+	if cmd.IsPropertyEnabled("no-agent") {
+		cmd.LogAgent("Agent: Not registering the agent, since 'no-agent' is set")
+		return nil
+	}
+
+	agent, err = NewAgent(conn)
 	if err != nil {
 		return err
 	}
@@ -70,21 +90,97 @@ func SetupAgent(conn *dbus.Conn) error {
 		return err
 	}
 
-	return RegisterAgent()
+	if err := RegisterAgent(); err != nil {
+		return err
+	}
+
+	go watchService()
+
+	return nil
 }
 
 // RemoveAgent removes the agent.
 func RemoveAgent() error {
+	if agent == nil {
+		return nil
+	}
+
 	return UnregisterAgent()
 }
 
-// RegisterAgent registers the agent.
+// RegisterAgent registers the agent, and requests it to be the default agent.
+// If other agents are found to be running and the application was launched
+// interactively, the user is asked whether the agent should take over as the
+// default agent. If the request fails, the agent stays registered, and the
+// error is stored so that the user can be warned.
 func RegisterAgent() error {
-	if err := CallAgentManager("RegisterAgent", AgentPath, "KeyboardDisplay").Store(); err != nil {
+	if err := CallAgentManager("RegisterAgent", AgentPath, cmd.GetProperty("agent-capability")).Store(); err != nil {
+		cmd.LogAgent("Agent: Could not register: %s", err)
 		return err
 	}
+	cmd.LogAgent("Agent: Registered with the %s capability", cmd.GetProperty("agent-capability"))
+
+	conflictingAgents = findConflictingAgents(agent.conn)
+	if conflictingAgents != nil {
+		agents := strings.Join(conflictingAgents, ", ")
+
+		cmd.LogAgent("Agent: Found other agents: %s", agents)
+
+		if cmd.IsInteractive() &&
+			!cmd.PromptConfirm("Other bluetooth agents ("+agents+") are running. Take over as the default agent?", true) {
+			cmd.LogAgent("Agent: Not requested as the default agent")
+			return nil
+		}
+	}
+
+	if err := CallAgentManager("RequestDefaultAgent", AgentPath).Store(); err != nil {
+		cmd.LogAgent("Agent: Could not request as the default agent: %s", err)
+		defaultAgentErr = err
 
-	return CallAgentManager("RequestDefaultAgent", AgentPath).Store()
+		return nil
+	}
+
+	cmd.LogAgent("Agent: Registered as the default agent")
+	defaultAgent = true
+
+	return nil
+}
+
+// DefaultAgentError returns the error which occurred when the
+// agent was requested to be the default agent, if any.
+func DefaultAgentError() error {
+	return defaultAgentErr
+}
+
+// watchService registers the agent again when the bluez daemon restarts,
+// since the registered agents are lost when the daemon stops.
+func watchService() {
+	agent.conn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0, bluez.ServiceStateMatch)
+
+	signals := make(chan *dbus.Signal, 1)
+	agent.conn.Signal(signals)
+	defer agent.conn.RemoveSignal(signals)
+
+	for signal := range signals {
+		state, ok := bluez.ParseServiceState(signal)
+		if !ok || !state.Running {
+			continue
+		}
+
+		if err := CallAgentManager("RegisterAgent", AgentPath, cmd.GetProperty("agent-capability")).Store(); err != nil {
+			cmd.LogAgent("Agent: Could not register again after bluez restarted: %s", err)
+			continue
+		}
+
+		if defaultAgent {
+			if err := CallAgentManager("RequestDefaultAgent", AgentPath).Store(); err != nil {
+				cmd.LogAgent("Agent: Could not request as the default agent after bluez restarted: %s", err)
+				continue
+			}
+		}
+
+		cmd.LogAgent("Agent: Registered again after bluez restarted")
+	}
 }
 
 // ExportAgent exports all Agent methods to the bluez DBus interface.
@@ -117,18 +213,95 @@ func CallAgentManager(method string, args ...interface{}) *dbus.Call {
 	return agent.conn.Object(AgentBluezName, AgentManagerPath).Call(AgentManagerIface+"."+method, 0, args...)
 }
 
-// RequestPinCode returns the default pincode.
+// RequestPinCode returns the default pincode, or if the "agent-prompt" option
+// is set, asks for the pincode to be entered.
 func (a *Agent) RequestPinCode(path dbus.ObjectPath) (string, *dbus.Error) {
-	return a.pinCode, nil
+	if !cmd.IsPropertyEnabled("agent-prompt") {
+		cmd.LogAgent("RequestPinCode: %s: Provided the default pincode", logDevice(path))
+
+		return a.pinCode, nil
+	}
+
+	device, err := ui.GetDeviceFromPath(string(path))
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+
+	ctx, cancel := a.promptContext()
+	defer cancel()
+
+	msg := fmt.Sprintf(
+		"Enter the pincode for [::bu]%s[-:-:-]\n\n(1 to 16 characters, for example 0000)",
+		device.Name,
+	)
+
+	pincode, ok := ui.NewInputModal(ctx, "pincode-input", "Pin Code", msg)
+	if !ok {
+		cmd.LogAgent("RequestPinCode: %s: %s", logDevice(path), promptError(ctx))
+		return "", dbus.MakeFailedError(promptError(ctx))
+	}
+
+	if pincode == "" || len(pincode) > 16 {
+		err := fmt.Errorf("The pincode must be 1 to 16 characters long")
+
+		ui.ErrorMessage(err)
+		cmd.LogAgent("RequestPinCode: %s: Rejected an invalid pincode", logDevice(path))
+
+		return "", dbus.MakeFailedError(err)
+	}
+
+	cmd.LogAgent("RequestPinCode: %s: Entered a pincode", logDevice(path))
+
+	return pincode, nil
 }
 
-// RequestPasskey returns the default passkey.
+// RequestPasskey returns the default passkey, or if the "agent-prompt" option
+// is set, asks for the passkey to be entered.
 func (a *Agent) RequestPasskey(path dbus.ObjectPath) (uint32, *dbus.Error) {
-	return a.passKey, nil
+	if !cmd.IsPropertyEnabled("agent-prompt") {
+		cmd.LogAgent("RequestPasskey: %s: Provided the default passkey", logDevice(path))
+
+		return a.passKey, nil
+	}
+
+	device, err := ui.GetDeviceFromPath(string(path))
+	if err != nil {
+		return 0, dbus.MakeFailedError(err)
+	}
+
+	ctx, cancel := a.promptContext()
+	defer cancel()
+
+	msg := fmt.Sprintf(
+		"Enter the passkey for [::bu]%s[-:-:-]\n\n(A number from 0 to 999999)",
+		device.Name,
+	)
+
+	input, ok := ui.NewInputModal(ctx, "passkey-input", "Passkey", msg)
+	if !ok {
+		cmd.LogAgent("RequestPasskey: %s: %s", logDevice(path), promptError(ctx))
+		return 0, dbus.MakeFailedError(promptError(ctx))
+	}
+
+	passkey, err := strconv.ParseUint(strings.TrimSpace(input), 10, 32)
+	if err != nil || passkey > 999999 {
+		err := fmt.Errorf("The passkey must be a number from 0 to 999999")
+
+		ui.ErrorMessage(err)
+		cmd.LogAgent("RequestPasskey: %s: Rejected an invalid passkey", logDevice(path))
+
+		return 0, dbus.MakeFailedError(err)
+	}
+
+	cmd.LogAgent("RequestPasskey: %s: Entered a passkey", logDevice(path))
+
+	return uint32(passkey), nil
 }
 
 // DisplayPinCode shows a notification with the pincode.
 func (a *Agent) DisplayPinCode(path dbus.ObjectPath, pincode string) *dbus.Error {
+	cmd.LogAgent("DisplayPinCode: %s: Displayed the pincode", logDevice(path))
+
 	device, err := ui.GetDeviceFromPath(string(path))
 	if err != nil {
 		return dbus.MakeFailedError(err)
@@ -146,6 +319,8 @@ func (a *Agent) DisplayPinCode(path dbus.ObjectPath, pincode string) *dbus.Error
 
 // DisplayPasskey shows a notification with the passkey.
 func (a *Agent) DisplayPasskey(path dbus.ObjectPath, passkey uint32, entered uint16) *dbus.Error {
+	cmd.LogAgent("DisplayPasskey: %s: Displayed the passkey (entered %d)", logDevice(path), entered)
+
 	device, err := ui.GetDeviceFromPath(string(path))
 	if err != nil {
 		return dbus.MakeFailedError(err)
@@ -164,60 +339,100 @@ func (a *Agent) DisplayPasskey(path dbus.ObjectPath, passkey uint32, entered uin
 	return nil
 }
 
-// RequestConfirmation shows the passkey and asks for confirmation.
+// RequestConfirmation shows the passkey and asks for confirmation,
+// unless pairing requests from the device are automatically accepted.
 func (a *Agent) RequestConfirmation(path dbus.ObjectPath, passkey uint32) *dbus.Error {
 	device, err := ui.GetDeviceFromPath(string(path))
 	if err != nil {
 		return dbus.MakeFailedError(err)
 	}
 
-	msg := fmt.Sprintf(
-		"Confirm passkey for [::bu]%s[-:-:-] is \n\n[::b]%d[-:-:-]",
-		device.Name, passkey,
-	)
-
-	reply := ui.NewConfirmModal("passkey-confirm", "Passkey Confirmation", msg)
-	if reply != "y" {
-		return dbus.MakeFailedError(errors.New("Cancelled"))
+	if cmd.IsPairAutoAccepted(device.Address) {
+		logAutoAccept("RequestConfirmation: %s: Auto-accepted the passkey", logDevice(path))
+	} else {
+		msg := fmt.Sprintf(
+			"Confirm passkey for [::bu]%s[-:-:-] is \n\n[::b]%d[-:-:-]",
+			device.Name, passkey,
+		)
+
+		ctx, cancel := a.promptContext()
+		defer cancel()
+
+		reply := ui.NewConfirmModal(ctx, "passkey-confirm", "Passkey Confirmation", msg)
+		if reply != "y" {
+			cmd.LogAgent("RequestConfirmation: %s: Rejected the passkey (%s)", logDevice(path), promptError(ctx))
+			return dbus.MakeFailedError(promptError(ctx))
+		}
 	}
 
 	err = ui.SetTrusted(string(path), true)
 	if err != nil {
+		cmd.LogAgent("RequestConfirmation: %s: Could not set trusted: %s", logDevice(path), err)
 		return dbus.MakeFailedError(err)
 	}
 
+	cmd.LogAgent("RequestConfirmation: %s: Confirmed the passkey", logDevice(path))
+
 	return nil
 }
 
-// RequestAuthorization asks for confirmation before pairing.
+// RequestAuthorization asks for confirmation before pairing,
+// unless pairing requests from the device are automatically accepted.
 func (a *Agent) RequestAuthorization(path dbus.ObjectPath) *dbus.Error {
 	device, err := ui.GetDeviceFromPath(string(path))
 	if err != nil {
 		return dbus.MakeFailedError(err)
 	}
 
-	msg := fmt.Sprintf("Confirm pairing with [::bu]%s[-:-:-]", device.Name)
+	if cmd.IsPairAutoAccepted(device.Address) {
+		logAutoAccept("RequestAuthorization: %s: Auto-accepted pairing", logDevice(path))
+	} else {
+		msg := fmt.Sprintf("Confirm pairing with [::bu]%s[-:-:-]", device.Name)
 
-	reply := ui.NewConfirmModal("pairing-confirm", "Pairing Confirmation", msg)
-	if reply != "y" {
-		return dbus.MakeFailedError(errors.New("Cancelled"))
+		ctx, cancel := a.promptContext()
+		defer cancel()
+
+		reply := ui.NewConfirmModal(ctx, "pairing-confirm", "Pairing Confirmation", msg)
+		if reply != "y" {
+			cmd.LogAgent("RequestAuthorization: %s: Rejected pairing (%s)", logDevice(path), promptError(ctx))
+			return dbus.MakeFailedError(promptError(ctx))
+		}
 	}
 
 	err = ui.SetTrusted(string(path), true)
 	if err != nil {
+		cmd.LogAgent("RequestAuthorization: %s: Could not set trusted: %s", logDevice(path), err)
 		return dbus.MakeFailedError(err)
 	}
 
+	cmd.LogAgent("RequestAuthorization: %s: Authorized pairing", logDevice(path))
+
 	return nil
 }
 
 // AuthorizeService asks for confirmation before authorizing a service UUID.
 // If alwaysAuthorize is set, all services are automatically authorized.
+// Services of devices which only initiate connections themselves, according to
+// their "connect-direction" property, are authorized and the devices are trusted.
 func (a *Agent) AuthorizeService(device dbus.ObjectPath, uuid string) *dbus.Error {
 	if alwaysAuthorize {
+		cmd.LogAgent("AuthorizeService: %s: Authorized service %s (always)", logDevice(device), uuid)
 		return nil
 	}
 
+	if d, err := ui.GetDeviceFromPath(string(device)); err == nil {
+		if cmd.GetDeviceConnectDirection(d.Address) == "incoming" {
+			if !d.Trusted {
+				if err := ui.SetTrusted(string(device), true); err != nil {
+					cmd.LogAgent("AuthorizeService: %s: Could not set trusted: %s", logDevice(device), err)
+				}
+			}
+
+			cmd.LogAgent("AuthorizeService: %s: Authorized service %s (connect-direction: incoming)", logDevice(device), uuid)
+			return nil
+		}
+	}
+
 	msg := fmt.Sprintf("Authorize service %s (y/n/a)", uuid)
 
 	reply := ui.SetInput(msg)
@@ -227,18 +442,86 @@ func (a *Agent) AuthorizeService(device dbus.ObjectPath, uuid string) *dbus.Erro
 		fallthrough
 
 	case "y":
+		cmd.LogAgent("AuthorizeService: %s: Authorized service %s", logDevice(device), uuid)
 		return nil
 	}
 
+	cmd.LogAgent("AuthorizeService: %s: Rejected service %s", logDevice(device), uuid)
+
 	return dbus.MakeFailedError(errors.New("Cancelled"))
 }
 
 // Cancel is called when the agent request was cancelled.
+// The prompt of the request, if shown, is closed.
 func (a *Agent) Cancel() *dbus.Error {
+	cmd.LogAgent("Cancel: The agent request was cancelled")
+
+	a.lock.Lock()
+	if a.cancel != nil {
+		a.cancel()
+	}
+	a.lock.Unlock()
+
 	return nil
 }
 
 // Release is called when the agent is unregistered.
 func (a *Agent) Release() *dbus.Error {
+	cmd.LogAgent("Release: The agent was released")
+
 	return nil
 }
+
+// promptContext returns a context for a prompt shown to the user, which is
+// cancelled after the duration set by the "agent-timeout" option, or when
+// the agent request is cancelled by bluez.
+func (a *Agent) promptContext() (context.Context, context.CancelFunc) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+
+	timeout, _ := time.ParseDuration(cmd.GetProperty("agent-timeout"))
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+
+	a.lock.Lock()
+	a.cancel = cancel
+	a.lock.Unlock()
+
+	return ctx, cancel
+}
+
+// promptError returns the reason why a prompt was not answered.
+func promptError(ctx context.Context) error {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return errors.New("Timed out")
+	}
+
+	return errors.New("Cancelled")
+}
+
+// logAutoAccept logs a pairing request or a transfer which was automatically accepted
+// or rejected. In the "receive" mode, the message is also printed along with the
+// received files.
+func logAutoAccept(format string, v ...interface{}) {
+	if cmd.IsPropertyEnabled("receive") {
+		cmd.LogReceive(format, v...)
+		return
+	}
+
+	cmd.Log("agent", format, v...)
+}
+
+// logDevice returns the name and address of the device
+// with the provided path, to be written to the debug log.
+func logDevice(path dbus.ObjectPath) string {
+	device, err := ui.GetDeviceFromPath(string(path))
+	if err != nil {
+		return string(path)
+	}
+
+	return device.Name + " (" + device.Address + ")"
+}
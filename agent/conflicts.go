@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// knownAgents lists the processes of other applications, which
+// may register themselves as the default bluetooth agent.
+var knownAgents = []string{
+	"blueman-applet",
+	"bluetoothctl",
+	"bt-agent",
+	"bluedevil-wizard",
+	"blueberry-tray",
+}
+
+// ConflictingAgents returns the names of the other agent processes that were
+// found when the agent was set up, if the agent did not take over as the
+// default agent.
+func ConflictingAgents() []string {
+	if defaultAgent {
+		return nil
+	}
+
+	return conflictingAgents
+}
+
+// findConflictingAgents returns the names of the known agent processes which
+// are connected to the system bus, since an agent has to be connected to the
+// system bus to be registered with bluez.
+func findConflictingAgents(conn *dbus.Conn) []string {
+	var names []string
+
+	if err := conn.BusObject().Call("org.freedesktop.DBus.ListNames", 0).Store(&names); err != nil {
+		return nil
+	}
+
+	pid := uint32(os.Getpid())
+	found := make(map[string]struct{})
+
+	for _, name := range names {
+		// Each connection has exactly one unique name, which starts with ':'.
+		if !strings.HasPrefix(name, ":") {
+			continue
+		}
+
+		var namePid uint32
+		if err := conn.BusObject().Call("org.freedesktop.DBus.GetConnectionUnixProcessID", 0, name).Store(&namePid); err != nil || namePid == pid {
+			continue
+		}
+
+		data, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", namePid))
+		if err != nil {
+			continue
+		}
+
+		comm := strings.TrimSpace(string(data))
+
+		for _, knownAgent := range knownAgents {
+			// The process name is truncated to 15 characters by the kernel.
+			if len(knownAgent) > 15 {
+				knownAgent = knownAgent[:15]
+			}
+
+			if comm == knownAgent {
+				found[comm] = struct{}{}
+			}
+		}
+	}
+
+	var agents []string
+	for name := range found {
+		agents = append(agents, name)
+	}
+	sort.Strings(agents)
+
+	return agents
+}
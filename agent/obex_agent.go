@@ -4,6 +4,7 @@ import (
 	"errors"
 	"path/filepath"
 
+	"github.com/darkhz/bluetuith/cmd"
 	"github.com/darkhz/bluetuith/ui"
 	"github.com/godbus/dbus/v5"
 	"github.com/godbus/dbus/v5/introspect"
@@ -104,6 +105,8 @@ func CallObexAgentManager(method string, args ...interface{}) *dbus.Call {
 // AuthorizePush asks for confirmation before receiving a transfer from the host device.
 // If the "Accept all" reply is given in response to the confirmation query, the device
 // will be added to a list of known devices and all transfers will be automatically accepted.
+// Transfers from trusted devices are accepted if the "auto-accept-trusted" option is set,
+// and transfers larger than the "receive-max-size" option are always rejected.
 func (o *ObexAgent) AuthorizePush(transferPath dbus.ObjectPath) (string, *dbus.Error) {
 	var msg, reply string
 
@@ -116,15 +119,54 @@ func (o *ObexAgent) AuthorizePush(transferPath dbus.ObjectPath) (string, *dbus.E
 
 	path, device, transferProps, err := ui.UI.Obex.ReceiveFile(sessionPath, transferPath)
 	if err != nil {
+		cmd.LogAgent("AuthorizePush: %s: Could not receive file: %s", transferPath, err)
 		return "", dbus.MakeFailedError(err)
 	}
 
+	if maxSize := cmd.GetReceiveMaxSize(); maxSize > 0 && transferProps.Size > maxSize {
+		adapter.Lock.Release(1)
+
+		logAutoAccept(
+			"AuthorizePush: %s: Rejected file %s (size %d bytes exceeds the maximum of %d bytes)",
+			device, filepath.Base(path), transferProps.Size, maxSize,
+		)
+
+		return "", dbus.MakeFailedError(errors.New("File too large"))
+	}
+
+	if _, err := ui.GetDeviceReceiveDir(device); err != nil {
+		adapter.Lock.Release(1)
+
+		cmd.LogAgent("AuthorizePush: %s: Could not create the receive directory: %s", device, err)
+		return "", dbus.MakeFailedError(err)
+	}
+
+	if cmd.IsPropertyEnabled("receive") {
+		cmd.LogAgent("AuthorizePush: %s: Accepted file %s (receive)", device, filepath.Base(path))
+
+		signals := ui.UI.Obex.WatchSignal()
+		go func() {
+			defer adapter.Lock.Release(1)
+
+			receiveFile(signals, transferPath, device, path)
+			ui.UI.Obex.RemoveSession(sessionPath)
+		}()
+
+		return path, nil
+	}
+
 	for _, knownDevice := range knownDevices {
 		if device == knownDevice {
+			cmd.LogAgent("AuthorizePush: %s: Accepted file %s (always)", device, filepath.Base(path))
 			goto SkipAuthentication
 		}
 	}
 
+	if cmd.IsPropertyEnabled("auto-accept-trusted") && isDeviceTrusted(device) {
+		logAutoAccept("AuthorizePush: %s: Auto-accepted file %s (trusted)", device, filepath.Base(path))
+		goto SkipAuthentication
+	}
+
 	msg = "Accept file " + filepath.Base(path) + " (y/n/a)?"
 	reply = ui.SetInput(msg)
 	switch reply {
@@ -135,22 +177,38 @@ func (o *ObexAgent) AuthorizePush(transferPath dbus.ObjectPath) (string, *dbus.E
 		break
 
 	default:
+		cmd.LogAgent("AuthorizePush: %s: Rejected file %s", device, filepath.Base(path))
 		return "", dbus.MakeFailedError(errors.New("Cancelled"))
 	}
 
+	cmd.LogAgent("AuthorizePush: %s: Accepted file %s", device, filepath.Base(path))
+
 SkipAuthentication:
 	go func() {
 		defer adapter.Lock.Release(1)
 
-		ui.StartProgress(transferPath, transferProps, path)
+		ui.StartProgress(transferPath, transferProps, device, path)
 		ui.UI.Obex.RemoveSession(sessionPath)
 	}()
 
 	return path, nil
 }
 
+// isDeviceTrusted returns if the device with the provided address is trusted.
+func isDeviceTrusted(address string) bool {
+	for _, device := range ui.UI.Bluez.GetDevices() {
+		if device.Address == address {
+			return device.Trusted
+		}
+	}
+
+	return false
+}
+
 // Cancel is called when the OBEX agent request was cancelled.
 func (o *ObexAgent) Cancel() *dbus.Error {
+	cmd.LogAgent("Cancel: The OBEX agent request was cancelled")
+
 	return nil
 }
 
@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/ui"
+	"github.com/godbus/dbus/v5"
+)
+
+// Receive accepts all incoming file transfers without the interface,
+// and logs each received file until it is interrupted.
+func Receive() {
+	if !cmd.IsPropertyEnabled("obex") {
+		cmd.PrintError("Cannot receive files, since the bluez OBEX agent could not be setup.")
+	}
+
+	receiveDir := cmd.GetProperty("receive-dir")
+	if receiveDir == "" {
+		receiveDir = "the 'bluetuith' directory in the home directory"
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	cmd.LogReceive("Receiving files into %s, press Ctrl+C to stop", receiveDir)
+
+	<-interrupt
+
+	cmd.LogReceive("Stopped receiving files")
+}
+
+// receiveFile monitors an incoming transfer until it is finished,
+// and saves the received file to the receive directory.
+func receiveFile(signals chan *dbus.Signal, transferPath dbus.ObjectPath, device, path string) {
+	defer ui.UI.Obex.Conn().RemoveSignal(signals)
+
+	name := filepath.Base(path)
+
+	cmd.LogReceive("%s: Receiving %s", device, name)
+
+	for dbusSignal := range signals {
+		if dbusSignal.Path != transferPath {
+			continue
+		}
+
+		props, ok := ui.UI.Obex.ParseSignalData(dbusSignal).(bluez.ObexProperties)
+		if !ok {
+			continue
+		}
+
+		switch props.TransferProperties.Status {
+		case "complete":
+			savedPath, err := ui.SaveFile(path, device)
+			if err != nil {
+				cmd.LogReceive("%s: Could not save %s: %s", device, name, err)
+				return
+			}
+
+			cmd.LogReceive("%s: Received %s", device, savedPath)
+			return
+
+		case "error":
+			cmd.LogReceive("%s: Could not receive %s", device, name)
+			return
+		}
+	}
+}
@@ -20,6 +20,7 @@ type Adapter struct {
 	Pairable     bool
 	Powered      bool
 	Discovering  bool
+	Roles        []string
 
 	Lock *semaphore.Weighted
 }
@@ -27,7 +28,10 @@ type Adapter struct {
 // CallAdapter is used to interact with the bluez Adapter dbus interface.
 // https://git.kernel.org/pub/scm/bluetooth/bluez.git/tree/doc/adapter-api.txt
 func (b *Bluez) CallAdapter(adapter, method string, flags dbus.Flags, args ...interface{}) *dbus.Call {
-	return b.conn.Object(dbusBluezName, dbus.ObjectPath(adapter)).Call("org.bluez.Adapter1."+method, flags, args...)
+	call := b.conn.Object(dbusBluezName, dbus.ObjectPath(adapter)).Call("org.bluez.Adapter1."+method, flags, args...)
+	b.logOperation(adapter, method, args, call.Err)
+
+	return call
 }
 
 // StartDiscovery will put the adapter into "discovering" mode, which means
@@ -103,6 +107,11 @@ func (b *Bluez) GetAdapters() []Adapter {
 	return adapters
 }
 
+// GetAdapter returns the adapter with the provided path, with its most recent properties.
+func (b *Bluez) GetAdapter(adapterPath string) Adapter {
+	return b.getAdapterFromStore(adapterPath)
+}
+
 // GetAdapterID gets the adapter ID from the adapter path.
 func GetAdapterID(adapterPath string) string {
 	currentAdapter := strings.Split(adapterPath, "/")
@@ -110,6 +119,17 @@ func GetAdapterID(adapterPath string) string {
 	return currentAdapter[len(currentAdapter)-1]
 }
 
+// GetAdapterRoles returns the supported roles of the adapter.
+// Older versions of bluez do not provide the roles, in which
+// case "unknown" is returned.
+func GetAdapterRoles(adapter Adapter) string {
+	if len(adapter.Roles) == 0 {
+		return "unknown"
+	}
+
+	return strings.Join(adapter.Roles, ", ")
+}
+
 // GetCurrentAdapterID gets the adapter ID from the current
 // adapter's path.
 func (b *Bluez) GetCurrentAdapterID() string {
@@ -133,6 +153,7 @@ func (b *Bluez) ConvertToAdapter(path string, values map[string]dbus.Variant, ad
 					Address => dbus.Variant{sig:dbus.Signature{str:"s"}, value:"9C:B6:D0:1C:BB:B0"}
 					Name => dbus.Variant{sig:dbus.Signature{str:"s"}, value:"jonathan-Blade"}
 					Alias => dbus.Variant{sig:dbus.Signature{str:"s"}, value:"jonathan-Blade"}
+					Roles => dbus.Variant{sig:dbus.Signature{str:"as"}, value:[]string{"central", "peripheral"}}
 
 	*/
 
@@ -167,7 +188,10 @@ func (b *Bluez) GetAdapterProperties(adapterPath string) (map[string]dbus.Varian
 func (b *Bluez) SetAdapterProperty(adapterPath, key string, value interface{}) error {
 	path := dbus.ObjectPath(adapterPath)
 
-	return b.conn.Object(dbusBluezName, path).Call("org.freedesktop.DBus.Properties.Set", 0, dbusBluezAdapterIface, key, dbus.MakeVariant(value)).Store()
+	err := b.conn.Object(dbusBluezName, path).Call("org.freedesktop.DBus.Properties.Set", 0, dbusBluezAdapterIface, key, dbus.MakeVariant(value)).Store()
+	b.logOperation(adapterPath, "Set "+key, []interface{}{value}, err)
+
+	return err
 }
 
 // addAdapterToStore adds an adapter to the store.
@@ -192,6 +216,8 @@ func (b *Bluez) removeAdapterFromStore(adapterPath string) {
 	defer b.StoreLock.Unlock()
 
 	delete(b.Store, adapterPath)
+
+	b.removeDiscoveryFilter(adapterPath)
 }
 
 // getAdapterFromStore gets an adapter from the store,
@@ -0,0 +1,161 @@
+package bluez
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Advertisement data identifiers.
+const (
+	appleCompanyID = 0x004C
+	eddystoneUUID  = "0000feaa-0000-1000-8000-00805f9b34fb"
+
+	advertisementLineBytes = 16
+)
+
+// eddystoneURLSchemes and eddystoneURLExpansions are the encodings
+// used by the Eddystone-URL frame.
+// https://github.com/google/eddystone/tree/master/eddystone-url
+var (
+	eddystoneURLSchemes = []string{
+		"http://www.", "https://www.", "http://", "https://",
+	}
+
+	eddystoneURLExpansions = []string{
+		".com/", ".org/", ".edu/", ".net/", ".info/", ".biz/", ".gov/",
+		".com", ".org", ".edu", ".net", ".info", ".biz", ".gov",
+	}
+)
+
+// FormatAdvertisementData returns the hexadecimal representation of
+// advertisement data.
+func FormatAdvertisementData(data []byte) string {
+	return strings.ToUpper(hex.EncodeToString(data))
+}
+
+// FormatAdvertisementLines returns the hexadecimal representation of
+// advertisement data, split into lines of advertisementLineBytes bytes
+// so that large payloads can be displayed compactly.
+func FormatAdvertisementLines(data []byte) []string {
+	var lines []string
+
+	for len(data) > advertisementLineBytes {
+		lines = append(lines, FormatAdvertisementData(data[:advertisementLineBytes]))
+		data = data[advertisementLineBytes:]
+	}
+
+	return append(lines, FormatAdvertisementData(data))
+}
+
+// DecodeManufacturerData decodes the manufacturer data of a device
+// if it is in a recognized format. An empty string is returned otherwise.
+func DecodeManufacturerData(companyID uint16, data []byte) string {
+	if companyID != appleCompanyID || len(data) != 23 || data[0] != 0x02 || data[1] != 0x15 {
+		return ""
+	}
+
+	beaconUUID, err := uuid.FromBytes(data[2:18])
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"iBeacon: UUID %s, Major %d, Minor %d, TX Power %d dBm",
+		beaconUUID.String(),
+		binary.BigEndian.Uint16(data[18:20]),
+		binary.BigEndian.Uint16(data[20:22]),
+		int8(data[22]),
+	)
+}
+
+// DecodeServiceData decodes the service data of a device
+// if it is in a recognized format. An empty string is returned otherwise.
+func DecodeServiceData(serviceUUID string, data []byte) string {
+	if strings.ToLower(serviceUUID) != eddystoneUUID || len(data) < 2 {
+		return ""
+	}
+
+	switch data[0] {
+	case 0x00:
+		if len(data) < 18 {
+			return ""
+		}
+
+		return fmt.Sprintf(
+			"Eddystone-UID: Namespace %s, Instance %s, TX Power %d dBm",
+			FormatAdvertisementData(data[2:12]),
+			FormatAdvertisementData(data[12:18]),
+			int8(data[1]),
+		)
+
+	case 0x10:
+		if len(data) < 3 || int(data[2]) >= len(eddystoneURLSchemes) {
+			return ""
+		}
+
+		url := eddystoneURLSchemes[data[2]]
+		for _, b := range data[3:] {
+			if int(b) < len(eddystoneURLExpansions) {
+				url += eddystoneURLExpansions[b]
+				continue
+			}
+
+			url += string(rune(b))
+		}
+
+		return fmt.Sprintf("Eddystone-URL: %s, TX Power %d dBm", url, int8(data[1]))
+
+	case 0x20:
+		if len(data) < 14 || data[1] != 0x00 {
+			return ""
+		}
+
+		return fmt.Sprintf(
+			"Eddystone-TLM: Battery %d mV, Temperature %.2f°C, Advertisements %d, Uptime %ds",
+			binary.BigEndian.Uint16(data[2:4]),
+			float64(int16(binary.BigEndian.Uint16(data[4:6])))/256,
+			binary.BigEndian.Uint32(data[6:10]),
+			binary.BigEndian.Uint32(data[10:14])/10,
+		)
+	}
+
+	return ""
+}
+
+// EarbudsBattery describes the battery levels of a pair of earbuds
+// and their charging case. An unknown level is set to -1.
+type EarbudsBattery struct {
+	Left, Right, Case int
+}
+
+// DecodeEarbudsBattery decodes the battery levels of a pair of earbuds
+// from the proximity pairing message within the manufacturer data.
+func DecodeEarbudsBattery(manufacturerData map[uint16][]byte) (EarbudsBattery, bool) {
+	data, ok := manufacturerData[appleCompanyID]
+	if !ok || len(data) < 8 || data[0] != 0x07 {
+		return EarbudsBattery{}, false
+	}
+
+	level := func(value byte) int {
+		if value > 10 {
+			return -1
+		}
+
+		return int(value) * 10
+	}
+
+	left, right := data[6]&0x0f, data[6]>>4
+	if data[5]&0x20 == 0 {
+		left, right = right, left
+	}
+
+	return EarbudsBattery{
+		Left:  level(left),
+		Right: level(right),
+		Case:  level(data[7] & 0x0f),
+	}, true
+}
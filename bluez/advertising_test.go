@@ -0,0 +1,60 @@
+package bluez
+
+import "testing"
+
+func TestDecodeEarbudsBattery(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want EarbudsBattery
+		ok   bool
+	}{
+		{
+			name: "not flipped",
+			data: []byte{0x07, 0x19, 0x01, 0x0e, 0x20, 0x2b, 0x98, 0x8f},
+			want: EarbudsBattery{Left: 80, Right: 90, Case: -1},
+			ok:   true,
+		},
+		{
+			name: "flipped",
+			data: []byte{0x07, 0x19, 0x01, 0x0e, 0x20, 0x0b, 0x98, 0x85},
+			want: EarbudsBattery{Left: 90, Right: 80, Case: 50},
+			ok:   true,
+		},
+		{
+			name: "not a proximity pairing message",
+			data: []byte{0x02, 0x15, 0x01, 0x0e, 0x20, 0x2b, 0x98, 0x8f},
+		},
+		{
+			name: "truncated",
+			data: []byte{0x07, 0x19},
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := DecodeEarbudsBattery(map[uint16][]byte{appleCompanyID: test.data})
+		if ok != test.ok || got != test.want {
+			t.Errorf("%s: DecodeEarbudsBattery() = %+v, %v, want %+v, %v",
+				test.name, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestFormatAdvertisementLines(t *testing.T) {
+	data := make([]byte, 20)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	lines := FormatAdvertisementLines(data)
+	if len(lines) != 2 {
+		t.Fatalf("FormatAdvertisementLines() returned %d lines, want 2", len(lines))
+	}
+	if lines[0] != "000102030405060708090A0B0C0D0E0F" || lines[1] != "10111213" {
+		t.Errorf("FormatAdvertisementLines() = %q", lines)
+	}
+
+	if lines := FormatAdvertisementLines([]byte{0xAB}); len(lines) != 1 || lines[0] != "AB" {
+		t.Errorf("FormatAdvertisementLines() = %q, want [\"AB\"]", lines)
+	}
+}
@@ -12,8 +12,18 @@ const (
 	dbusPropertiesGetPath    = "org.freedesktop.DBus.Properties.Get"
 	dbusPropertiesGetAllPath = "org.freedesktop.DBus.Properties.GetAll"
 	dbusObjectManagerPath    = "org.freedesktop.DBus.ObjectManager.GetManagedObjects"
+	dbusNameOwnerChanged     = "org.freedesktop.DBus.NameOwnerChanged"
+
+	// ServiceStateMatch is the match rule for the signal which is sent
+	// when the bluez daemon (bluetoothd) starts or stops.
+	ServiceStateMatch = "type='signal', sender='org.freedesktop.DBus', member='NameOwnerChanged', arg0='org.bluez'"
 )
 
+// ServiceState describes the state of the bluez daemon (bluetoothd).
+type ServiceState struct {
+	Running bool
+}
+
 // StoreObject holds an Adapter and the Devices that belong to it.
 // Each device is stored into Devices with the device adapter path
 // (held by (Device).Adapter) as the identifier.
@@ -37,20 +47,52 @@ type Bluez struct {
 
 	CurrentPlayer dbus.ObjectPath
 	PlayerLock    sync.Mutex
+
+	Transports    map[string]MediaTransport
+	TransportLock sync.Mutex
+
+	ConnectedProfiles map[string]map[string]bool
+	ProfileLock       sync.Mutex
+
+	Filters    map[string]DiscoveryFilter
+	FilterLock sync.Mutex
+
+	queue *ConnectQueue
+
+	operationLogger OperationLogger
 }
 
-// NewBluez returns a new Bluez.
-func NewBluez() (*Bluez, error) {
+// OperationLogger is called after a method is called or a property is set on an
+// adapter or a device, with the object path, the operation, its arguments and its error.
+type OperationLogger func(path, operation string, args []interface{}, err error)
+
+// NewBluez returns a new Bluez. If the system bus address is specified,
+// it is connected to instead of the default system bus.
+func NewBluez(systemBusAddress string) (*Bluez, error) {
 	var b *Bluez
+	var conn *dbus.Conn
+	var err error
 
-	conn, err := dbus.SystemBus()
-	if err != nil {
-		return nil, errors.Wrap(err, "unable to create dbus system bus:")
+	if systemBusAddress == "" {
+		conn, err = dbus.SystemBus()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to create dbus system bus:")
+		}
+	} else {
+		conn, err = dbus.Connect(systemBusAddress)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to connect to the dbus system bus at '%s':", systemBusAddress)
+		}
 	}
 
 	b = &Bluez{
-		conn:  conn,
-		Store: make(map[string]StoreObject),
+		conn:       conn,
+		Store:      make(map[string]StoreObject),
+		Transports: make(map[string]MediaTransport),
+		Filters:    make(map[string]DiscoveryFilter),
+		queue:      newConnectQueue(),
+
+		ConnectedProfiles: make(map[string]map[string]bool),
 	}
 	if err := b.RefreshStore(); err != nil {
 		return nil, errors.Wrapf(err, "unable to populate cache")
@@ -64,6 +106,18 @@ func (b *Bluez) Close() {
 	b.conn.Close()
 }
 
+// SetOperationLogger sets the function to log the operations on adapters and devices.
+func (b *Bluez) SetOperationLogger(logger OperationLogger) {
+	b.operationLogger = logger
+}
+
+// logOperation logs an operation, if an operation logger is set.
+func (b *Bluez) logOperation(path, operation string, args []interface{}, err error) {
+	if b.operationLogger != nil {
+		b.operationLogger(path, operation, args, err)
+	}
+}
+
 // Conn returns the current SystemBus connection.
 func (b *Bluez) Conn() *dbus.Conn {
 	return b.conn
@@ -97,6 +151,14 @@ func (b *Bluez) ConvertAndStoreObjects(objects map[dbus.ObjectPath]map[string]ma
 
 			case dbusBluezDeviceIface:
 				err = b.ConvertToDevice(string(path), values, &devices)
+
+			case dbusBluezMediaTransportIface:
+				var transport MediaTransport
+
+				transport, err = b.ConvertToTransport(string(path), values)
+				if err == nil {
+					b.addTransportToStore(transport)
+				}
 			}
 			if err != nil {
 				return err
@@ -107,7 +169,7 @@ func (b *Bluez) ConvertAndStoreObjects(objects map[dbus.ObjectPath]map[string]ma
 	for _, adapter := range adapters {
 		var store StoreObject
 
-		if adapter == (Adapter{}) {
+		if adapter.Path == "" {
 			continue
 		}
 
@@ -128,6 +190,19 @@ func (b *Bluez) ConvertAndStoreObjects(objects map[dbus.ObjectPath]map[string]ma
 	return nil
 }
 
+// ClearStore removes all the stored adapters, devices and media transports.
+// This is used when the bluez daemon has stopped, since all its objects
+// are removed along with it.
+func (b *Bluez) ClearStore() {
+	b.StoreLock.Lock()
+	b.Store = make(map[string]StoreObject)
+	b.StoreLock.Unlock()
+
+	b.TransportLock.Lock()
+	b.Transports = make(map[string]MediaTransport)
+	b.TransportLock.Unlock()
+}
+
 // ManagedObjects gets all bluetooth devices and adapters that are currently managed by bluez.
 func (b *Bluez) ManagedObjects() (map[dbus.ObjectPath]map[string]map[string]dbus.Variant, error) {
 	result := make(map[dbus.ObjectPath]map[string]map[string]dbus.Variant)
@@ -142,16 +217,45 @@ func (b *Bluez) ManagedObjects() (map[dbus.ObjectPath]map[string]map[string]dbus
 func (b *Bluez) WatchSignal() chan *dbus.Signal {
 	signalMatch := "type='signal', sender='org.bluez'"
 	b.conn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0, signalMatch)
+	b.conn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0, ServiceStateMatch)
 	ch := make(chan *dbus.Signal, 1)
 	b.conn.Signal(ch)
 	return ch
 }
 
+// ParseServiceState parses the signal which is sent when the bluez daemon
+// starts or stops, and returns the state of the daemon.
+func ParseServiceState(signal *dbus.Signal) (ServiceState, bool) {
+	if signal.Name != dbusNameOwnerChanged || len(signal.Body) != 3 {
+		return ServiceState{}, false
+	}
+
+	name, ok := signal.Body[0].(string)
+	if !ok || name != dbusBluezName {
+		return ServiceState{}, false
+	}
+
+	newOwner, ok := signal.Body[2].(string)
+	if !ok {
+		return ServiceState{}, false
+	}
+
+	return ServiceState{Running: newOwner != ""}, true
+}
+
 // ParseSignalData parses bluez DBus signal data.
 //
 //gocyclo:ignore
 func (b *Bluez) ParseSignalData(signal *dbus.Signal) interface{} {
 	switch signal.Name {
+	case dbusNameOwnerChanged:
+		state, ok := ParseServiceState(signal)
+		if !ok {
+			return nil
+		}
+
+		return state
+
 	case "org.freedesktop.DBus.Properties.PropertiesChanged":
 		objInterface, ok := signal.Body[0].(string)
 		if !ok {
@@ -166,7 +270,7 @@ func (b *Bluez) ParseSignalData(signal *dbus.Signal) interface{} {
 		switch objInterface {
 		case dbusBluezAdapterIface:
 			adapter := b.getAdapterFromStore(string(signal.Path))
-			if adapter == (Adapter{}) {
+			if adapter.Path == "" {
 				return nil
 			}
 
@@ -184,10 +288,23 @@ func (b *Bluez) ParseSignalData(signal *dbus.Signal) interface{} {
 				return nil
 			}
 
+			// The advertisement data maps are shared with the stored device,
+			// so they are replaced instead of being decoded into.
+			if _, ok := objMap["ManufacturerData"]; ok {
+				device.ManufacturerData = nil
+			}
+			if _, ok := objMap["ServiceData"]; ok {
+				device.ServiceData = nil
+			}
+
 			if err := DecodeVariantMap(objMap, &device); err != nil {
 				return nil
 			}
 
+			if !device.Connected {
+				b.clearConnectedProfiles(device.Path)
+			}
+
 			b.addDeviceToStore(device)
 
 			return device
@@ -197,6 +314,39 @@ func (b *Bluez) ParseSignalData(signal *dbus.Signal) interface{} {
 
 			return media
 
+		case dbusBluezMediaControlIface:
+			control, err := b.GetMediaControl(string(signal.Path))
+			if err != nil {
+				return nil
+			}
+
+			return control
+
+		case dbusBluezMediaTransportIface:
+			transport := b.getTransportFromStore(string(signal.Path))
+			if transport.Path == "" {
+				return nil
+			}
+
+			if err := DecodeVariantMap(objMap, &transport); err != nil {
+				return nil
+			}
+			if _, ok := objMap["Volume"]; ok {
+				transport.VolumeSupported = true
+			}
+
+			b.addTransportToStore(transport)
+
+			return transport
+
+		case dbusBluezGattCharacteristicIface:
+			change, err := convertToCharacteristicChange(string(signal.Path), objMap)
+			if err != nil {
+				return nil
+			}
+
+			return change
+
 		case dbusBluezBatteryIface:
 			device := b.getDeviceFromStore(string(signal.Path))
 			if device.Path == "" {
@@ -205,7 +355,7 @@ func (b *Bluez) ParseSignalData(signal *dbus.Signal) interface{} {
 
 			if v, ok := objMap["Percentage"]; ok {
 				if p, ok := v.Value().(byte); ok {
-					device.Percentage = int(p)
+					device.BatteryPercentage = int(p)
 					b.addDeviceToStore(device)
 				}
 			}
@@ -274,12 +424,22 @@ func (b *Bluez) ParseSignalData(signal *dbus.Signal) interface{} {
 
 				if v, ok := objMap[iftype]["Percentage"]; ok {
 					if p, ok := v.Value().(byte); ok {
-						device.Percentage = int(p)
+						device.BatteryPercentage = int(p)
 						b.addDeviceToStore(device)
 					}
 				}
 
 				return map[string][]Device{devicePath: {device}}
+
+			case dbusBluezMediaTransportIface:
+				transport, err := b.ConvertToTransport(string(objPath), objMap[iftype])
+				if err != nil {
+					return nil
+				}
+
+				b.addTransportToStore(transport)
+
+				return transport
 			}
 		}
 
@@ -316,10 +476,20 @@ func (b *Bluez) ParseSignalData(signal *dbus.Signal) interface{} {
 					return nil
 				}
 
-				device.Percentage = 0
+				device.BatteryPercentage = -1
 				b.addDeviceToStore(device)
 
-				return nil
+				return device
+
+			case dbusBluezMediaTransportIface:
+				transport := b.removeTransportFromStore(string(objPath))
+				if transport.Path == "" {
+					return nil
+				}
+
+				transport.State = "removed"
+
+				return transport
 			}
 		}
 	}
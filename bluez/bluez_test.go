@@ -0,0 +1,27 @@
+package bluez
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestParseServiceState(t *testing.T) {
+	tests := []struct {
+		signal  *dbus.Signal
+		running bool
+		ok      bool
+	}{
+		{&dbus.Signal{Name: dbusNameOwnerChanged, Body: []interface{}{"org.bluez", "", ":1.42"}}, true, true},
+		{&dbus.Signal{Name: dbusNameOwnerChanged, Body: []interface{}{"org.bluez", ":1.42", ""}}, false, true},
+		{&dbus.Signal{Name: dbusNameOwnerChanged, Body: []interface{}{"org.bluez.obex", "", ":1.43"}}, false, false},
+		{&dbus.Signal{Name: "org.freedesktop.DBus.Properties.PropertiesChanged", Body: []interface{}{"org.bluez", "", ":1.42"}}, false, false},
+	}
+
+	for i, test := range tests {
+		state, ok := ParseServiceState(test.signal)
+		if ok != test.ok || state.Running != test.running {
+			t.Errorf("test %d: ParseServiceState() = (%v, %v), want (%v, %v)", i, state.Running, ok, test.running, test.ok)
+		}
+	}
+}
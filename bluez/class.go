@@ -0,0 +1,76 @@
+package bluez
+
+import "fmt"
+
+// ClassFilter describes a filter which matches devices of a major device class.
+// The service class IDs are used to filter the devices reported by bluez during
+// discovery, and to match devices which do not advertise a device class.
+type ClassFilter struct {
+	Name       string
+	MajorClass uint32
+	Services   []uint32
+}
+
+// ClassFilters holds the available device class filters.
+var ClassFilters = []ClassFilter{
+	{
+		Name:       "audio",
+		MajorClass: 0x04,
+		Services: []uint32{
+			AUDIO_SINK_SVCLASS_ID, AUDIO_SOURCE_SVCLASS_ID,
+			HEADSET_SVCLASS_ID, HANDSFREE_SVCLASS_ID,
+		},
+	},
+	{
+		Name:       "peripheral",
+		MajorClass: 0x05,
+		Services:   []uint32{HID_SVCLASS_ID, 0x1812 /* HID over GATT */},
+	},
+	{
+		Name:       "phone",
+		MajorClass: 0x02,
+		Services:   []uint32{HANDSFREE_AGW_SVCLASS_ID, PBAP_PSE_SVCLASS_ID, MAP_MSE_SVCLASS_ID},
+	},
+	{
+		Name:       "computer",
+		MajorClass: 0x01,
+	},
+}
+
+// GetClassFilter returns the device class filter with the provided name.
+func GetClassFilter(name string) (ClassFilter, bool) {
+	for _, filter := range ClassFilters {
+		if filter.Name == name {
+			return filter, true
+		}
+	}
+
+	return ClassFilter{}, false
+}
+
+// UUIDs returns the service UUIDs of the filter, to be used in a discovery filter.
+func (c ClassFilter) UUIDs() []string {
+	uuids := make([]string, 0, len(c.Services))
+	for _, service := range c.Services {
+		uuids = append(uuids, fmt.Sprintf("%08x-0000-1000-8000-00805f9b34fb", service))
+	}
+
+	return uuids
+}
+
+// Matches returns if the device belongs to the major device class of the filter,
+// or if the device does not have a device class and provides one of the services
+// of the filter.
+func (c ClassFilter) Matches(device Device) bool {
+	if device.Class != 0 {
+		return (device.Class&0x1f00)>>8 == c.MajorClass
+	}
+
+	for _, service := range c.Services {
+		if device.HaveService(service) {
+			return true
+		}
+	}
+
+	return false
+}
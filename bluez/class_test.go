@@ -0,0 +1,30 @@
+package bluez
+
+import "testing"
+
+func TestClassFilterMatches(t *testing.T) {
+	audio, ok := GetClassFilter("audio")
+	if !ok {
+		t.Fatal("GetClassFilter(\"audio\") not found")
+	}
+
+	tests := []struct {
+		device  Device
+		matches bool
+	}{
+		{Device{Class: 0x240418}, true},
+		{Device{Class: 0x5a020c}, false},
+		{Device{UUIDs: []string{"0000110b-0000-1000-8000-00805f9b34fb"}}, true},
+		{Device{UUIDs: []string{"00001124-0000-1000-8000-00805f9b34fb"}}, false},
+	}
+
+	for i, test := range tests {
+		if matches := audio.Matches(test.device); matches != test.matches {
+			t.Errorf("test %d: Matches() = %v, want %v", i, matches, test.matches)
+		}
+	}
+
+	if uuids := audio.UUIDs(); uuids[0] != "0000110b-0000-1000-8000-00805f9b34fb" {
+		t.Errorf("UUIDs()[0] = %s, want 0000110b-0000-1000-8000-00805f9b34fb", uuids[0])
+	}
+}
@@ -0,0 +1,90 @@
+package bluez
+
+import (
+	"encoding/binary"
+
+	"github.com/google/uuid"
+)
+
+// The A2DP codec identifiers, from the A2DP specification.
+const (
+	a2dpCodecSBC    = 0x00
+	a2dpCodecMPEG12 = 0x01
+	a2dpCodecMPEG24 = 0x02
+	a2dpCodecATRAC  = 0x04
+	a2dpCodecVendor = 0xFF
+)
+
+// bapCodecLC3 is the codec identifier of LC3, which is used by LE Audio transports.
+const bapCodecLC3 = 0x06
+
+// a2dpCodecs matches the A2DP codec identifiers with their names.
+var a2dpCodecs = map[byte]string{
+	a2dpCodecSBC:    "SBC",
+	a2dpCodecMPEG12: "MP3",
+	a2dpCodecMPEG24: "AAC",
+	a2dpCodecATRAC:  "ATRAC",
+}
+
+// vendorCodec describes a vendor-specific A2DP codec, which is identified
+// by the vendor (company) identifier and the vendor's codec identifier.
+type vendorCodec struct {
+	Vendor uint32
+	Codec  uint16
+}
+
+// a2dpVendorCodecs matches the vendor-specific A2DP codecs with their names.
+var a2dpVendorCodecs = map[vendorCodec]string{
+	{0x0000004F, 0x0001}: "aptX",
+	{0x000000D7, 0x0024}: "aptX HD",
+	{0x0000000A, 0x0002}: "aptX Low Latency",
+	{0x0000000A, 0x0001}: "FastStream",
+	{0x0000012D, 0x00AA}: "LDAC",
+	{0x000008A9, 0x0001}: "LC3plus",
+	{0x000005F1, 0x1005}: "Opus",
+}
+
+// CodecName returns the name of the audio codec which is in use by the media
+// transport. If the codec cannot be determined, "unknown" is returned.
+func (t MediaTransport) CodecName() string {
+	serviceUUID, err := uuid.Parse(t.UUID)
+	if err != nil {
+		return "unknown"
+	}
+
+	var name string
+
+	switch serviceUUID.ID() {
+	case AUDIO_SOURCE_SVCLASS_ID, AUDIO_SINK_SVCLASS_ID:
+		if t.Codec == a2dpCodecVendor {
+			name = t.vendorCodecName()
+		} else {
+			name = a2dpCodecs[t.Codec]
+		}
+
+	default:
+		if t.Codec == bapCodecLC3 {
+			name = "LC3"
+		}
+	}
+
+	if name == "" {
+		return "unknown"
+	}
+
+	return name
+}
+
+// vendorCodecName returns the name of the vendor-specific A2DP codec of the media transport.
+// The configuration of a vendor codec starts with the vendor identifier and the
+// codec identifier, in little-endian order.
+func (t MediaTransport) vendorCodecName() string {
+	if len(t.Configuration) < 6 {
+		return ""
+	}
+
+	return a2dpVendorCodecs[vendorCodec{
+		Vendor: binary.LittleEndian.Uint32(t.Configuration[0:4]),
+		Codec:  binary.LittleEndian.Uint16(t.Configuration[4:6]),
+	}]
+}
@@ -16,7 +16,11 @@
 package bluez
 
 import (
+	"bytes"
+	"context"
+	"net"
 	"path/filepath"
+	"reflect"
 
 	"github.com/godbus/dbus/v5"
 )
@@ -36,6 +40,7 @@ type Device struct {
 	AddressType   string
 	Adapter       string
 	Modalias      string
+	Icon          string
 	UUIDs         []string
 	Paired        bool
 	Connected     bool
@@ -45,7 +50,14 @@ type Device struct {
 	LegacyPairing bool
 	RSSI          int16
 	Class         uint32
-	Percentage    int
+	Appearance    uint16
+
+	// BatteryPercentage is the battery level of the device, or -1
+	// if the device does not provide the Battery1 interface.
+	BatteryPercentage int
+
+	ManufacturerData map[uint16][]byte
+	ServiceData      map[string][]byte
 }
 
 // HaveService checks if the device has the specified service.
@@ -57,7 +69,34 @@ func (d Device) HaveService(service uint32) bool {
 // https://git.kernel.org/pub/scm/bluetooth/bluez.git/tree/doc/device-api.txt
 func (b *Bluez) CallDevice(devicePath, method string, flags dbus.Flags, args ...interface{}) *dbus.Call {
 	path := dbus.ObjectPath(devicePath)
-	return b.conn.Object(dbusBluezName, path).Call("org.bluez.Device1."+method, flags, args...)
+
+	call := b.conn.Object(dbusBluezName, path).Call("org.bluez.Device1."+method, flags, args...)
+	b.logOperation(devicePath, method, args, call.Err)
+
+	return call
+}
+
+// callDeviceContext calls the method on the bluez Device dbus interface, and
+// waits for the call to complete. If the context is cancelled before the call
+// completes, the device is disconnected to cancel the connection in progress,
+// unless the device was connected in the meantime.
+func (b *Bluez) callDeviceContext(ctx context.Context, devicePath, method string, args ...interface{}) error {
+	path := dbus.ObjectPath(devicePath)
+	call := b.conn.Object(dbusBluezName, path).Go("org.bluez.Device1."+method, 0, make(chan *dbus.Call, 1), args...)
+
+	select {
+	case <-call.Done:
+		b.logOperation(devicePath, method, args, call.Err)
+		return call.Err
+
+	case <-ctx.Done():
+		if !b.GetDevice(devicePath).Connected {
+			b.Disconnect(devicePath)
+		}
+
+		b.logOperation(devicePath, method, args, ctx.Err())
+		return ctx.Err()
+	}
 }
 
 // Pair will attempt to pair a bluetooth device that is in pairing mode.
@@ -71,12 +110,64 @@ func (b *Bluez) CancelPairing(devicePath string) error {
 }
 
 // Connect will attempt to connect an already paired bluetooth device
-// to an adapter.
+// to an adapter. The connection is queued if other devices are being
+// connected.
 func (b *Bluez) Connect(devicePath string) error {
-	return b.CallDevice(devicePath, "Connect", 0).Store()
+	return b.ConnectContext(context.Background(), devicePath)
+}
+
+// ConnectContext is like Connect, but if the context is cancelled while the
+// connection is queued, the connection is not started, and if it is cancelled
+// while the device is connecting, the pending connection is cancelled.
+func (b *Bluez) ConnectContext(ctx context.Context, devicePath string) error {
+	return b.runQueued(ctx, devicePath, "connect", func() error {
+		return b.callDeviceContext(ctx, devicePath, "Connect")
+	})
+}
+
+// ConnectProfile will attempt to connect the specified profile
+// of an already paired bluetooth device.
+// The profile must be one of the profiles advertised by the device.
+func (b *Bluez) ConnectProfile(devicePath, profileUUID string) error {
+	return b.ConnectProfileContext(context.Background(), devicePath, profileUUID)
+}
+
+// ConnectProfileContext is like ConnectProfile, but the connection
+// is cancelled in the same way as ConnectContext.
+func (b *Bluez) ConnectProfileContext(ctx context.Context, devicePath, profileUUID string) error {
+	if err := b.checkDeviceProfile(devicePath, profileUUID); err != nil {
+		return err
+	}
+
+	err := b.runQueued(ctx, devicePath, "connect", func() error {
+		return b.callDeviceContext(ctx, devicePath, "ConnectProfile", profileUUID)
+	})
+	if err == nil {
+		b.setProfileConnected(devicePath, profileUUID, true)
+	}
+
+	return err
+}
+
+// DisconnectProfile will attempt to disconnect the specified profile
+// of a connected bluetooth device. The profile must be one of the
+// profiles advertised by the device.
+func (b *Bluez) DisconnectProfile(devicePath, profileUUID string) error {
+	if err := b.checkDeviceProfile(devicePath, profileUUID); err != nil {
+		return err
+	}
+
+	err := b.CallDevice(devicePath, "DisconnectProfile", 0, profileUUID).Store()
+	if err == nil {
+		b.setProfileConnected(devicePath, profileUUID, false)
+	}
+
+	return err
 }
 
 // Disconnect will remove the bluetooth device from the adapter.
+// The disconnection is not queued, so that a pending connection
+// to the device can be cancelled by disconnecting it.
 func (b *Bluez) Disconnect(devicePath string) error {
 	return b.CallDevice(devicePath, "Disconnect", 0).Store()
 }
@@ -94,6 +185,22 @@ func (b *Bluez) GetDevice(devicePath string) Device {
 	return b.getDeviceFromStore(devicePath)
 }
 
+// GetAllDevices gets the stored devices of all adapters.
+func (b *Bluez) GetAllDevices() []Device {
+	b.StoreLock.Lock()
+	defer b.StoreLock.Unlock()
+
+	var devices []Device
+
+	for _, store := range b.Store {
+		for _, device := range store.Devices {
+			devices = append(devices, device)
+		}
+	}
+
+	return devices
+}
+
 // GetDevices gets the stored devices.
 func (b *Bluez) GetDevices() []Device {
 	b.StoreLock.Lock()
@@ -118,6 +225,49 @@ func (b *Bluez) GetDevices() []Device {
 	return devices
 }
 
+// GetDevicePartner returns the device that forms a pair of earbuds
+// with the provided device. Two devices are considered a pair if they
+// have the same name and class, and their addresses differ only by
+// one in the last octet.
+func (b *Bluez) GetDevicePartner(device Device) (Device, bool) {
+	b.StoreLock.Lock()
+	defer b.StoreLock.Unlock()
+
+	if device.Name == "" || (device.Class&0x1f00)>>8 != 0x04 {
+		return Device{}, false
+	}
+
+	store, ok := b.Store[device.Adapter]
+	if !ok {
+		return Device{}, false
+	}
+
+	address, err := net.ParseMAC(device.Address)
+	if err != nil || len(address) != 6 {
+		return Device{}, false
+	}
+
+	for _, partner := range store.Devices {
+		if partner.Path == device.Path ||
+			partner.Name != device.Name ||
+			partner.Class != device.Class {
+			continue
+		}
+
+		partnerAddress, err := net.ParseMAC(partner.Address)
+		if err != nil || len(partnerAddress) != 6 ||
+			!bytes.Equal(address[:5], partnerAddress[:5]) {
+			continue
+		}
+
+		if diff := int(address[5]) - int(partnerAddress[5]); diff == 1 || diff == -1 {
+			return partner, true
+		}
+	}
+
+	return Device{}, false
+}
+
 // ConvertToDevices converts a map of dbus objects to a common Device structure.
 func (b *Bluez) ConvertToDevice(path string, values map[string]dbus.Variant, devices *[]Device) error {
 	/*
@@ -146,8 +296,9 @@ func (b *Bluez) ConvertToDevice(path string, values map[string]dbus.Variant, dev
 
 	device.Path = path
 	device.Type = GetDeviceType(device.Class)
+	device.BatteryPercentage = -1
 	if p, err := b.GetBatteryPercentage(path); err == nil {
-		device.Percentage = int(p)
+		device.BatteryPercentage = int(p)
 	}
 
 	if devices != nil {
@@ -270,10 +421,101 @@ func (b *Bluez) GetBatteryPercentage(devicePath string) (byte, error) {
 	return result, nil
 }
 
+// SetAlias sets the alias of a device. If the alias is empty,
+// the alias is reset to the name of the device.
+func (b *Bluez) SetAlias(devicePath, alias string) error {
+	return b.SetDeviceProperty(devicePath, "Alias", alias)
+}
+
+// SetTrusted sets the trusted state of a device. Connections
+// from a trusted device are accepted without authorization.
+func (b *Bluez) SetTrusted(devicePath string, trusted bool) error {
+	return b.SetDeviceProperty(devicePath, "Trusted", trusted)
+}
+
+// BlockDevice blocks a device. Incoming connections from a blocked
+// device are rejected, and it cannot be connected to.
+func (b *Bluez) BlockDevice(devicePath string) error {
+	return b.SetDeviceProperty(devicePath, "Blocked", true)
+}
+
+// UnblockDevice unblocks a device.
+func (b *Bluez) UnblockDevice(devicePath string) error {
+	return b.SetDeviceProperty(devicePath, "Blocked", false)
+}
+
 // SetDeviceProperty can be used to set certain properties for a bluetooth device.
 func (b *Bluez) SetDeviceProperty(devicePath, key string, value interface{}) error {
 	path := dbus.ObjectPath(devicePath)
-	return b.conn.Object(dbusBluezName, path).Call("org.freedesktop.DBus.Properties.Set", 0, dbusBluezDeviceIface, key, dbus.MakeVariant(value)).Store()
+
+	err := b.conn.Object(dbusBluezName, path).Call("org.freedesktop.DBus.Properties.Set", 0, dbusBluezDeviceIface, key, dbus.MakeVariant(value)).Store()
+	b.logOperation(devicePath, "Set "+key, []interface{}{value}, err)
+
+	return err
+}
+
+// ReconcileDevices compares the stored devices of the adapter with the devices
+// which are currently managed by bluez. Devices which no longer exist are removed
+// from the store, and devices whose properties have changed are updated in the store.
+// The changed devices and the paths of the removed devices are returned.
+func (b *Bluez) ReconcileDevices(adapterPath string) ([]Device, []string, error) {
+	var devices, changed []Device
+	var removed []string
+
+	objects, err := b.ManagedObjects()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for path, object := range objects {
+		values, ok := object[dbusBluezDeviceIface]
+		if !ok {
+			continue
+		}
+
+		if err := b.ConvertToDevice(string(path), values, &devices); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	b.StoreLock.Lock()
+	defer b.StoreLock.Unlock()
+
+	store, ok := b.Store[adapterPath]
+	if !ok {
+		return nil, nil, nil
+	}
+
+	if store.Devices == nil {
+		store.Devices = make(map[string]Device)
+	}
+
+	current := make(map[string]struct{}, len(devices))
+	for _, device := range devices {
+		if device.Adapter != adapterPath {
+			continue
+		}
+
+		current[device.Path] = struct{}{}
+
+		if stored, ok := store.Devices[device.Path]; ok && reflect.DeepEqual(stored, device) {
+			continue
+		}
+
+		store.Devices[device.Path] = device
+		changed = append(changed, device)
+	}
+
+	for devicePath := range store.Devices {
+		if _, ok := current[devicePath]; !ok {
+			delete(store.Devices, devicePath)
+			removed = append(removed, devicePath)
+		}
+	}
+
+	b.Store[adapterPath] = store
+
+	return changed, removed, nil
 }
 
 // addDeviceToStore adds a device to the store.
@@ -0,0 +1,240 @@
+package bluez
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Modalias describes the parsed modalias of a device, which
+// identifies the vendor and the product of the device.
+type Modalias struct {
+	Source  string
+	Vendor  uint16
+	Product uint16
+	Version uint16
+}
+
+// bluetoothCompanies matches some of the Bluetooth SIG company identifiers
+// with their names. The identifiers are used by the "bluetooth" modalias
+// source and by the manufacturer data of the devices.
+var bluetoothCompanies = map[uint16]string{
+	0x0001: "Nokia",
+	0x0002: "Intel",
+	0x0003: "IBM",
+	0x0006: "Microsoft",
+	0x0008: "Motorola",
+	0x000A: "Qualcomm Technologies International",
+	0x000D: "Texas Instruments",
+	0x000F: "Broadcom",
+	0x001D: "Qualcomm",
+	0x0046: "MediaTek",
+	0x004C: "Apple",
+	0x0057: "Harman International",
+	0x0059: "Nordic Semiconductor",
+	0x0075: "Samsung Electronics",
+	0x0087: "Garmin",
+	0x009E: "Bose",
+	0x00D7: "Qualcomm Technologies",
+	0x00E0: "Google",
+	0x012D: "Sony",
+	0x02E5: "Espressif",
+}
+
+// usbVendors matches some of the USB vendor identifiers with their names.
+// The identifiers are used by the "usb" modalias source.
+var usbVendors = map[uint16]string{
+	0x045E: "Microsoft",
+	0x046D: "Logitech",
+	0x04E8: "Samsung Electronics",
+	0x054C: "Sony",
+	0x057E: "Nintendo",
+	0x05AC: "Apple",
+	0x18D1: "Google",
+}
+
+// appearanceCategories matches the appearance categories with their names.
+var appearanceCategories = map[uint16]string{
+	0x001: "Phone",
+	0x002: "Computer",
+	0x003: "Watch",
+	0x004: "Clock",
+	0x005: "Display",
+	0x006: "Remote Control",
+	0x007: "Eye-glasses",
+	0x008: "Tag",
+	0x009: "Keyring",
+	0x00A: "Media Player",
+	0x00B: "Barcode Scanner",
+	0x00C: "Thermometer",
+	0x00D: "Heart Rate Sensor",
+	0x00E: "Blood Pressure",
+	0x00F: "Human Interface Device",
+	0x010: "Glucose Meter",
+	0x011: "Running Walking Sensor",
+	0x012: "Cycling",
+	0x013: "Control Device",
+	0x014: "Network Device",
+	0x015: "Sensor",
+	0x016: "Light Fixtures",
+	0x017: "Fan",
+	0x018: "HVAC",
+	0x019: "Air Conditioning",
+	0x01A: "Humidifier",
+	0x01B: "Heating",
+	0x01C: "Access Control",
+	0x01D: "Motorized Device",
+	0x01E: "Power Device",
+	0x01F: "Light Source",
+	0x020: "Window Covering",
+	0x021: "Audio Sink",
+	0x022: "Audio Source",
+	0x023: "Motorized Vehicle",
+	0x024: "Domestic Appliance",
+	0x025: "Wearable Audio Device",
+	0x026: "Aircraft",
+	0x027: "AV Equipment",
+	0x028: "Display Equipment",
+	0x029: "Hearing Aid",
+	0x02A: "Gaming",
+	0x02B: "Signage",
+	0x031: "Pulse Oximeter",
+	0x032: "Weight Scale",
+	0x033: "Personal Mobility Device",
+	0x034: "Continuous Glucose Monitor",
+	0x035: "Insulin Pump",
+	0x036: "Medication Delivery",
+	0x037: "Spirometer",
+	0x051: "Outdoor Sports Activity",
+}
+
+// ParseModalias parses a modalias of the form "<source>:v<vendor>p<product>d<version>",
+// for example "bluetooth:v004Cp200Ed0100".
+func ParseModalias(modalias string) (Modalias, bool) {
+	var parsed Modalias
+
+	source, ids, ok := strings.Cut(modalias, ":")
+	if !ok || source == "" {
+		return Modalias{}, false
+	}
+	parsed.Source = source
+
+	for _, id := range []struct {
+		prefix string
+		value  *uint16
+	}{
+		{"v", &parsed.Vendor},
+		{"p", &parsed.Product},
+		{"d", &parsed.Version},
+	} {
+		if len(ids) < 5 || !strings.EqualFold(ids[:1], id.prefix) {
+			return Modalias{}, false
+		}
+
+		value, err := strconv.ParseUint(ids[1:5], 16, 16)
+		if err != nil {
+			return Modalias{}, false
+		}
+
+		*id.value = uint16(value)
+		ids = ids[5:]
+	}
+
+	return parsed, true
+}
+
+// String returns the vendor, product and version identifiers of the modalias.
+func (m Modalias) String() string {
+	return fmt.Sprintf("Vendor 0x%04X, Product 0x%04X, Version 0x%04X", m.Vendor, m.Product, m.Version)
+}
+
+// Manufacturer returns the manufacturer of the device, from the vendor of its modalias
+// or from the company identifier of its manufacturer data. If the manufacturer is
+// not known, its identifier is returned, and if it cannot be determined, false is returned.
+func (d Device) Manufacturer() (string, bool) {
+	if modalias, ok := ParseModalias(d.Modalias); ok {
+		var vendors map[uint16]string
+
+		switch modalias.Source {
+		case "bluetooth":
+			vendors = bluetoothCompanies
+
+		case "usb":
+			vendors = usbVendors
+
+		default:
+			return fmt.Sprintf("0x%04X", modalias.Vendor), true
+		}
+
+		if name, ok := vendors[modalias.Vendor]; ok {
+			return name, true
+		}
+
+		return fmt.Sprintf("0x%04X", modalias.Vendor), true
+	}
+
+	var companyID uint16
+	var found bool
+
+	for id := range d.ManufacturerData {
+		if !found || id < companyID {
+			companyID, found = id, true
+		}
+	}
+	if !found {
+		return "", false
+	}
+
+	if name, ok := CompanyName(companyID); ok {
+		return name, true
+	}
+
+	return fmt.Sprintf("0x%04X", companyID), true
+}
+
+// CompanyName returns the name of the company with the Bluetooth SIG
+// assigned company identifier, if it is known.
+func CompanyName(companyID uint16) (string, bool) {
+	name, ok := bluetoothCompanies[companyID]
+
+	return name, ok
+}
+
+// AppearanceName returns the category of the appearance of the device, along with
+// the appearance value. If the device does not report its appearance, false is returned.
+func (d Device) AppearanceName() (string, bool) {
+	if d.Appearance == 0 {
+		return "", false
+	}
+
+	value := fmt.Sprintf("0x%04X", d.Appearance)
+
+	if category, ok := appearanceCategories[d.Appearance>>6]; ok {
+		return category + " (" + value + ")", true
+	}
+
+	return value, true
+}
+
+// Transport returns the transport which the device is known to use: "le" for
+// Low Energy, "bredr" for BR/EDR (classic), or "dual" for both. Since BlueZ
+// does not expose the transport directly, it is determined from the class,
+// address type and appearance of the device. If it cannot be determined,
+// an empty string is returned.
+func (d Device) Transport() string {
+	bredr := d.Class != 0
+	le := d.AddressType == "random" || d.Appearance != 0 || len(d.ServiceData) > 0
+
+	switch {
+	case bredr && le:
+		return "dual"
+
+	case bredr:
+		return "bredr"
+
+	case le:
+		return "le"
+	}
+
+	return ""
+}
@@ -0,0 +1,75 @@
+package bluez
+
+import "testing"
+
+func TestParseModalias(t *testing.T) {
+	modalias, ok := ParseModalias("bluetooth:v004Cp200Ed0100")
+	if !ok {
+		t.Fatal("ParseModalias() could not parse a valid modalias")
+	}
+
+	want := Modalias{Source: "bluetooth", Vendor: 0x004C, Product: 0x200E, Version: 0x0100}
+	if modalias != want {
+		t.Errorf("ParseModalias() = %+v, want %+v", modalias, want)
+	}
+
+	for _, invalid := range []string{"", "bluetooth", "usb:v05AC", "usb:x05ACp0265d0100", "usb:v05ACpZZZZd0100"} {
+		if _, ok := ParseModalias(invalid); ok {
+			t.Errorf("ParseModalias(%q) parsed an invalid modalias", invalid)
+		}
+	}
+}
+
+func TestDeviceManufacturer(t *testing.T) {
+	for _, test := range []struct {
+		device Device
+		want   string
+		ok     bool
+	}{
+		{Device{Modalias: "bluetooth:v004Cp200Ed0100"}, "Apple", true},
+		{Device{Modalias: "usb:v046DpB01Ed0001"}, "Logitech", true},
+		{Device{Modalias: "bluetooth:vFFF0p0001d0001"}, "0xFFF0", true},
+		{Device{ManufacturerData: map[uint16][]byte{0x0075: nil, 0x00E0: nil}}, "Samsung Electronics", true},
+		{Device{}, "", false},
+	} {
+		manufacturer, ok := test.device.Manufacturer()
+		if manufacturer != test.want || ok != test.ok {
+			t.Errorf("Manufacturer() = %q, %v, want %q, %v", manufacturer, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestDeviceAppearanceName(t *testing.T) {
+	for _, test := range []struct {
+		appearance uint16
+		want       string
+		ok         bool
+	}{
+		{0x0941, "Wearable Audio Device (0x0941)", true},
+		{0x03C1, "Human Interface Device (0x03C1)", true},
+		{0xFFC0, "0xFFC0", true},
+		{0, "", false},
+	} {
+		name, ok := Device{Appearance: test.appearance}.AppearanceName()
+		if name != test.want || ok != test.ok {
+			t.Errorf("AppearanceName() for 0x%04X = %q, %v, want %q, %v", test.appearance, name, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestDeviceTransport(t *testing.T) {
+	for _, test := range []struct {
+		device Device
+		want   string
+	}{
+		{Device{Class: 0x240404}, "bredr"},
+		{Device{AddressType: "random"}, "le"},
+		{Device{AddressType: "public", Appearance: 0x0941}, "le"},
+		{Device{Class: 0x240404, Appearance: 0x0941}, "dual"},
+		{Device{AddressType: "public"}, ""},
+	} {
+		if transport := test.device.Transport(); transport != test.want {
+			t.Errorf("Transport() for %+v = %q, want %q", test.device, transport, test.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package bluez
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// DeviceProfile describes a profile of a device, which can
+// be connected to or disconnected from separately.
+type DeviceProfile struct {
+	UUID      string
+	Name      string
+	Connected bool
+}
+
+// deviceProfileGroups groups the roles of each audio profile, so that
+// the profiles of the device can be matched with the local roles.
+var deviceProfileGroups = map[uint32]string{
+	AUDIO_SOURCE_SVCLASS_ID:         "A2DP",
+	AUDIO_SINK_SVCLASS_ID:           "A2DP",
+	HEADSET_SVCLASS_ID:              "HSP",
+	HEADSET_AGW_SVCLASS_ID:          "HSP",
+	HANDSFREE_SVCLASS_ID:            "HFP",
+	HANDSFREE_AGW_SVCLASS_ID:        "HFP",
+	AV_REMOTE_SVCLASS_ID:            "AVRCP",
+	AV_REMOTE_TARGET_SVCLASS_ID:     "AVRCP",
+	AV_REMOTE_CONTROLLER_SVCLASS_ID: "AVRCP",
+}
+
+// GetDeviceProfiles returns the profiles which are advertised by the device.
+// An audio profile is marked as connected if the device has a media transport
+// of the profile, or in the case of AVRCP, if the media control of the device
+// is connected. Other profiles are marked as connected if they were connected
+// using ConnectProfile, until they are disconnected or the device disconnects.
+func (b *Bluez) GetDeviceProfiles(device Device) []DeviceProfile {
+	var controlConnected bool
+
+	if control, err := b.GetMediaControl(device.Path); err == nil {
+		controlConnected = control.Connected
+	}
+
+	return deviceProfiles(
+		device.UUIDs, b.GetTransports(device.Path),
+		b.getConnectedProfiles(device.Path), controlConnected,
+	)
+}
+
+// deviceProfiles returns the profiles from the service UUIDs of a device,
+// sorted by their names.
+func deviceProfiles(
+	uuids []string, transports []MediaTransport,
+	connectedProfiles map[string]bool, controlConnected bool,
+) []DeviceProfile {
+	connectedGroups := make(map[string]bool)
+	if controlConnected {
+		connectedGroups["AVRCP"] = true
+	}
+
+	for _, transport := range transports {
+		if group, ok := profileGroup(transport.UUID); ok {
+			connectedGroups[group] = true
+		}
+	}
+
+	var profiles []DeviceProfile
+
+	for _, serviceUUID := range uuids {
+		if _, err := uuid.Parse(serviceUUID); err != nil {
+			continue
+		}
+
+		profile := DeviceProfile{
+			UUID: serviceUUID,
+			Name: ServiceName(serviceUUID),
+		}
+
+		if group, ok := profileGroup(serviceUUID); ok {
+			profile.Name += " (" + group + ")"
+			profile.Connected = connectedGroups[group]
+		} else {
+			profile.Connected = connectedProfiles[strings.ToLower(serviceUUID)]
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+
+	return profiles
+}
+
+// profileGroup returns the audio profile group of the service UUID.
+func profileGroup(serviceUUID string) (string, bool) {
+	parsedUUID, err := uuid.Parse(serviceUUID)
+	if err != nil {
+		return "", false
+	}
+
+	group, ok := deviceProfileGroups[parsedUUID.ID()]
+
+	return group, ok
+}
+
+// checkDeviceProfile checks whether the profile is advertised by the device.
+func (b *Bluez) checkDeviceProfile(devicePath, profileUUID string) error {
+	device := b.getDeviceFromStore(devicePath)
+	if device.Path == "" {
+		return fmt.Errorf("Device %s was not found", devicePath)
+	}
+
+	for _, serviceUUID := range device.UUIDs {
+		if strings.EqualFold(serviceUUID, profileUUID) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Profile %s is not supported by %s", profileUUID, device.Name)
+}
+
+// getConnectedProfiles returns the profiles of the device which were connected.
+func (b *Bluez) getConnectedProfiles(devicePath string) map[string]bool {
+	b.ProfileLock.Lock()
+	defer b.ProfileLock.Unlock()
+
+	connectedProfiles := make(map[string]bool)
+	for profileUUID := range b.ConnectedProfiles[devicePath] {
+		connectedProfiles[profileUUID] = true
+	}
+
+	return connectedProfiles
+}
+
+// setProfileConnected marks the profile of the device as connected or disconnected.
+func (b *Bluez) setProfileConnected(devicePath, profileUUID string, connected bool) {
+	b.ProfileLock.Lock()
+	defer b.ProfileLock.Unlock()
+
+	profileUUID = strings.ToLower(profileUUID)
+
+	if !connected {
+		delete(b.ConnectedProfiles[devicePath], profileUUID)
+		return
+	}
+
+	if b.ConnectedProfiles == nil {
+		b.ConnectedProfiles = make(map[string]map[string]bool)
+	}
+	if b.ConnectedProfiles[devicePath] == nil {
+		b.ConnectedProfiles[devicePath] = make(map[string]bool)
+	}
+
+	b.ConnectedProfiles[devicePath][profileUUID] = true
+}
+
+// clearConnectedProfiles marks all the profiles of the device as disconnected.
+func (b *Bluez) clearConnectedProfiles(devicePath string) {
+	b.ProfileLock.Lock()
+	defer b.ProfileLock.Unlock()
+
+	delete(b.ConnectedProfiles, devicePath)
+}
@@ -0,0 +1,71 @@
+package bluez
+
+import "testing"
+
+func TestDeviceProfiles(t *testing.T) {
+	uuids := []string{
+		"00001124-0000-1000-8000-00805f9b34fb",
+		"0000110b-0000-1000-8000-00805f9b34fb",
+		"0000110e-0000-1000-8000-00805f9b34fb",
+		"0000111e-0000-1000-8000-00805f9b34fb",
+		"81c2e72a-0591-443e-a1ff-05f988593351",
+	}
+	transports := []MediaTransport{
+		{UUID: "0000110a-0000-1000-8000-00805f9b34fb"},
+	}
+	connectedProfiles := map[string]bool{uuids[0]: true}
+
+	want := []DeviceProfile{
+		{UUID: uuids[4], Name: uuids[4], Connected: false},
+		{UUID: uuids[2], Name: "A/V Remote Control (AVRCP)", Connected: false},
+		{UUID: uuids[1], Name: "Audio Sink (A2DP)", Connected: true},
+		{UUID: uuids[3], Name: "Handsfree (HFP)", Connected: false},
+		{UUID: uuids[0], Name: "Human Interface Device Service", Connected: true},
+	}
+
+	profiles := deviceProfiles(uuids, transports, connectedProfiles, false)
+	if len(profiles) != len(want) {
+		t.Fatalf("deviceProfiles() returned %d profiles, want %d", len(profiles), len(want))
+	}
+
+	for i, profile := range profiles {
+		if profile != want[i] {
+			t.Errorf("deviceProfiles()[%d] = %+v, want %+v", i, profile, want[i])
+		}
+	}
+
+	profiles = deviceProfiles(uuids, nil, nil, true)
+	if !profiles[1].Connected || profiles[2].Connected || profiles[4].Connected {
+		t.Errorf("deviceProfiles() = %+v, want only AVRCP connected", profiles)
+	}
+}
+
+func TestConnectedProfiles(t *testing.T) {
+	const devicePath = "/org/bluez/hci0/dev_AA_BB_CC_DD_EE_FF"
+	const hidUUID = "00001124-0000-1000-8000-00805f9b34fb"
+
+	b := &Bluez{Store: make(map[string]StoreObject)}
+	b.addDeviceToStore(Device{
+		Path:    devicePath,
+		Name:    "Keyboard",
+		Adapter: "/org/bluez/hci0",
+		UUIDs:   []string{hidUUID},
+	})
+
+	if err := b.checkDeviceProfile(devicePath, "00001124-0000-1000-8000-00805F9B34FB"); err != nil {
+		t.Errorf("checkDeviceProfile() returned error for an advertised profile: %v", err)
+	}
+	if err := b.checkDeviceProfile(devicePath, "0000110b-0000-1000-8000-00805f9b34fb"); err == nil {
+		t.Error("checkDeviceProfile() returned no error for a profile which is not advertised")
+	}
+
+	b.setProfileConnected(devicePath, hidUUID, true)
+	if !b.getConnectedProfiles(devicePath)[hidUUID] {
+		t.Error("profile is not connected after setProfileConnected()")
+	}
+
+	b.clearConnectedProfiles(devicePath)
+	if b.getConnectedProfiles(devicePath)[hidUUID] {
+		t.Error("profile is connected after clearConnectedProfiles()")
+	}
+}
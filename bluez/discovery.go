@@ -0,0 +1,171 @@
+package bluez
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// DiscoveryFilter describes the parameters of a discovery filter.
+// https://git.kernel.org/pub/scm/bluetooth/bluez.git/tree/doc/adapter-api.txt
+type DiscoveryFilter struct {
+	Transport string
+	UUIDs     []string
+	RSSI      int16
+}
+
+// ParseDiscoveryFilter parses a discovery filter in the form of
+// comma-separated 'parameter:value' pairs, for example
+// 'transport:le,rssi:-70,uuid:0000110b-0000-1000-8000-00805f9b34fb'.
+func ParseDiscoveryFilter(filter string) (DiscoveryFilter, error) {
+	var discoveryFilter DiscoveryFilter
+
+	for _, pv := range strings.Split(filter, ",") {
+		pv = strings.TrimSpace(pv)
+		if pv == "" {
+			continue
+		}
+
+		parameter, value, ok := strings.Cut(pv, ":")
+		if !ok {
+			return DiscoveryFilter{}, fmt.Errorf("Provided parameter:value format '%s' is incorrect", pv)
+		}
+
+		switch parameter {
+		case "transport":
+			switch value {
+			case "auto", "bredr", "le":
+				discoveryFilter.Transport = value
+
+			default:
+				return DiscoveryFilter{}, fmt.Errorf("Provided transport '%s' is incorrect, valid transports are 'auto', 'bredr' or 'le'", value)
+			}
+
+		case "rssi":
+			rssi, err := strconv.ParseInt(value, 10, 16)
+			if err != nil || rssi < -127 || rssi > 20 {
+				return DiscoveryFilter{}, fmt.Errorf("Provided RSSI '%s' is incorrect, it must be between -127 and 20", value)
+			}
+
+			discoveryFilter.RSSI = int16(rssi)
+
+		case "uuid":
+			if _, err := uuid.Parse(value); err != nil {
+				return DiscoveryFilter{}, fmt.Errorf("Provided UUID '%s' is incorrect", value)
+			}
+
+			discoveryFilter.UUIDs = append(discoveryFilter.UUIDs, strings.ToLower(value))
+
+		default:
+			return DiscoveryFilter{}, fmt.Errorf("Provided parameter '%s' is incorrect, valid parameters are 'transport', 'rssi' or 'uuid'", parameter)
+		}
+	}
+
+	return discoveryFilter, nil
+}
+
+// discoveryTransports lists the discovery transports in the order in which they are cycled.
+var discoveryTransports = []string{"auto", "le", "bredr"}
+
+// NextTransport returns the discovery filter with the next transport, cycling
+// between 'auto', 'le' and 'bredr'. The 'auto' transport is the default
+// transport, and is therefore cleared from the filter.
+func (f DiscoveryFilter) NextTransport() DiscoveryFilter {
+	current := f.Transport
+	if current == "" {
+		current = "auto"
+	}
+
+	for i, transport := range discoveryTransports {
+		if transport == current {
+			f.Transport = discoveryTransports[(i+1)%len(discoveryTransports)]
+			break
+		}
+	}
+
+	if f.Transport == "auto" {
+		f.Transport = ""
+	}
+
+	return f
+}
+
+// IsEmpty returns if no parameters are set in the discovery filter.
+func (f DiscoveryFilter) IsEmpty() bool {
+	return f.Transport == "" && f.UUIDs == nil && f.RSSI == 0
+}
+
+// String returns a short description of the discovery filter parameters.
+func (f DiscoveryFilter) String() string {
+	var parameters []string
+
+	if f.Transport != "" {
+		parameters = append(parameters, f.Transport)
+	}
+
+	if f.RSSI != 0 {
+		parameters = append(parameters, fmt.Sprintf("RSSI %d", f.RSSI))
+	}
+
+	switch len(f.UUIDs) {
+	case 0:
+
+	case 1:
+		parameters = append(parameters, ServiceType(f.UUIDs[0]))
+
+	default:
+		parameters = append(parameters, strconv.Itoa(len(f.UUIDs))+" UUIDs")
+	}
+
+	return strings.Join(parameters, ", ")
+}
+
+// SetDiscoveryFilter sets the discovery filter of the adapter.
+// An empty filter clears any previously set filter.
+func (b *Bluez) SetDiscoveryFilter(adapterPath string, filter DiscoveryFilter) error {
+	parameters := make(map[string]interface{})
+
+	if filter.Transport != "" {
+		parameters["Transport"] = filter.Transport
+	}
+
+	if filter.UUIDs != nil {
+		parameters["UUIDs"] = filter.UUIDs
+	}
+
+	if filter.RSSI != 0 {
+		parameters["RSSI"] = filter.RSSI
+	}
+
+	if err := b.CallAdapter(adapterPath, "SetDiscoveryFilter", 0, parameters).Store(); err != nil {
+		return err
+	}
+
+	b.FilterLock.Lock()
+	defer b.FilterLock.Unlock()
+
+	b.Filters[adapterPath] = filter
+
+	return nil
+}
+
+// GetDiscoveryFilter returns the discovery filter which was set on the adapter.
+// If no filter was set on the adapter yet, false is returned.
+func (b *Bluez) GetDiscoveryFilter(adapterPath string) (DiscoveryFilter, bool) {
+	b.FilterLock.Lock()
+	defer b.FilterLock.Unlock()
+
+	filter, ok := b.Filters[adapterPath]
+
+	return filter, ok
+}
+
+// removeDiscoveryFilter removes the stored discovery filter of the adapter.
+func (b *Bluez) removeDiscoveryFilter(adapterPath string) {
+	b.FilterLock.Lock()
+	defer b.FilterLock.Unlock()
+
+	delete(b.Filters, adapterPath)
+}
@@ -0,0 +1,46 @@
+package bluez
+
+import "testing"
+
+func TestParseDiscoveryFilter(t *testing.T) {
+	filter, err := ParseDiscoveryFilter("transport:le, rssi:-70,uuid:0000110B-0000-1000-8000-00805F9B34FB")
+	if err != nil {
+		t.Fatalf("ParseDiscoveryFilter() returned error: %v", err)
+	}
+
+	if filter.Transport != "le" || filter.RSSI != -70 ||
+		len(filter.UUIDs) != 1 || filter.UUIDs[0] != "0000110b-0000-1000-8000-00805f9b34fb" {
+		t.Errorf("ParseDiscoveryFilter() = %+v", filter)
+	}
+
+	if filter.String() != "le, RSSI -70, Audio Sink" {
+		t.Errorf("String() = %q", filter.String())
+	}
+
+	if filter, err := ParseDiscoveryFilter(""); err != nil || !filter.IsEmpty() {
+		t.Errorf("ParseDiscoveryFilter(\"\") = %+v, %v, want an empty filter", filter, err)
+	}
+
+	for _, invalid := range []string{
+		"transport:usb",
+		"rssi:-200",
+		"uuid:1234",
+		"pathloss:10",
+		"transport",
+	} {
+		if _, err := ParseDiscoveryFilter(invalid); err == nil {
+			t.Errorf("ParseDiscoveryFilter(%q) did not return an error", invalid)
+		}
+	}
+}
+
+func TestDiscoveryFilterNextTransport(t *testing.T) {
+	filter := DiscoveryFilter{RSSI: -70}
+
+	for _, want := range []string{"le", "bredr", ""} {
+		filter = filter.NextTransport()
+		if filter.Transport != want || filter.RSSI != -70 {
+			t.Errorf("NextTransport() = %+v, want transport %q with RSSI -70", filter, want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package bluez
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusBluezGattServiceIface        = "org.bluez.GattService1"
+	dbusBluezGattCharacteristicIface = "org.bluez.GattCharacteristic1"
+)
+
+// GattService describes a GATT service of a device.
+type GattService struct {
+	Path    string
+	UUID    string
+	Device  string
+	Primary bool
+
+	Characteristics []GattCharacteristic
+}
+
+// GattCharacteristic describes a GATT characteristic of a service.
+type GattCharacteristic struct {
+	Path      string
+	UUID      string
+	Service   string
+	Flags     []string
+	Notifying bool
+	Value     []byte
+}
+
+// GattCharacteristicChange describes the changed properties of a GATT characteristic.
+type GattCharacteristicChange struct {
+	Path string
+
+	Value        []byte
+	ValueChanged bool
+
+	Notifying        bool
+	NotifyingChanged bool
+}
+
+// GetGattServices returns the GATT services of the device, along with their
+// characteristics. The services and characteristics are sorted by their
+// paths, which are in the order of their handles.
+func (b *Bluez) GetGattServices(devicePath string) ([]GattService, error) {
+	objects, err := b.ManagedObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	return gattServices(devicePath, objects)
+}
+
+// HasGattServices returns if the device has any GATT services.
+func (b *Bluez) HasGattServices(devicePath string) bool {
+	services, err := b.GetGattServices(devicePath)
+
+	return err == nil && services != nil
+}
+
+// ReadCharacteristic reads the value of the GATT characteristic.
+func (b *Bluez) ReadCharacteristic(characteristicPath string) ([]byte, error) {
+	var value []byte
+
+	err := b.conn.Object(dbusBluezName, dbus.ObjectPath(characteristicPath)).
+		Call(dbusBluezGattCharacteristicIface+".ReadValue", 0, map[string]dbus.Variant{}).
+		Store(&value)
+	b.logOperation(characteristicPath, "ReadValue", nil, err)
+
+	return value, err
+}
+
+// WriteCharacteristic writes the value to the GATT characteristic.
+func (b *Bluez) WriteCharacteristic(characteristicPath string, value []byte) error {
+	err := b.conn.Object(dbusBluezName, dbus.ObjectPath(characteristicPath)).
+		Call(dbusBluezGattCharacteristicIface+".WriteValue", 0, value, map[string]dbus.Variant{}).
+		Store()
+	b.logOperation(characteristicPath, "WriteValue", []interface{}{FormatHexValue(value)}, err)
+
+	return err
+}
+
+// StartNotify subscribes to the value changes of the GATT characteristic.
+func (b *Bluez) StartNotify(characteristicPath string) error {
+	err := b.conn.Object(dbusBluezName, dbus.ObjectPath(characteristicPath)).
+		Call(dbusBluezGattCharacteristicIface+".StartNotify", 0).
+		Store()
+	b.logOperation(characteristicPath, "StartNotify", nil, err)
+
+	return err
+}
+
+// StopNotify unsubscribes from the value changes of the GATT characteristic.
+func (b *Bluez) StopNotify(characteristicPath string) error {
+	err := b.conn.Object(dbusBluezName, dbus.ObjectPath(characteristicPath)).
+		Call(dbusBluezGattCharacteristicIface+".StopNotify", 0).
+		Store()
+	b.logOperation(characteristicPath, "StopNotify", nil, err)
+
+	return err
+}
+
+// HasFlag returns if the GATT characteristic has the flag, for example "read" or "notify".
+func (c GattCharacteristic) HasFlag(flag string) bool {
+	for _, f := range c.Flags {
+		if f == flag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanNotify returns if the GATT characteristic supports notifications or indications.
+func (c GattCharacteristic) CanNotify() bool {
+	return c.HasFlag("notify") || c.HasFlag("indicate")
+}
+
+// CanWrite returns if values can be written to the GATT characteristic.
+func (c GattCharacteristic) CanWrite() bool {
+	return c.HasFlag("write") || c.HasFlag("write-without-response")
+}
+
+// ParseHexValue parses a value in hexadecimal form. The bytes can be optionally
+// separated by spaces or colons, and the value can be prefixed with "0x".
+func ParseHexValue(value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "0x"), "0X")
+	value = strings.NewReplacer(" ", "", ":", "").Replace(value)
+
+	if value == "" {
+		return nil, fmt.Errorf("No value provided")
+	}
+
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("Value '%s' is not a valid hexadecimal value", value)
+	}
+
+	return data, nil
+}
+
+// FormatHexValue formats the value as space-separated hexadecimal bytes.
+func FormatHexValue(value []byte) string {
+	bytes := make([]string, len(value))
+	for i, b := range value {
+		bytes[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(bytes, " ")
+}
+
+// gattServices returns the GATT services of the device from the managed objects.
+func gattServices(devicePath string, objects map[dbus.ObjectPath]map[string]map[string]dbus.Variant) ([]GattService, error) {
+	var services []GattService
+
+	characteristics := make(map[string][]GattCharacteristic)
+
+	for path, object := range objects {
+		if !strings.HasPrefix(string(path), devicePath+"/") {
+			continue
+		}
+
+		if values, ok := object[dbusBluezGattServiceIface]; ok {
+			var service GattService
+
+			if err := DecodeVariantMap(values, &service, "UUID"); err != nil {
+				return nil, err
+			}
+			service.Path = string(path)
+
+			services = append(services, service)
+		}
+
+		if values, ok := object[dbusBluezGattCharacteristicIface]; ok {
+			characteristic, err := convertToCharacteristic(string(path), values)
+			if err != nil {
+				return nil, err
+			}
+
+			characteristics[characteristic.Service] = append(characteristics[characteristic.Service], characteristic)
+		}
+	}
+
+	for i, service := range services {
+		serviceCharacteristics := characteristics[service.Path]
+		sort.Slice(serviceCharacteristics, func(i, j int) bool {
+			return serviceCharacteristics[i].Path < serviceCharacteristics[j].Path
+		})
+
+		services[i].Characteristics = serviceCharacteristics
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Path < services[j].Path
+	})
+
+	return services, nil
+}
+
+// convertToCharacteristicChange converts a map of changed dbus objects to a GattCharacteristicChange.
+func convertToCharacteristicChange(path string, values map[string]dbus.Variant) (GattCharacteristicChange, error) {
+	characteristic, err := convertToCharacteristic(path, values)
+	if err != nil {
+		return GattCharacteristicChange{}, err
+	}
+
+	change := GattCharacteristicChange{
+		Path:      path,
+		Value:     characteristic.Value,
+		Notifying: characteristic.Notifying,
+	}
+	_, change.ValueChanged = values["Value"]
+	_, change.NotifyingChanged = values["Notifying"]
+
+	return change, nil
+}
+
+// convertToCharacteristic converts a map of dbus objects to a GattCharacteristic.
+func convertToCharacteristic(path string, values map[string]dbus.Variant) (GattCharacteristic, error) {
+	var characteristic GattCharacteristic
+
+	if err := DecodeVariantMap(values, &characteristic, "UUID"); err != nil {
+		return GattCharacteristic{}, err
+	}
+	characteristic.Path = path
+
+	return characteristic, nil
+}
@@ -0,0 +1,86 @@
+package bluez
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestGattServices(t *testing.T) {
+	const devicePath = "/org/bluez/hci0/dev_AA_BB_CC_DD_EE_FF"
+
+	objects := map[dbus.ObjectPath]map[string]map[string]dbus.Variant{
+		devicePath + "/service000a": {
+			dbusBluezGattServiceIface: {
+				"UUID":    dbus.MakeVariant("0000180f-0000-1000-8000-00805f9b34fb"),
+				"Device":  dbus.MakeVariant(dbus.ObjectPath(devicePath)),
+				"Primary": dbus.MakeVariant(true),
+			},
+		},
+		devicePath + "/service000a/char000b": {
+			dbusBluezGattCharacteristicIface: {
+				"UUID":    dbus.MakeVariant("00002a19-0000-1000-8000-00805f9b34fb"),
+				"Service": dbus.MakeVariant(dbus.ObjectPath(devicePath + "/service000a")),
+				"Flags":   dbus.MakeVariant([]string{"read", "notify"}),
+				"Value":   dbus.MakeVariant([]byte{0x64}),
+			},
+		},
+		"/org/bluez/hci0/dev_11_22_33_44_55_66/service000a": {
+			dbusBluezGattServiceIface: {
+				"UUID": dbus.MakeVariant("00001800-0000-1000-8000-00805f9b34fb"),
+			},
+		},
+	}
+
+	services, err := gattServices(devicePath, objects)
+	if err != nil {
+		t.Fatalf("gattServices() returned error: %v", err)
+	}
+
+	if len(services) != 1 || services[0].Device != devicePath || !services[0].Primary ||
+		len(services[0].Characteristics) != 1 {
+		t.Fatalf("gattServices() = %+v, want one primary service with one characteristic", services)
+	}
+
+	characteristic := services[0].Characteristics[0]
+	if !characteristic.HasFlag("read") || !characteristic.CanNotify() || characteristic.CanWrite() ||
+		!bytes.Equal(characteristic.Value, []byte{0x64}) {
+		t.Errorf("characteristic = %+v, want a readable and notifiable characteristic with value 64", characteristic)
+	}
+}
+
+func TestParseHexValue(t *testing.T) {
+	for _, value := range []string{"0a1bff", "0x0A1BFF", "0a 1b ff", "0a:1b:ff"} {
+		data, err := ParseHexValue(value)
+		if err != nil || !bytes.Equal(data, []byte{0x0a, 0x1b, 0xff}) {
+			t.Errorf("ParseHexValue(%q) = %v, %v, want [0a 1b ff]", value, data, err)
+		}
+	}
+
+	for _, value := range []string{"", "0x", "abc", "zz"} {
+		if _, err := ParseHexValue(value); err == nil {
+			t.Errorf("ParseHexValue(%q) returned no error", value)
+		}
+	}
+
+	if formatted := FormatHexValue([]byte{0x0a, 0x1b, 0xff}); formatted != "0A 1B FF" {
+		t.Errorf("FormatHexValue() = %q, want %q", formatted, "0A 1B FF")
+	}
+}
+
+func TestCharacteristicChange(t *testing.T) {
+	const path = "/org/bluez/hci0/dev_AA_BB_CC_DD_EE_FF/service000a/char000b"
+
+	change, err := convertToCharacteristicChange(path, map[string]dbus.Variant{
+		"Value": dbus.MakeVariant([]byte{0x01, 0x02}),
+	})
+	if err != nil {
+		t.Fatalf("convertToCharacteristicChange() returned error: %v", err)
+	}
+
+	if change.Path != path || !change.ValueChanged || change.NotifyingChanged ||
+		!bytes.Equal(change.Value, []byte{0x01, 0x02}) {
+		t.Errorf("convertToCharacteristicChange() = %+v, want only a changed value", change)
+	}
+}
@@ -0,0 +1,96 @@
+package bluez
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+// HCI socket and connection list values.
+// https://git.kernel.org/pub/scm/bluetooth/bluez.git/tree/lib/hci.h
+const (
+	afBluetooth = 31
+	btprotoHCI  = 1
+
+	hciGetConnList = 0x800448d4
+	hciMaxConns    = 10
+	hciConnInfoLen = 16
+
+	hciACLLink  = 1
+	hciLMMaster = 0x0001
+)
+
+// Link roles of a connection.
+const (
+	LinkRoleUnknown = "unknown"
+	LinkRoleMaster  = "master"
+	LinkRoleSlave   = "slave"
+)
+
+// GetLinkRole returns the role of the adapter in the classic (ACL) link
+// with the device, by querying the connection list of the adapter from
+// the kernel. If the role cannot be determined, LinkRoleUnknown is returned.
+func GetLinkRole(adapterPath, address string) (string, error) {
+	devID, err := strconv.ParseUint(strings.TrimPrefix(GetAdapterID(adapterPath), "hci"), 10, 16)
+	if err != nil {
+		return LinkRoleUnknown, fmt.Errorf("Cannot get the adapter index of %s", adapterPath)
+	}
+
+	fd, err := syscall.Socket(afBluetooth, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, btprotoHCI)
+	if err != nil {
+		return LinkRoleUnknown, errors.Wrap(err, "Cannot open HCI socket")
+	}
+	defer syscall.Close(fd)
+
+	// The request is laid out as: dev_id, conn_num (uint16), followed by
+	// conn_num hci_conn_info structures.
+	request := make([]byte, 4+hciMaxConns*hciConnInfoLen)
+	binary.LittleEndian.PutUint16(request[0:], uint16(devID))
+	binary.LittleEndian.PutUint16(request[2:], hciMaxConns)
+
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_IOCTL, uintptr(fd),
+		hciGetConnList, uintptr(unsafe.Pointer(&request[0])),
+	)
+	if errno != 0 {
+		return LinkRoleUnknown, errors.Wrap(errno, "Cannot get HCI connection list")
+	}
+
+	return parseLinkRole(request, address), nil
+}
+
+// parseLinkRole returns the link role of the device's classic connection
+// from the connection list response.
+func parseLinkRole(response []byte, address string) string {
+	count := int(binary.LittleEndian.Uint16(response[2:]))
+
+	for i := 0; i < count && i < hciMaxConns; i++ {
+		// The connection info is laid out as: handle (uint16), bdaddr (6 bytes,
+		// in reverse order), type, out (uint8), state (uint16), link_mode (uint32).
+		info := response[4+i*hciConnInfoLen : 4+(i+1)*hciConnInfoLen]
+		if info[8] != hciACLLink {
+			continue
+		}
+
+		bdaddr := make([]string, 6)
+		for j := 0; j < 6; j++ {
+			bdaddr[5-j] = fmt.Sprintf("%02X", info[2+j])
+		}
+		if !strings.EqualFold(strings.Join(bdaddr, ":"), address) {
+			continue
+		}
+
+		if binary.LittleEndian.Uint32(info[12:])&hciLMMaster != 0 {
+			return LinkRoleMaster
+		}
+
+		return LinkRoleSlave
+	}
+
+	return LinkRoleUnknown
+}
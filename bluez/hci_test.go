@@ -0,0 +1,31 @@
+package bluez
+
+import "testing"
+
+func TestParseLinkRole(t *testing.T) {
+	response := make([]byte, 4+hciMaxConns*hciConnInfoLen)
+	response[2] = 2
+
+	// An LE connection to the same device, which must be ignored.
+	le := response[4:]
+	copy(le[2:], []byte{0xff, 0xee, 0xdd, 0xcc, 0xbb, 0xaa})
+	le[8] = 0x80
+	le[12] = hciLMMaster
+
+	acl := response[4+hciConnInfoLen:]
+	copy(acl[2:], []byte{0xff, 0xee, 0xdd, 0xcc, 0xbb, 0xaa})
+	acl[8] = hciACLLink
+
+	if role := parseLinkRole(response, "aa:bb:cc:dd:ee:ff"); role != LinkRoleSlave {
+		t.Errorf("parseLinkRole() = %s, want %s", role, LinkRoleSlave)
+	}
+
+	acl[12] = hciLMMaster
+	if role := parseLinkRole(response, "AA:BB:CC:DD:EE:FF"); role != LinkRoleMaster {
+		t.Errorf("parseLinkRole() = %s, want %s", role, LinkRoleMaster)
+	}
+
+	if role := parseLinkRole(response, "11:22:33:44:55:66"); role != LinkRoleUnknown {
+		t.Errorf("parseLinkRole() = %s, want %s", role, LinkRoleUnknown)
+	}
+}
@@ -4,14 +4,43 @@ import (
 	"fmt"
 
 	"github.com/godbus/dbus/v5"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 )
 
 const (
 	dbusBluezMediaControlIface = "org.bluez.MediaControl1"
 	dbusBluezMediaPlayerIface  = "org.bluez.MediaPlayer1"
+
+	dbusBluezMediaTransportIface = "org.bluez.MediaTransport1"
 )
 
+// MaxTransportVolume is the maximum AVRCP absolute volume of a media transport.
+const MaxTransportVolume = 127
+
+// MediaTransport describes a media transport of a device.
+type MediaTransport struct {
+	Path   string
+	Device string
+	UUID   string
+	Codec  byte
+	State  string
+	Volume uint16
+
+	Configuration []byte
+
+	// VolumeSupported is set if the transport has the Volume property,
+	// which is only present if the device supports absolute volume.
+	VolumeSupported bool
+}
+
+// MediaControl describes the media control state of a device.
+type MediaControl struct {
+	Device    string
+	Player    string
+	Connected bool
+}
+
 // MediaProperties holds the media player information.
 type MediaProperties struct {
 	Status   string
@@ -20,7 +49,8 @@ type MediaProperties struct {
 }
 
 // TrackProperties describes the track properties of
-// the currently playing media.
+// the currently playing media. Players may not report
+// all of the properties, in which case they are empty.
 type TrackProperties struct {
 	Title       string
 	Album       string
@@ -52,6 +82,25 @@ func (b *Bluez) InitMediaPlayer(devicePath string) error {
 	return nil
 }
 
+// GetMediaControl gets the media control state of a device.
+func (b *Bluez) GetMediaControl(devicePath string) (MediaControl, error) {
+	control := MediaControl{Device: devicePath}
+
+	mediaControl, err := b.GetMediaControlProperties(devicePath)
+	if err != nil {
+		return control, err
+	}
+
+	return control, DecodeVariantMap(mediaControl, &control)
+}
+
+// HasMediaPlayer returns if the device has a connected media player.
+func (b *Bluez) HasMediaPlayer(devicePath string) bool {
+	control, err := b.GetMediaControl(devicePath)
+
+	return err == nil && control.Connected && control.Player != ""
+}
+
 // Play starts the media playback.
 func (b *Bluez) Play() error {
 	return b.CallMediaPlayer("Play")
@@ -119,10 +168,7 @@ func (b *Bluez) GetMediaProperties(values ...map[string]dbus.Variant) (MediaProp
 		mediaPlayer = mp
 	}
 
-	track := TrackProperties{
-		Artist: "<Unknown Artist>",
-		Album:  "<Unknown Album>",
-	}
+	var track TrackProperties
 	if t, ok := mediaPlayer["Track"].Value().(map[string]dbus.Variant); ok {
 		if err := DecodeVariantMap(t, &track); err != nil {
 			return MediaProperties{}, err
@@ -216,3 +262,125 @@ func (b *Bluez) CallMediaPlayer(command string) error {
 		Call(dbusBluezMediaPlayerIface+"."+command, 0).
 		Store()
 }
+
+// GetTransports returns the media transports that belong to the device.
+func (b *Bluez) GetTransports(devicePath string) []MediaTransport {
+	b.TransportLock.Lock()
+	defer b.TransportLock.Unlock()
+
+	var transports []MediaTransport
+
+	for _, transport := range b.Transports {
+		if transport.Device == devicePath {
+			transports = append(transports, transport)
+		}
+	}
+
+	return transports
+}
+
+// GetVolumeTransport returns the media transport of the device which supports
+// absolute volume control, if any.
+func (b *Bluez) GetVolumeTransport(devicePath string) (MediaTransport, bool) {
+	for _, transport := range b.GetTransports(devicePath) {
+		if transport.VolumeSupported {
+			return transport, true
+		}
+	}
+
+	return MediaTransport{}, false
+}
+
+// SetTransportVolume sets the absolute volume of the media transport.
+// The volume is clamped to the range 0-127.
+func (b *Bluez) SetTransportVolume(transportPath string, volume int) error {
+	path := dbus.ObjectPath(transportPath)
+	value := ClampTransportVolume(volume)
+
+	err := b.conn.Object(dbusBluezName, path).Call("org.freedesktop.DBus.Properties.Set", 0, dbusBluezMediaTransportIface, "Volume", dbus.MakeVariant(value)).Store()
+	b.logOperation(transportPath, "Set Volume", []interface{}{value}, err)
+
+	return err
+}
+
+// ClampTransportVolume clamps the volume to the range of the absolute volume.
+func ClampTransportVolume(volume int) uint16 {
+	switch {
+	case volume < 0:
+		return 0
+
+	case volume > MaxTransportVolume:
+		return MaxTransportVolume
+	}
+
+	return uint16(volume)
+}
+
+// VolumePercent returns the absolute volume of the media transport as a percentage.
+func (t MediaTransport) VolumePercent() int {
+	return (int(t.Volume)*100 + MaxTransportVolume/2) / MaxTransportVolume
+}
+
+// IsMicrophoneActive returns if the device has an active headset or handsfree
+// media transport, which indicates that its microphone is in use.
+func (b *Bluez) IsMicrophoneActive(devicePath string) bool {
+	for _, transport := range b.GetTransports(devicePath) {
+		if transport.State != "active" {
+			continue
+		}
+
+		serviceUUID, err := uuid.Parse(transport.UUID)
+		if err != nil {
+			continue
+		}
+
+		switch serviceUUID.ID() {
+		case HEADSET_SVCLASS_ID, HEADSET_AGW_SVCLASS_ID, HANDSFREE_SVCLASS_ID, HANDSFREE_AGW_SVCLASS_ID:
+			return true
+		}
+	}
+
+	return false
+}
+
+// ConvertToTransport converts a map of dbus objects to a MediaTransport.
+func (b *Bluez) ConvertToTransport(path string, values map[string]dbus.Variant) (MediaTransport, error) {
+	var transport MediaTransport
+
+	if err := DecodeVariantMap(values, &transport, "State"); err != nil {
+		return MediaTransport{}, err
+	}
+	transport.Path = path
+	_, transport.VolumeSupported = values["Volume"]
+
+	return transport, nil
+}
+
+// addTransportToStore adds a media transport to the store.
+func (b *Bluez) addTransportToStore(transport MediaTransport) {
+	b.TransportLock.Lock()
+	defer b.TransportLock.Unlock()
+
+	b.Transports[transport.Path] = transport
+}
+
+// removeTransportFromStore removes a media transport from the store,
+// using its path.
+func (b *Bluez) removeTransportFromStore(transportPath string) MediaTransport {
+	b.TransportLock.Lock()
+	defer b.TransportLock.Unlock()
+
+	transport := b.Transports[transportPath]
+	delete(b.Transports, transportPath)
+
+	return transport
+}
+
+// getTransportFromStore gets a media transport from the store,
+// using its path.
+func (b *Bluez) getTransportFromStore(transportPath string) MediaTransport {
+	b.TransportLock.Lock()
+	defer b.TransportLock.Unlock()
+
+	return b.Transports[transportPath]
+}
@@ -0,0 +1,110 @@
+package bluez
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestIsMicrophoneActive(t *testing.T) {
+	const devicePath = "/org/bluez/hci0/dev_AA_BB_CC_DD_EE_FF"
+
+	b := &Bluez{
+		Transports: map[string]MediaTransport{
+			devicePath + "/fd0": {
+				Device: devicePath,
+				UUID:   "0000110b-0000-1000-8000-00805f9b34fb",
+				State:  "active",
+			},
+			devicePath + "/fd1": {
+				Device: devicePath,
+				UUID:   "0000111e-0000-1000-8000-00805f9b34fb",
+				State:  "idle",
+			},
+		},
+	}
+
+	if b.IsMicrophoneActive(devicePath) {
+		t.Error("IsMicrophoneActive() = true with an idle handsfree transport")
+	}
+
+	transport := b.Transports[devicePath+"/fd1"]
+	transport.State = "active"
+	b.Transports[devicePath+"/fd1"] = transport
+
+	if !b.IsMicrophoneActive(devicePath) {
+		t.Error("IsMicrophoneActive() = false with an active handsfree transport")
+	}
+}
+
+func TestCodecName(t *testing.T) {
+	const a2dpSink = "0000110b-0000-1000-8000-00805f9b34fb"
+
+	b := &Bluez{}
+
+	transport, err := b.ConvertToTransport("/org/bluez/hci0/dev_AA_BB_CC_DD_EE_FF/fd0", map[string]dbus.Variant{
+		"UUID":          dbus.MakeVariant(a2dpSink),
+		"Codec":         dbus.MakeVariant(byte(0xFF)),
+		"Configuration": dbus.MakeVariant([]byte{0x2D, 0x01, 0x00, 0x00, 0xAA, 0x00, 0x3C, 0x01}),
+		"State":         dbus.MakeVariant("idle"),
+	})
+	if err != nil {
+		t.Fatalf("ConvertToTransport() returned error: %v", err)
+	}
+
+	if name := transport.CodecName(); name != "LDAC" {
+		t.Errorf("CodecName() = %q, want LDAC", name)
+	}
+
+	for _, test := range []struct {
+		transport MediaTransport
+		name      string
+	}{
+		{MediaTransport{UUID: a2dpSink, Codec: 0x00}, "SBC"},
+		{MediaTransport{UUID: a2dpSink, Codec: 0x02}, "AAC"},
+		{MediaTransport{UUID: a2dpSink, Codec: 0xFF, Configuration: []byte{0x4F, 0, 0, 0, 0x01, 0}}, "aptX"},
+		{MediaTransport{UUID: a2dpSink, Codec: 0xFF, Configuration: []byte{0x01, 0, 0, 0, 0x01, 0}}, "unknown"},
+		{MediaTransport{UUID: a2dpSink, Codec: 0xFF}, "unknown"},
+		{MediaTransport{UUID: a2dpSink, Codec: 0x10}, "unknown"},
+		{MediaTransport{UUID: "0000111e-0000-1000-8000-00805f9b34fb", Codec: 0x02}, "unknown"},
+	} {
+		if name := test.transport.CodecName(); name != test.name {
+			t.Errorf("CodecName(%+v) = %q, want %q", test.transport, name, test.name)
+		}
+	}
+}
+
+func TestTransportVolume(t *testing.T) {
+	for _, test := range []struct {
+		volume int
+		want   uint16
+	}{
+		{-10, 0},
+		{0, 0},
+		{64, 64},
+		{127, 127},
+		{200, 127},
+	} {
+		if got := ClampTransportVolume(test.volume); got != test.want {
+			t.Errorf("ClampTransportVolume(%d) = %d, want %d", test.volume, got, test.want)
+		}
+	}
+
+	b := &Bluez{}
+
+	transport, err := b.ConvertToTransport("/org/bluez/hci0/dev_AA_BB_CC_DD_EE_FF/fd0", map[string]dbus.Variant{
+		"Volume": dbus.MakeVariant(uint16(127)),
+	})
+	if err != nil {
+		t.Fatalf("ConvertToTransport() returned error: %v", err)
+	}
+
+	if !transport.VolumeSupported || transport.VolumePercent() != 100 {
+		t.Errorf("transport.VolumeSupported = %v, VolumePercent() = %d, want true, 100", transport.VolumeSupported, transport.VolumePercent())
+	}
+
+	transport, _ = b.ConvertToTransport("/org/bluez/hci0/dev_AA_BB_CC_DD_EE_FF/fd1", map[string]dbus.Variant{})
+	if transport.VolumeSupported {
+		t.Error("transport.VolumeSupported = true without the Volume property")
+	}
+}
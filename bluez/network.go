@@ -0,0 +1,63 @@
+package bluez
+
+import (
+	"github.com/godbus/dbus/v5"
+)
+
+const dbusBluezNetworkIface = "org.bluez.Network1"
+
+// NetworkConnection describes the PAN network connection of a device.
+type NetworkConnection struct {
+	Device    string
+	Connected bool
+	Interface string
+	UUID      string
+}
+
+// ConnectNetwork connects to the PAN network service of a device with
+// the provided role ("nap" to use the device as a network access point),
+// and returns the name of the created network interface.
+func (b *Bluez) ConnectNetwork(devicePath, role string) (string, error) {
+	var iface string
+
+	err := b.CallNetwork(devicePath, "Connect", role).Store(&iface)
+
+	return iface, err
+}
+
+// DisconnectNetwork disconnects from the PAN network service of a device.
+func (b *Bluez) DisconnectNetwork(devicePath string) error {
+	return b.CallNetwork(devicePath, "Disconnect").Store()
+}
+
+// GetNetworkConnection gets the PAN network connection of a device.
+func (b *Bluez) GetNetworkConnection(devicePath string) (NetworkConnection, error) {
+	connection := NetworkConnection{Device: devicePath}
+
+	props, err := b.GetNetworkProperties(devicePath)
+	if err != nil {
+		return connection, err
+	}
+
+	return connection, DecodeVariantMap(props, &connection)
+}
+
+// GetNetworkProperties gets the properties of the PAN network service of a device.
+func (b *Bluez) GetNetworkProperties(devicePath string) (map[string]dbus.Variant, error) {
+	result := make(map[string]dbus.Variant)
+	path := dbus.ObjectPath(devicePath)
+
+	if err := b.conn.Object(dbusBluezName, path).
+		Call(dbusPropertiesGetAllPath, 0, dbusBluezNetworkIface).
+		Store(&result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CallNetwork calls the Network1 interface with the provided method.
+func (b *Bluez) CallNetwork(devicePath, method string, args ...interface{}) *dbus.Call {
+	path := dbus.ObjectPath(devicePath)
+	return b.conn.Object(dbusBluezName, path).Call(dbusBluezNetworkIface+"."+method, 0, args...)
+}
@@ -15,6 +15,7 @@ const (
 	dbusObexSessionIface    = "org.bluez.obex.Session1"
 	dbusObexTransferIface   = "org.bluez.obex.Transfer1"
 	dbusObexObjectPushIface = "org.bluez.obex.ObjectPush1"
+	dbusObexFileTransIface  = "org.bluez.obex.FileTransfer1"
 
 	dbusObexPath = dbus.ObjectPath("/org/bluez/obex")
 )
@@ -52,6 +53,20 @@ type ObexProperties struct {
 	TransferProperties ObexTransferProperties
 }
 
+// ObexFolderEntry describes a file or a folder in
+// the current folder of an OBEX file transfer session.
+type ObexFolderEntry struct {
+	Name     string
+	Type     string
+	Size     uint64
+	Modified string
+}
+
+// IsFolder returns if the entry is a folder.
+func (e ObexFolderEntry) IsFolder() bool {
+	return e.Type == "folder"
+}
+
 // Obex represents an OBEX connection. It also stores
 // information about the currently running transfers.
 type Obex struct {
@@ -90,10 +105,21 @@ func (o *Obex) Conn() *dbus.Conn {
 
 // CreateSession creates a new OBEX transfer session.
 func (o *Obex) CreateSession(ctx context.Context, address string) (dbus.ObjectPath, error) {
+	return o.createSession(ctx, address, "opp")
+}
+
+// CreateFileTransferSession creates a new OBEX file transfer (FTP) session,
+// which is used to browse the filesystem of the device.
+func (o *Obex) CreateFileTransferSession(ctx context.Context, address string) (dbus.ObjectPath, error) {
+	return o.createSession(ctx, address, "ftp")
+}
+
+// createSession creates a new OBEX session with the provided target.
+func (o *Obex) createSession(ctx context.Context, address, target string) (dbus.ObjectPath, error) {
 	var sessionPath dbus.ObjectPath
 
 	args := make(map[string]interface{})
-	args["Target"] = "opp"
+	args["Target"] = target
 
 	session := o.CallClientAsync(ctx, "CreateSession", address, args)
 	select {
@@ -135,6 +161,49 @@ func (o *Obex) SendFile(sessionPath dbus.ObjectPath, path string) (dbus.ObjectPa
 	return transferPath, transferProperties, err
 }
 
+// ChangeFolder changes the current folder of the file transfer session.
+// The ".." folder changes to the parent folder.
+func (o *Obex) ChangeFolder(sessionPath dbus.ObjectPath, folder string) error {
+	return o.CallFileTransfer(sessionPath, "ChangeFolder", folder).Store()
+}
+
+// ListFolder lists the files and folders in the current folder of the file transfer session.
+func (o *Obex) ListFolder(sessionPath dbus.ObjectPath) ([]ObexFolderEntry, error) {
+	var listing []map[string]dbus.Variant
+
+	if err := o.CallFileTransfer(sessionPath, "ListFolder").Store(&listing); err != nil {
+		return nil, err
+	}
+
+	entries := make([]ObexFolderEntry, 0, len(listing))
+	for _, values := range listing {
+		var entry ObexFolderEntry
+		if err := DecodeVariantMap(values, &entry, "Name", "Type"); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// GetFile copies a file from the current folder of the file transfer session
+// to the target path.
+func (o *Obex) GetFile(sessionPath dbus.ObjectPath, targetPath, name string) (dbus.ObjectPath, ObexTransferProperties, error) {
+	var transferPath dbus.ObjectPath
+
+	transferPropertyMap := make(map[string]dbus.Variant)
+	if err := o.CallFileTransfer(sessionPath, "GetFile", targetPath, name).Store(&transferPath, &transferPropertyMap); err != nil {
+		return "", ObexTransferProperties{}, err
+	}
+
+	transferProperties, err := o.GetTransferProperties(transferPropertyMap)
+	o.addTransferPropertiesToStore(transferPath, transferProperties)
+
+	return transferPath, transferProperties, err
+}
+
 // ReceiveFile returns a path where the OBEX daemon (obexd) will receive the file, along with
 // the transfer properties.
 func (o *Obex) ReceiveFile(sessionPath, transferPath dbus.ObjectPath) (string, string, ObexTransferProperties, error) {
@@ -305,6 +374,11 @@ func (o *Obex) CallObjectPush(sessionPath dbus.ObjectPath, method string, args .
 	return o.conn.Object(dbusObexName, sessionPath).Call(dbusObexObjectPushIface+"."+method, 0, args...)
 }
 
+// CallFileTransfer calls the FileTransfer1 interface with the provided method.
+func (o *Obex) CallFileTransfer(sessionPath dbus.ObjectPath, method string, args ...interface{}) *dbus.Call {
+	return o.conn.Object(dbusObexName, sessionPath).Call(dbusObexFileTransIface+"."+method, 0, args...)
+}
+
 // CallTransfer calls the Transfer1 interface with the provided method.
 func (o *Obex) CallTransfer(transferPath dbus.ObjectPath, method string, args ...interface{}) *dbus.Call {
 	return o.conn.Object(dbusObexName, transferPath).Call(dbusObexTransferIface+"."+method, 0, args...)
@@ -0,0 +1,101 @@
+package bluez
+
+import (
+	"context"
+	"sync"
+)
+
+// ConnectQueue describes a queue of connect operations, which are
+// processed up to a configurable number at a time. Disconnections
+// are not queued, so that they can cancel pending connections.
+type ConnectQueue struct {
+	slots   chan struct{}
+	pending map[string]string
+	changed func(devicePath string)
+
+	lock sync.Mutex
+}
+
+// newConnectQueue returns a new ConnectQueue, which processes
+// one operation at a time.
+func newConnectQueue() *ConnectQueue {
+	return &ConnectQueue{
+		slots:   make(chan struct{}, 1),
+		pending: make(map[string]string),
+	}
+}
+
+// SetConnectConcurrency sets the maximum number of connect
+// operations to be processed at a time.
+func (b *Bluez) SetConnectConcurrency(concurrency int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	b.queue.lock.Lock()
+	defer b.queue.lock.Unlock()
+
+	b.queue.slots = make(chan struct{}, concurrency)
+}
+
+// SetConnectQueueHandler sets the handler which is called with the
+// device path, whenever an operation for the device is queued or started.
+func (b *Bluez) SetConnectQueueHandler(handler func(devicePath string)) {
+	b.queue.lock.Lock()
+	defer b.queue.lock.Unlock()
+
+	b.queue.changed = handler
+}
+
+// GetQueuedOperation returns the operation that is queued for the device,
+// for example "connect". An empty string is returned if no operation is queued.
+func (b *Bluez) GetQueuedOperation(devicePath string) string {
+	b.queue.lock.Lock()
+	defer b.queue.lock.Unlock()
+
+	return b.queue.pending[devicePath]
+}
+
+// runQueued waits for the queue to have a free slot, and runs the operation.
+// If the context is cancelled before the operation is started, the operation
+// is removed from the queue, and the error of the context is returned.
+func (b *Bluez) runQueued(ctx context.Context, devicePath, operation string, run func() error) error {
+	b.queue.lock.Lock()
+	slots := b.queue.slots
+	b.queue.pending[devicePath] = operation
+	b.queue.lock.Unlock()
+
+	b.queueChanged(devicePath)
+
+	var err error
+	select {
+	case slots <- struct{}{}:
+		defer func() { <-slots }()
+
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	b.queue.lock.Lock()
+	delete(b.queue.pending, devicePath)
+	b.queue.lock.Unlock()
+
+	b.queueChanged(devicePath)
+
+	if err != nil {
+		return err
+	}
+
+	return run()
+}
+
+// queueChanged calls the queue handler, if it is set.
+func (b *Bluez) queueChanged(devicePath string) {
+	b.queue.lock.Lock()
+	changed := b.queue.changed
+	b.queue.lock.Unlock()
+
+	if changed != nil {
+		changed(devicePath)
+	}
+}
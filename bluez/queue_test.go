@@ -0,0 +1,102 @@
+package bluez
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestConnectQueue(t *testing.T) {
+	var active, maxActive int
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+
+	b := &Bluez{queue: newConnectQueue()}
+	b.SetConnectConcurrency(2)
+
+	devicePaths := []string{"/dev_1", "/dev_2", "/dev_3"}
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	for _, devicePath := range devicePaths {
+		wg.Add(1)
+
+		devicePath := devicePath
+		go func() {
+			defer wg.Done()
+
+			b.runQueued(context.Background(), devicePath, "connect", func() error {
+				lock.Lock()
+				active++
+				if active > maxActive {
+					maxActive = active
+				}
+				lock.Unlock()
+
+				started <- struct{}{}
+				<-release
+
+				lock.Lock()
+				active--
+				lock.Unlock()
+
+				return nil
+			})
+		}()
+	}
+
+	<-started
+	<-started
+
+	var queued int
+	for _, devicePath := range devicePaths {
+		if b.GetQueuedOperation(devicePath) == "connect" {
+			queued++
+		}
+	}
+
+	close(release)
+	<-started
+	wg.Wait()
+
+	if maxActive != 2 {
+		t.Errorf("maximum active operations = %d, want 2", maxActive)
+	}
+	if queued > 1 {
+		t.Errorf("queued operations = %d, want at most 1", queued)
+	}
+}
+
+func TestConnectQueueCancel(t *testing.T) {
+	b := &Bluez{queue: newConnectQueue()}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go b.runQueued(context.Background(), "/dev_1", "connect", func() error {
+		close(started)
+		<-release
+
+		return nil
+	})
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- b.runQueued(ctx, "/dev_2", "connect", func() error {
+			t.Error("operation was run after it was cancelled")
+			return nil
+		})
+	}()
+
+	cancel()
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Errorf("runQueued() = %v, want %v", err, context.Canceled)
+	}
+	if operation := b.GetQueuedOperation("/dev_2"); operation != "" {
+		t.Errorf("queued operation = %q after it was cancelled, want none", operation)
+	}
+
+	close(release)
+}
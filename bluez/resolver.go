@@ -44,12 +44,7 @@ func DecodeVariantMap(
 			}
 		}
 
-		val := value.Value()
-		if v, ok := val.(dbus.ObjectPath); ok {
-			val = string(v)
-		}
-
-		props[key] = val
+		props[key] = variantValue(value.Value())
 	}
 
 	resolver.encoder.ResetBytes(&resolver.data)
@@ -60,3 +55,34 @@ func DecodeVariantMap(
 	resolver.decoder.ResetBytes(resolver.data)
 	return resolver.decoder.Decode(data)
 }
+
+// variantValue converts the provided variant value into a value that
+// can be encoded. Any nested variants (for example, within advertisement
+// data maps) are unwrapped into their underlying values.
+func variantValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case dbus.ObjectPath:
+		return string(v)
+
+	case dbus.Variant:
+		return variantValue(v.Value())
+
+	case map[uint16]dbus.Variant:
+		values := make(map[uint16]interface{}, len(v))
+		for key, val := range v {
+			values[key] = variantValue(val.Value())
+		}
+
+		return values
+
+	case map[string]dbus.Variant:
+		values := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			values[key] = variantValue(val.Value())
+		}
+
+		return values
+	}
+
+	return value
+}
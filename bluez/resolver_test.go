@@ -0,0 +1,85 @@
+package bluez
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestDecodeVariantMapAdvertisementData(t *testing.T) {
+	ibeacon := []byte{
+		0x02, 0x15,
+		0xe2, 0xc5, 0x6d, 0xb5, 0xdf, 0xfb, 0x48, 0xd2,
+		0xb0, 0x60, 0xd0, 0xf5, 0xa7, 0x10, 0x96, 0xe0,
+		0x00, 0x01, 0x00, 0x02, 0xc5,
+	}
+	eddystone := []byte{0x10, 0xeb, 0x03, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 0x00}
+
+	values := map[string]dbus.Variant{
+		"Name":    dbus.MakeVariant("Beacon"),
+		"Address": dbus.MakeVariant("AA:BB:CC:DD:EE:FF"),
+		"Adapter": dbus.MakeVariant(dbus.ObjectPath("/org/bluez/hci0")),
+		"RSSI":    dbus.MakeVariant(int16(-60)),
+		"ManufacturerData": dbus.MakeVariant(map[uint16]dbus.Variant{
+			appleCompanyID: dbus.MakeVariant(ibeacon),
+		}),
+		"ServiceData": dbus.MakeVariant(map[string]dbus.Variant{
+			eddystoneUUID: dbus.MakeVariant(eddystone),
+		}),
+	}
+
+	var device Device
+	if err := DecodeVariantMap(values, &device, "Name", "Address"); err != nil {
+		t.Fatalf("DecodeVariantMap returned error: %v", err)
+	}
+
+	if device.Name != "Beacon" || device.Address != "AA:BB:CC:DD:EE:FF" {
+		t.Errorf("unexpected name/address: %q/%q", device.Name, device.Address)
+	}
+	if device.Adapter != "/org/bluez/hci0" {
+		t.Errorf("unexpected adapter: %q", device.Adapter)
+	}
+	if device.RSSI != -60 {
+		t.Errorf("unexpected RSSI: %d", device.RSSI)
+	}
+
+	if data := device.ManufacturerData[appleCompanyID]; !bytes.Equal(data, ibeacon) {
+		t.Errorf("unexpected manufacturer data: %x", data)
+	}
+	if data := device.ServiceData[eddystoneUUID]; !bytes.Equal(data, eddystone) {
+		t.Errorf("unexpected service data: %x", data)
+	}
+
+	want := "iBeacon: UUID e2c56db5-dffb-48d2-b060-d0f5a71096e0, Major 1, Minor 2, TX Power -59 dBm"
+	if got := DecodeManufacturerData(appleCompanyID, device.ManufacturerData[appleCompanyID]); got != want {
+		t.Errorf("DecodeManufacturerData() = %q, want %q", got, want)
+	}
+
+	want = "Eddystone-URL: https://example.com/, TX Power -21 dBm"
+	if got := DecodeServiceData(eddystoneUUID, device.ServiceData[eddystoneUUID]); got != want {
+		t.Errorf("DecodeServiceData() = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeVariantMapAdapterRoles(t *testing.T) {
+	values := map[string]dbus.Variant{
+		"Address": dbus.MakeVariant("AA:BB:CC:DD:EE:FF"),
+	}
+
+	var adapter Adapter
+	if err := DecodeVariantMap(values, &adapter, "Address"); err != nil {
+		t.Fatalf("DecodeVariantMap returned error: %v", err)
+	}
+	if roles := GetAdapterRoles(adapter); roles != "unknown" {
+		t.Errorf("unexpected roles without the Roles property: %q", roles)
+	}
+
+	values["Roles"] = dbus.MakeVariant([]string{"central", "peripheral"})
+	if err := DecodeVariantMap(values, &adapter, "Address"); err != nil {
+		t.Fatalf("DecodeVariantMap returned error: %v", err)
+	}
+	if roles := GetAdapterRoles(adapter); roles != "central, peripheral" {
+		t.Errorf("unexpected roles: %q", roles)
+	}
+}
@@ -0,0 +1,39 @@
+package bluez
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// rfkill event values for a soft block of all bluetooth devices.
+// https://www.kernel.org/doc/html/latest/driver-api/rfkill.html
+const (
+	rfkillTypeBluetooth = 2
+	rfkillOpChangeAll   = 3
+)
+
+// SetRfkillBlock soft-blocks or unblocks all bluetooth devices via rfkill.
+func SetRfkillBlock(block bool) error {
+	var soft byte
+
+	if block {
+		soft = 1
+	}
+
+	rfkill, err := os.OpenFile("/dev/rfkill", os.O_WRONLY, 0)
+	if err != nil {
+		return errors.Wrap(err, "Cannot open rfkill device")
+	}
+	defer rfkill.Close()
+
+	// The event is laid out as: idx (uint32), type, op, soft, hard (uint8).
+	// Since the idx is ignored for the "change all" operation, its
+	// byte order does not need to be considered.
+	event := []byte{0, 0, 0, 0, rfkillTypeBluetooth, rfkillOpChangeAll, soft, 0}
+	if _, err := rfkill.Write(event); err != nil {
+		return errors.Wrap(err, "Cannot set rfkill state")
+	}
+
+	return nil
+}
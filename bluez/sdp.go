@@ -0,0 +1,39 @@
+package bluez
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// GetServiceRecords queries the service records of a device via SDP.
+// Since bluez does not expose the service records over DBus, the
+// "sdptool" utility is used to query them.
+func GetServiceRecords(address string) ([]string, error) {
+	sdptool, err := exec.LookPath("sdptool")
+	if err != nil {
+		return nil, errors.New("sdptool is not installed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, sdptool, "records", address).CombinedOutput()
+	records := strings.TrimSpace(string(output))
+	if err != nil || strings.HasPrefix(records, "Failed to connect to SDP server") {
+		if records == "" {
+			records = err.Error()
+		}
+
+		return nil, errors.New("Cannot query service records: " + records)
+	}
+
+	if records == "" {
+		return nil, errors.New("No service records were found")
+	}
+
+	return strings.Split(records, "\n"), nil
+}
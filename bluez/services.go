@@ -1,6 +1,14 @@
 package bluez
 
-import "github.com/google/uuid"
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// serviceUUIDFormat is the suffix of the UUIDs which are
+// based on the Bluetooth base UUID.
+const serviceUUIDFormat = "-0000-1000-8000-00805f9b34fb"
 
 // Service Class identifiers.
 // Adapted from:
@@ -961,8 +969,7 @@ var Services = map[uint32]string{
 // Adapted from:
 // https://github.com/bluez/bluez/blob/master/src/shared/util.c#L1189
 func ServiceType(serviceUUID string) string {
-	const serviceUUIDFormat = "-0000-1000-8000-00805f9b34fb"
-	if serviceUUID[8:] != serviceUUIDFormat {
+	if len(serviceUUID) < 8 || serviceUUID[8:] != serviceUUIDFormat {
 		return "Vendor specific"
 	}
 
@@ -979,6 +986,21 @@ func ServiceType(serviceUUID string) string {
 	return serviceType
 }
 
+// ServiceName returns the name of the service UUID from the well-known
+// Bluetooth UUID assignments. If the service is unknown, the UUID is returned.
+func ServiceName(serviceUUID string) string {
+	parsedUUID, err := uuid.Parse(serviceUUID)
+	if err != nil || !strings.HasSuffix(strings.ToLower(serviceUUID), serviceUUIDFormat) {
+		return serviceUUID
+	}
+
+	if serviceType, ok := Services[parsedUUID.ID()]; ok {
+		return serviceType
+	}
+
+	return serviceUUID
+}
+
 // ServiceExists checks if the service class ID exists in the UUID list.
 func ServiceExists(uuidList []string, svclass uint32) bool {
 	for _, serviceUUID := range uuidList {
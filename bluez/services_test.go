@@ -0,0 +1,23 @@
+package bluez
+
+import "testing"
+
+func TestServiceName(t *testing.T) {
+	for _, test := range []struct {
+		serviceUUID, want string
+	}{
+		{"0000110b-0000-1000-8000-00805f9b34fb", "Audio Sink"},
+		{"0000110B-0000-1000-8000-00805F9B34FB", "Audio Sink"},
+		{"0000ffaa-0000-1000-8000-00805f9b34fb", "0000ffaa-0000-1000-8000-00805f9b34fb"},
+		{"81c2e72a-0591-443e-a1ff-05f988593351", "81c2e72a-0591-443e-a1ff-05f988593351"},
+		{"110b", "110b"},
+	} {
+		if name := ServiceName(test.serviceUUID); name != test.want {
+			t.Errorf("ServiceName(%q) = %q, want %q", test.serviceUUID, name, test.want)
+		}
+	}
+
+	if serviceType := ServiceType("110b"); serviceType != "Vendor specific" {
+		t.Errorf("ServiceType(%q) = %q, want %q", "110b", serviceType, "Vendor specific")
+	}
+}
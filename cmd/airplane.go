@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+)
+
+// AirplaneState describes the state of the airplane mode.
+type AirplaneState struct {
+	Adapters []string `json:"adapters"`
+	Rfkill   bool     `json:"rfkill"`
+}
+
+// IsAirplaneModeEnabled returns if the airplane mode is enabled.
+func IsAirplaneModeEnabled() bool {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	state.load()
+
+	return state.Airplane != nil
+}
+
+// SetAirplaneMode enables or disables the airplane mode. When enabled, all adapters are
+// powered off and the powered adapters are remembered, so that they can be powered on
+// again when the airplane mode is disabled. If the "airplane-rfkill" option is enabled,
+// all bluetooth devices are additionally soft-blocked via rfkill.
+func SetAirplaneMode(b *bluez.Bluez, enable bool) error {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	state.load()
+
+	if enable == (state.Airplane != nil) {
+		if enable {
+			return errors.New("Airplane mode is already enabled")
+		}
+
+		return errors.New("Airplane mode is not enabled")
+	}
+
+	var failed []string
+
+	if enable {
+		airplane := AirplaneState{
+			Rfkill: IsPropertyEnabled("airplane-rfkill"),
+		}
+
+		for _, adapter := range b.GetAdapters() {
+			if !adapter.Powered {
+				continue
+			}
+
+			if err := b.SetAdapterProperty(adapter.Path, "Powered", false); err != nil {
+				failed = append(failed, bluez.GetAdapterID(adapter.Path))
+				continue
+			}
+
+			airplane.Adapters = append(airplane.Adapters, adapter.Path)
+		}
+
+		state.Airplane = &airplane
+		state.save()
+
+		if airplane.Rfkill {
+			if err := bluez.SetRfkillBlock(true); err != nil {
+				return err
+			}
+		}
+
+		if failed != nil {
+			return errors.New("Cannot power off " + strings.Join(failed, ", "))
+		}
+
+		return nil
+	}
+
+	if state.Airplane.Rfkill {
+		if err := bluez.SetRfkillBlock(false); err != nil {
+			return err
+		}
+	}
+
+	for _, adapterPath := range state.Airplane.Adapters {
+		var err error
+
+		// The adapter may take some time to become available
+		// after it has been unblocked.
+		for retry := 0; retry < 5; retry++ {
+			if err = b.SetAdapterProperty(adapterPath, "Powered", true); err == nil {
+				break
+			}
+
+			time.Sleep(500 * time.Millisecond)
+		}
+		if err != nil {
+			failed = append(failed, bluez.GetAdapterID(adapterPath))
+		}
+	}
+
+	state.Airplane = nil
+	state.save()
+
+	if failed != nil {
+		return errors.New("Cannot power on " + strings.Join(failed, ", "))
+	}
+
+	return nil
+}
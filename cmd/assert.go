@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/bluetuith/bluez"
+)
+
+// Assertion describes a check against the state of an adapter or a device.
+//
+// The assertions are provided as a comma-separated list of expressions:
+//
+//	expression := ["!"] kind ":" target [operator number]
+//	kind       := "connected" | "paired" | "trusted" | "blocked" | "battery" |
+//	              "powered" | "discoverable" | "pairable" | "discovering"
+//	target     := device address (for device kinds) | adapter ID or address (for adapter kinds)
+//	operator   := "==" | "!=" | ">=" | "<=" | ">" | "<"
+//
+// The "battery" kind requires an operator and a number, for example 'battery:AA:BB:CC:DD:EE:FF>=20',
+// and the other kinds do not accept them. A "!" prefix negates the assertion, for example
+// '!blocked:AA:BB:CC:DD:EE:FF'.
+//
+// If any assertion fails, the exit status is that of the first failed assertion:
+// 2 if its target was not found, 10 to 14 for the device kinds and 20 to 23 for the
+// adapter kinds, in the order listed above.
+type Assertion struct {
+	Kind   string
+	Target string
+	Negate bool
+
+	Operator string
+	Value    int
+}
+
+// assertKind describes a kind of assertion.
+type assertKind struct {
+	adapter bool
+	numeric bool
+	status  int
+}
+
+// The exit statuses of failed assertions. If the target of an assertion is not found,
+// assertExitNotFound is used, otherwise the exit status of the assertion kind is used.
+const (
+	assertExitNotFound = 2
+)
+
+var assertKinds = map[string]assertKind{
+	"connected": {status: 10},
+	"paired":    {status: 11},
+	"trusted":   {status: 12},
+	"blocked":   {status: 13},
+	"battery":   {numeric: true, status: 14},
+
+	"powered":      {adapter: true, status: 20},
+	"discoverable": {adapter: true, status: 21},
+	"pairable":     {adapter: true, status: 22},
+	"discovering":  {adapter: true, status: 23},
+}
+
+var assertOperators = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// ParseAssertions parses a comma-separated list of assertion expressions.
+func ParseAssertions(expressions string) ([]Assertion, error) {
+	var assertions []Assertion
+
+	for _, expression := range strings.Split(expressions, ",") {
+		expression = strings.TrimSpace(expression)
+		if expression == "" {
+			continue
+		}
+
+		assertion, err := parseAssertion(expression)
+		if err != nil {
+			return nil, err
+		}
+
+		assertions = append(assertions, assertion)
+	}
+
+	if assertions == nil {
+		return nil, fmt.Errorf("No assertions were provided")
+	}
+
+	return assertions, nil
+}
+
+// parseAssertion parses a single assertion expression.
+func parseAssertion(expression string) (Assertion, error) {
+	var assertion Assertion
+
+	if strings.HasPrefix(expression, "!") {
+		assertion.Negate = true
+		expression = expression[1:]
+	}
+
+	kindName, target, ok := strings.Cut(expression, ":")
+	if !ok {
+		return assertion, fmt.Errorf("Provided assertion '%s' is not in the 'kind:target' format", expression)
+	}
+
+	kind, ok := assertKinds[kindName]
+	if !ok {
+		return assertion, fmt.Errorf("Provided assertion kind '%s' is incorrect", kindName)
+	}
+
+	assertion.Kind = kindName
+
+	if index := strings.IndexAny(target, "=!<>"); index >= 0 {
+		for _, operator := range assertOperators {
+			if !strings.HasPrefix(target[index:], operator) {
+				continue
+			}
+
+			value, err := strconv.Atoi(strings.TrimSpace(target[index+len(operator):]))
+			if err != nil {
+				return assertion, fmt.Errorf("Provided value in assertion '%s' is not a number", expression)
+			}
+
+			assertion.Operator = operator
+			assertion.Value = value
+			break
+		}
+		if assertion.Operator == "" {
+			return assertion, fmt.Errorf("Provided operator in assertion '%s' is incorrect", expression)
+		}
+
+		target = target[:index]
+	}
+
+	assertion.Target = strings.TrimSpace(target)
+	if assertion.Target == "" {
+		return assertion, fmt.Errorf("No target was provided in assertion '%s'", expression)
+	}
+
+	switch {
+	case kind.numeric && assertion.Operator == "":
+		return assertion, fmt.Errorf("Assertion '%s' requires an operator and a number", expression)
+
+	case !kind.numeric && assertion.Operator != "":
+		return assertion, fmt.Errorf("Assertion '%s' does not accept an operator", expression)
+	}
+
+	return assertion, nil
+}
+
+// String returns the expression of the assertion.
+func (a Assertion) String() string {
+	var negate string
+	if a.Negate {
+		negate = "!"
+	}
+
+	expression := negate + a.Kind + ":" + a.Target
+	if a.Operator != "" {
+		expression += a.Operator + strconv.Itoa(a.Value)
+	}
+
+	return expression
+}
+
+// Status returns the exit status to use if the assertion fails.
+func (a Assertion) Status() int {
+	return assertKinds[a.Kind].status
+}
+
+// EvaluateDevice evaluates the assertion against the state of a device.
+func (a Assertion) EvaluateDevice(device bluez.Device) bool {
+	var result bool
+
+	switch a.Kind {
+	case "connected":
+		result = device.Connected
+
+	case "paired":
+		result = device.Paired
+
+	case "trusted":
+		result = device.Trusted
+
+	case "blocked":
+		result = device.Blocked
+
+	case "battery":
+		result = device.BatteryPercentage >= 0 && compareAssertValue(device.BatteryPercentage, a.Operator, a.Value)
+	}
+
+	return result != a.Negate
+}
+
+// EvaluateAdapter evaluates the assertion against the state of an adapter.
+func (a Assertion) EvaluateAdapter(adapter bluez.Adapter) bool {
+	var result bool
+
+	switch a.Kind {
+	case "powered":
+		result = adapter.Powered
+
+	case "discoverable":
+		result = adapter.Discoverable
+
+	case "pairable":
+		result = adapter.Pairable
+
+	case "discovering":
+		result = adapter.Discovering
+	}
+
+	return result != a.Negate
+}
+
+// compareAssertValue compares the value with the assertion value using the operator.
+func compareAssertValue(value int, operator string, assertValue int) bool {
+	switch operator {
+	case "==":
+		return value == assertValue
+
+	case "!=":
+		return value != assertValue
+
+	case ">=":
+		return value >= assertValue
+
+	case "<=":
+		return value <= assertValue
+
+	case ">":
+		return value > assertValue
+
+	case "<":
+		return value < assertValue
+	}
+
+	return false
+}
+
+func cmdOptionAssert(b *bluez.Bluez) {
+	optionAssert := GetProperty("assert")
+	if optionAssert == "" {
+		return
+	}
+
+	assertions, err := ParseAssertions(optionAssert)
+	if err != nil {
+		PrintError("Cannot parse assertions", err)
+	}
+
+	status, passedCount := 0, 0
+
+	for _, assertion := range assertions {
+		found, passed := false, false
+
+		if assertKinds[assertion.Kind].adapter {
+			for _, adapter := range b.GetAdapters() {
+				if !strings.EqualFold(bluez.GetAdapterID(adapter.Path), assertion.Target) &&
+					!strings.EqualFold(adapter.Address, assertion.Target) {
+					continue
+				}
+
+				found, passed = true, assertion.EvaluateAdapter(adapter)
+				break
+			}
+		} else {
+			for _, device := range b.GetAllDevices() {
+				if !strings.EqualFold(device.Address, assertion.Target) {
+					continue
+				}
+
+				found = true
+				if assertion.EvaluateDevice(device) {
+					passed = true
+					break
+				}
+			}
+		}
+
+		switch {
+		case !found:
+			PrintWarn(fmt.Sprintf("%s: target not found", assertion))
+			if status == 0 {
+				status = assertExitNotFound
+			}
+
+		case !passed:
+			PrintWarn(fmt.Sprintf("%s: failed", assertion))
+			if status == 0 {
+				status = assertion.Status()
+			}
+
+		default:
+			Print(fmt.Sprintf("[+] %s: passed", assertion))
+			passedCount++
+		}
+	}
+
+	Print(fmt.Sprintf("%d of %d assertions passed.", passedCount, len(assertions)), status)
+}
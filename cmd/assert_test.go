@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/darkhz/bluetuith/bluez"
+)
+
+func TestParseAssertions(t *testing.T) {
+	assertions, err := ParseAssertions("powered:hci0, !blocked:AA:BB:CC:DD:EE:FF,battery:AA:BB:CC:DD:EE:FF>=20")
+	if err != nil {
+		t.Fatalf("ParseAssertions() returned an error: %v", err)
+	}
+
+	expected := []Assertion{
+		{Kind: "powered", Target: "hci0"},
+		{Kind: "blocked", Target: "AA:BB:CC:DD:EE:FF", Negate: true},
+		{Kind: "battery", Target: "AA:BB:CC:DD:EE:FF", Operator: ">=", Value: 20},
+	}
+	if len(assertions) != len(expected) {
+		t.Fatalf("ParseAssertions() = %v, want %v", assertions, expected)
+	}
+
+	for i := range expected {
+		if assertions[i] != expected[i] {
+			t.Errorf("assertion %d = %+v, want %+v", i, assertions[i], expected[i])
+		}
+	}
+
+	for _, invalid := range []string{
+		"",
+		"connected",
+		"unknown:hci0",
+		"battery:AA:BB:CC:DD:EE:FF",
+		"connected:AA:BB:CC:DD:EE:FF>1",
+		"battery:AA:BB:CC:DD:EE:FF=>20",
+		"battery:AA:BB:CC:DD:EE:FF>=high",
+		"powered:",
+	} {
+		if _, err := ParseAssertions(invalid); err == nil {
+			t.Errorf("ParseAssertions(%q) did not return an error", invalid)
+		}
+	}
+}
+
+func TestEvaluateAssertions(t *testing.T) {
+	device := bluez.Device{Connected: true, BatteryPercentage: 40}
+	adapter := bluez.Adapter{Powered: true}
+
+	tests := []struct {
+		expression string
+		passed     bool
+	}{
+		{"connected:AA", true},
+		{"!connected:AA", false},
+		{"paired:AA", false},
+		{"battery:AA>=20", true},
+		{"battery:AA<20", false},
+		{"battery:AA!=40", false},
+		{"powered:hci0", true},
+		{"!discoverable:hci0", true},
+	}
+
+	for _, test := range tests {
+		assertions, err := ParseAssertions(test.expression)
+		if err != nil {
+			t.Fatalf("ParseAssertions(%q) returned an error: %v", test.expression, err)
+		}
+
+		assertion := assertions[0]
+
+		passed := assertion.EvaluateDevice(device)
+		if assertKinds[assertion.Kind].adapter {
+			passed = assertion.EvaluateAdapter(adapter)
+		}
+
+		if passed != test.passed {
+			t.Errorf("%s evaluated to %v, want %v", test.expression, passed, test.passed)
+		}
+	}
+}
@@ -7,18 +7,57 @@ var Version string
 
 // Init initializes the application.
 func Init(bluez *bluez.Bluez) {
+	cmdOptionLogLevel()
+	cmdOptionLogFile(bluez)
+	cmdOptionDevices()
+
 	cmdOptionListAdapters(bluez)
+	cmdOptionFind(bluez)
+	cmdOptionAssert(bluez)
+	cmdOptionWizard(bluez)
 	cmdOptionAdapter(bluez)
+	cmdOptionListDevices(bluez)
+	cmdOptionAdapterInfo(bluez)
+	cmdOptionMaxConcurrentConnections(bluez)
 	cmdOptionConnectBDAddr(bluez)
+	cmdOptionConnectionProfiles()
+	cmdOptionProfile(bluez)
+	cmdOptionAwaitConnect(bluez)
+	cmdOptionMaintainBDAddr(bluez)
+	cmdOptionSendFile(bluez)
+	cmdOptionExportDevices(bluez)
+	cmdOptionImportDevices(bluez)
+	cmdOptionAirplane(bluez)
+	cmdOptionDiscoverable(bluez)
 	cmdOptionAdapterStates()
+	cmdOptionDiscoveryFilter()
+	cmdOptionDiscoverablePresets()
+	cmdOptionDeviceSort()
+	cmdOptionStatusbarSegments()
+	cmdOptionRemoveProtection()
+	cmdOptionRSSI()
+	cmdOptionPresenceCheck()
+	cmdOptionScanRefresh()
+	cmdOptionManualDisconnectCooldown()
+	cmdOptionAutoConnect()
+	cmdOptionPlayerSeekStep()
+	cmdOptionAutoAcceptPairPrefixes()
 
 	validateKeybindings()
 	cmdOptionGenerate()
+	cmdOptionThemePreset()
 	cmdOptionTheme()
 
 	cmdOptionGsm()
 
 	cmdOptionReceiveDir()
+	cmdOptionReceiveMaxSize()
+	cmdOptionReceiveConflict()
+	cmdOptionIPCSocket()
+	cmdOptionMaxConcurrentTransfers()
+	cmdOptionTransferRetries()
+
+	cmdOptionCollectDiagnostics(bluez)
 }
 
 // Parse parses the command-line parameters.
@@ -26,5 +65,11 @@ func Parse() {
 	config.setup()
 	parse()
 
+	cmdOptionNoColor()
+	cmdOptionDBusSystemAddress()
+	cmdOptionAgentCapability()
+	cmdOptionAgentTimeout()
 	cmdOptionVersion()
+	cmdOptionConfigKeys()
+	cmdOptionDumpKeybindings()
 }
@@ -6,9 +6,16 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
 
+	"github.com/darkhz/bluetuith/bluez"
 	"github.com/hjson/hjson-go/v4"
+	koanfhjson "github.com/knadh/koanf/parsers/hjson"
+	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
 )
 
@@ -16,6 +23,11 @@ import (
 type Config struct {
 	path string
 
+	// provider is the configuration file provider, and themeFlag
+	// is set if the theme was specified on the command-line.
+	provider  *file.File
+	themeFlag bool
+
 	*koanf.Koanf
 }
 
@@ -28,6 +40,14 @@ type ConfigDir struct {
 
 var config Config
 
+// runtimeProperties holds the properties which were set with SetRuntimeProperty.
+var runtimeProperties = struct {
+	values map[string]string
+	lock   sync.RWMutex
+}{
+	values: make(map[string]string),
+}
+
 // setup checks for the config directory,
 // and creates one if it doesn't exist.
 func (c *Config) setup() {
@@ -93,6 +113,38 @@ func (c *Config) setup() {
 	return
 }
 
+// configWatchDelay is the duration to wait before watching the configuration
+// file again, after it was replaced (for example, by an editor saving it).
+const configWatchDelay = 500 * time.Millisecond
+
+// WatchThemeConfig watches the configuration file, and calls the provided function
+// with the parsed theme configuration each time the file changes. The file is
+// not watched if the theme was specified on the command-line.
+func WatchThemeConfig(reload func(themeConfig map[string]interface{}, err error)) error {
+	if config.provider == nil || config.themeFlag {
+		return nil
+	}
+
+	return config.provider.Watch(func(_ interface{}, err error) {
+		if err != nil {
+			// The watch stops if the file was replaced, so watch the new file.
+			time.Sleep(configWatchDelay)
+			if watchErr := WatchThemeConfig(reload); watchErr != nil {
+				reload(nil, fmt.Errorf("Stopped watching the configuration file: %s", err.Error()))
+				return
+			}
+		}
+
+		k := koanf.New(".")
+		if err := k.Load(config.provider, koanfhjson.Parser()); err != nil {
+			reload(nil, err)
+			return
+		}
+
+		reload(parseThemeOption(k.Get("theme")))
+	})
+}
+
 // ConfigPath returns the absolute path for the given configType.
 func ConfigPath(configType string) (string, error) {
 	confPath := filepath.Join(config.path, configType)
@@ -110,6 +162,14 @@ func ConfigPath(configType string) (string, error) {
 
 // GetProperty returns the value for the given property.
 func GetProperty(property string) string {
+	runtimeProperties.lock.RLock()
+	value, ok := runtimeProperties.values[property]
+	runtimeProperties.lock.RUnlock()
+
+	if ok {
+		return value
+	}
+
 	return config.String(property)
 }
 
@@ -118,11 +178,197 @@ func GetPropertyMap(property string) map[string]string {
 	return config.StringMap(property)
 }
 
+// GetDiscoverablePreset returns the duration of the discoverable preset
+// at the provided index. A zero duration indicates a permanent preset.
+func GetDiscoverablePreset(index int) time.Duration {
+	presets := strings.Split(GetProperty("discoverable-presets"), ",")
+	if index >= len(presets) {
+		return 0
+	}
+
+	duration, _ := time.ParseDuration(presets[index])
+
+	return duration
+}
+
+// GetDiscoveryFilter returns the discovery filter from the "discovery-filter" option.
+func GetDiscoveryFilter() bluez.DiscoveryFilter {
+	filter, _ := bluez.ParseDiscoveryFilter(GetProperty("discovery-filter"))
+
+	return filter
+}
+
+// GetReceiveMaxSize returns the maximum size in bytes of a received file,
+// from the "receive-max-size" option. A size of 0 indicates no limit.
+func GetReceiveMaxSize() uint64 {
+	size, _ := strconv.ParseUint(GetProperty("receive-max-size"), 10, 64)
+
+	return size
+}
+
+// GetRSSIBars returns the number of signal bars, from 0 to 4, for the
+// provided signal strength, based on the "rssi-thresholds" option.
+func GetRSSIBars(rssi int16) int {
+	var bars int
+
+	for _, threshold := range strings.Split(GetProperty("rssi-thresholds"), ",") {
+		value, err := strconv.ParseInt(threshold, 10, 16)
+		if err != nil || rssi < int16(value) {
+			break
+		}
+
+		bars++
+	}
+
+	return bars
+}
+
+// GetDeviceProperty returns the value for the given property of a device,
+// as specified in the "devices" section of the configuration.
+func GetDeviceProperty(address, property string) string {
+	return config.String("devices." + strings.ToUpper(address) + "." + property)
+}
+
+// IsDevicePropertyEnabled returns if a property of a device is enabled.
+func IsDevicePropertyEnabled(address, property string) bool {
+	enabled, _ := strconv.ParseBool(GetDeviceProperty(address, property))
+
+	return enabled
+}
+
+// GetDeviceProfileOrder returns the profile UUIDs of a device, in the order
+// they must be connected. If no order is specified, nil is returned.
+func GetDeviceProfileOrder(address string) []string {
+	var uuids []string
+
+	for _, profileUUID := range strings.Split(GetDeviceProperty(address, "profile-order"), ",") {
+		profileUUID = strings.TrimSpace(profileUUID)
+		if profileUUID == "" {
+			continue
+		}
+
+		uuids = append(uuids, strings.ToLower(profileUUID))
+	}
+
+	return uuids
+}
+
+// GetDeviceAdapterFallback returns the adapter IDs to attempt connecting to a device with,
+// in order, if connecting with its preferred adapter fails. If no adapters are specified,
+// nil is returned.
+func GetDeviceAdapterFallback(address string) []string {
+	var adapterIDs []string
+
+	for _, adapterID := range strings.Split(GetDeviceProperty(address, "adapter-fallback"), ",") {
+		adapterID = strings.TrimSpace(adapterID)
+		if adapterID == "" {
+			continue
+		}
+
+		adapterIDs = append(adapterIDs, adapterID)
+	}
+
+	return adapterIDs
+}
+
+// GetDeviceConnectDirection returns the connection direction of a device,
+// which is either "outgoing" (the default), "incoming" or "both".
+func GetDeviceConnectDirection(address string) string {
+	direction := GetDeviceProperty(address, "connect-direction")
+	if direction == "" {
+		return "outgoing"
+	}
+
+	return direction
+}
+
+// IsPairAutoAccepted returns if pairing requests from the device with the provided
+// address are accepted without confirmation, according to the "auto-accept-pair"
+// and "auto-accept-pair-prefixes" options.
+func IsPairAutoAccepted(address string) bool {
+	if !IsPropertyEnabled("auto-accept-pair") {
+		return false
+	}
+
+	prefixes := GetProperty("auto-accept-pair-prefixes")
+	if strings.TrimSpace(prefixes) == "" {
+		return true
+	}
+
+	for _, prefix := range strings.Split(prefixes, ",") {
+		prefix = strings.TrimSpace(prefix)
+		if prefix != "" && strings.HasPrefix(strings.ToUpper(address), strings.ToUpper(prefix)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetDeviceTags returns the tags of a device. Tags which were set from
+// the application are stored in the state file, and take precedence over
+// the tags from the configuration.
+func GetDeviceTags(address string) []string {
+	if tags, ok := getStateDeviceTags(address); ok {
+		return tags
+	}
+
+	var tags []string
+
+	for _, tag := range strings.Split(GetDeviceProperty(address, "tags"), ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// HasDeviceTag returns if a device has the provided tag.
+func HasDeviceTag(address, tag string) bool {
+	for _, deviceTag := range GetDeviceTags(address) {
+		if strings.EqualFold(deviceTag, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateTag checks if the tag name is valid.
+func ValidateTag(tag string) error {
+	if tag == "" {
+		return fmt.Errorf("The tag name must not be empty")
+	}
+
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return fmt.Errorf("The tag name '%s' must only contain letters, digits, '-' or '_'", tag)
+		}
+	}
+
+	return nil
+}
+
 // AddProperty adds a property and its value to the properties store.
 func AddProperty(property string, value interface{}) {
 	config.Set(property, value)
 }
 
+// SetRuntimeProperty sets a property while the application is running.
+// The configuration cannot be modified while it is read concurrently, so
+// the property is stored separately, and takes precedence over the value
+// from the configuration in GetProperty.
+func SetRuntimeProperty(property, value string) {
+	runtimeProperties.lock.Lock()
+	defer runtimeProperties.lock.Unlock()
+
+	runtimeProperties.values[property] = value
+}
+
 // IsPropertyEnabled returns if a property is enabled.
 func IsPropertyEnabled(property string) bool {
 	return config.Bool(property)
@@ -136,6 +382,13 @@ func generate() {
 	genMap := make(map[string]interface{})
 
 	for _, option := range options {
+		// The SIM PIN and the control socket token are secrets, and the
+		// options which perform one-time actions would be repeated on every
+		// launch, so they are never written to the configuration.
+		if option.IsAction || option.Name == "gsm-pin" || option.Name == "control-socket-token" {
+			continue
+		}
+
 		if !option.IsBoolean {
 			genMap[option.Name] = config.Get(option.Name)
 		}
@@ -153,6 +406,18 @@ func generate() {
 	}
 	genMap["theme"] = theme
 
+	devices := config.Get("devices")
+	if devices == nil {
+		devices = make(map[string]interface{})
+	}
+	genMap["devices"] = devices
+
+	connectionProfiles := config.Get("connection-profiles")
+	if connectionProfiles == nil {
+		connectionProfiles = make(map[string]interface{})
+	}
+	genMap["connection-profiles"] = connectionProfiles
+
 	data, err := hjson.Marshal(genMap)
 	if err != nil {
 		PrintError(err.Error())
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/knadh/koanf/v2"
+)
+
+func TestGetRSSIBars(t *testing.T) {
+	config.Koanf = koanf.New(".")
+	AddProperty("rssi-thresholds", "-90,-80,-70,-60")
+
+	tests := []struct {
+		rssi int16
+		bars int
+	}{
+		{-100, 0},
+		{-90, 1},
+		{-85, 1},
+		{-70, 3},
+		{-60, 4},
+		{-30, 4},
+	}
+
+	for _, test := range tests {
+		if bars := GetRSSIBars(test.rssi); bars != test.bars {
+			t.Errorf("GetRSSIBars(%d) = %d, want %d", test.rssi, bars, test.bars)
+		}
+	}
+}
+
+func TestGetDeviceProfileOrder(t *testing.T) {
+	config.Koanf = koanf.New(".")
+	AddProperty("devices", map[string]interface{}{
+		"AA:BB:CC:DD:EE:FF": map[string]interface{}{
+			"profile-order": "0000111E-0000-1000-8000-00805F9B34FB, 0000110b-0000-1000-8000-00805f9b34fb",
+		},
+	})
+
+	order := GetDeviceProfileOrder("aa:bb:cc:dd:ee:ff")
+	want := []string{
+		"0000111e-0000-1000-8000-00805f9b34fb",
+		"0000110b-0000-1000-8000-00805f9b34fb",
+	}
+	if len(order) != len(want) || order[0] != want[0] || order[1] != want[1] {
+		t.Errorf("GetDeviceProfileOrder() = %v, want %v", order, want)
+	}
+
+	if order := GetDeviceProfileOrder("11:22:33:44:55:66"); order != nil {
+		t.Errorf("GetDeviceProfileOrder() = %v, want nil", order)
+	}
+}
+
+func TestGetDeviceAdapterFallback(t *testing.T) {
+	config.Koanf = koanf.New(".")
+	AddProperty("devices", map[string]interface{}{
+		"AA:BB:CC:DD:EE:FF": map[string]interface{}{
+			"adapter-fallback": "hci1, hci0,",
+		},
+	})
+
+	fallback := GetDeviceAdapterFallback("AA:BB:CC:DD:EE:FF")
+	if len(fallback) != 2 || fallback[0] != "hci1" || fallback[1] != "hci0" {
+		t.Errorf("GetDeviceAdapterFallback() = %v, want [hci1 hci0]", fallback)
+	}
+
+	if fallback := GetDeviceAdapterFallback("11:22:33:44:55:66"); fallback != nil {
+		t.Errorf("GetDeviceAdapterFallback() = %v, want nil", fallback)
+	}
+}
+
+func TestGetConnectionProfileFilter(t *testing.T) {
+	config.Koanf = koanf.New(".")
+	AddProperty("connection-profiles", map[string]interface{}{
+		"beacon-hunt": map[string]interface{}{
+			"discovery-filter": "transport:le,rssi:-70",
+		},
+		"headset": map[string]interface{}{
+			"AA:BB:CC:DD:EE:FF": "",
+		},
+	})
+
+	filter, ok := GetConnectionProfileFilter("beacon-hunt")
+	if !ok || filter.Transport != "le" || filter.RSSI != -70 {
+		t.Errorf("GetConnectionProfileFilter() = %+v, %v, want an LE filter with RSSI -70", filter, ok)
+	}
+
+	if _, ok := GetConnectionProfileFilter("headset"); ok {
+		t.Errorf("GetConnectionProfileFilter() returned a filter for a profile without one")
+	}
+}
+
+func TestSetDeviceTags(t *testing.T) {
+	config.path = t.TempDir()
+	config.Koanf = koanf.New(".")
+	state = State{}
+
+	AddProperty("devices", map[string]interface{}{
+		"AA:BB:CC:DD:EE:FF": map[string]interface{}{
+			"tags": "loaner",
+		},
+	})
+
+	if !HasDeviceTag("aa:bb:cc:dd:ee:ff", "loaner") {
+		t.Errorf("GetDeviceTags() = %v, want the configured tags", GetDeviceTags("aa:bb:cc:dd:ee:ff"))
+	}
+
+	if err := SetDeviceTags("aa:bb:cc:dd:ee:ff", []string{"work", "audio"}); err != nil {
+		t.Fatalf("SetDeviceTags() returned error: %v", err)
+	}
+
+	if !HasDeviceTag("AA:BB:CC:DD:EE:FF", "Audio") || HasDeviceTag("AA:BB:CC:DD:EE:FF", "loaner") {
+		t.Errorf("GetDeviceTags() = %v", GetDeviceTags("AA:BB:CC:DD:EE:FF"))
+	}
+
+	statePath, err := ConfigPath("state.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "AA:BB:CC:DD:EE:FF") {
+		t.Errorf("state file does not contain the device tags:\n%s", data)
+	}
+
+	if err := SetDeviceTags("AA:BB:CC:DD:EE:FF", nil); err != nil {
+		t.Fatalf("SetDeviceTags() returned error: %v", err)
+	}
+	if tags := GetDeviceTags("AA:BB:CC:DD:EE:FF"); tags != nil {
+		t.Errorf("GetDeviceTags() = %v after clearing the tags", tags)
+	}
+
+	if err := SetDeviceTags("AA:BB:CC:DD:EE:FF", []string{"bad tag"}); err == nil {
+		t.Error("SetDeviceTags() did not return an error for an invalid tag")
+	}
+}
+
+func TestGetDeviceConnectDirection(t *testing.T) {
+	config.Koanf = koanf.New(".")
+	AddProperty("devices", map[string]interface{}{
+		"AA:BB:CC:DD:EE:FF": map[string]interface{}{
+			"connect-direction": "incoming",
+		},
+	})
+
+	if direction := GetDeviceConnectDirection("aa:bb:cc:dd:ee:ff"); direction != "incoming" {
+		t.Errorf("GetDeviceConnectDirection() = %s, want incoming", direction)
+	}
+
+	if direction := GetDeviceConnectDirection("11:22:33:44:55:66"); direction != "outgoing" {
+		t.Errorf("GetDeviceConnectDirection() = %s, want outgoing", direction)
+	}
+
+	if err := validateConnectDirection("sideways"); err == nil {
+		t.Error("validateConnectDirection() did not return an error")
+	}
+}
+
+func TestIsPairAutoAccepted(t *testing.T) {
+	config.Koanf = koanf.New(".")
+	if IsPairAutoAccepted("AA:BB:CC:DD:EE:FF") {
+		t.Errorf("IsPairAutoAccepted() = true without the auto-accept-pair option")
+	}
+
+	AddProperty("auto-accept-pair", true)
+	if !IsPairAutoAccepted("AA:BB:CC:DD:EE:FF") {
+		t.Errorf("IsPairAutoAccepted() = false without any address prefixes")
+	}
+
+	AddProperty("auto-accept-pair-prefixes", "aa:bb:cc, 11:22")
+	for address, want := range map[string]bool{
+		"AA:BB:CC:DD:EE:FF": true,
+		"11:22:33:44:55:66": true,
+		"AA:BB:CD:DD:EE:FF": false,
+	} {
+		if accepted := IsPairAutoAccepted(address); accepted != want {
+			t.Errorf("IsPairAutoAccepted(%s) = %v, want %v", address, accepted, want)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/google/uuid"
+)
+
+// connectionProfileFilter is the key of the discovery filter within a connection profile.
+const connectionProfileFilter = "discovery-filter"
+
+// ConnectionProfileDevice describes a device within a connection profile,
+// along with the service profiles to connect to. If no service profiles
+// are specified, the device is connected normally.
+type ConnectionProfileDevice struct {
+	Device bluez.Device
+	UUIDs  []string
+}
+
+// GetConnectionProfiles returns the names of the connection profiles,
+// as specified in the "connection-profiles" section of the configuration.
+func GetConnectionProfiles() []string {
+	profiles := config.MapKeys("connection-profiles")
+	sort.Strings(profiles)
+
+	return profiles
+}
+
+// GetConnectionProfile returns the devices of the connection profile.
+// An error is returned if the profile does not exist, or if any of its
+// devices cannot be found on any of the adapters.
+func GetConnectionProfile(b *bluez.Bluez, name string) ([]ConnectionProfileDevice, error) {
+	profile := config.StringMap("connection-profiles." + name)
+	if len(profile) == 0 {
+		return nil, fmt.Errorf("Connection profile '%s' does not exist", name)
+	}
+
+	addresses := make([]string, 0, len(profile))
+	for address := range profile {
+		if address == connectionProfileFilter {
+			continue
+		}
+
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	devices := make([]ConnectionProfileDevice, 0, len(addresses))
+
+	for _, address := range addresses {
+		var profileDevice ConnectionProfileDevice
+
+		for _, device := range b.GetAllDevices() {
+			if device.Address == address {
+				profileDevice.Device = device
+				break
+			}
+		}
+		if profileDevice.Device.Path == "" {
+			return nil, fmt.Errorf("Device '%s' of connection profile '%s' was not found", address, name)
+		}
+
+		if uuids := profile[address]; uuids != "" {
+			profileDevice.UUIDs = strings.Split(uuids, ",")
+		}
+
+		devices = append(devices, profileDevice)
+	}
+
+	return devices, nil
+}
+
+// GetConnectionProfileFilter returns the discovery filter of the connection profile.
+// If the profile does not specify a discovery filter, false is returned.
+func GetConnectionProfileFilter(name string) (bluez.DiscoveryFilter, bool) {
+	value := config.String("connection-profiles." + name + "." + connectionProfileFilter)
+	if value == "" {
+		return bluez.DiscoveryFilter{}, false
+	}
+
+	filter, err := bluez.ParseDiscoveryFilter(value)
+
+	return filter, err == nil && !filter.IsEmpty()
+}
+
+// Connect connects the device, or its service profiles if they are specified.
+// If no profiles are specified, the device's profile order is used, if any.
+func (c ConnectionProfileDevice) Connect(b *bluez.Bluez) error {
+	if GetDeviceConnectDirection(c.Device.Address) == "incoming" {
+		return fmt.Errorf("%s only accepts incoming connections", c.Device.Name)
+	}
+
+	if c.UUIDs == nil {
+		c.UUIDs = GetDeviceProfileOrder(c.Device.Address)
+	}
+	if c.UUIDs == nil {
+		return b.Connect(c.Device.Path)
+	}
+
+	for _, profileUUID := range c.UUIDs {
+		if err := b.ConnectProfile(c.Device.Path, profileUUID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func cmdOptionConnectionProfiles() {
+	if !config.Exists("connection-profiles") {
+		return
+	}
+
+	profiles := make(map[string]interface{})
+
+	for _, name := range config.MapKeys("connection-profiles") {
+		devices := make(map[string]interface{})
+
+		for _, address := range config.MapKeys("connection-profiles." + name) {
+			if address == connectionProfileFilter {
+				value := config.String("connection-profiles." + name + "." + address)
+				if _, err := bluez.ParseDiscoveryFilter(value); err != nil {
+					PrintError(
+						fmt.Sprintf(
+							"Connection profiles: Provided discovery filter '%s' for profile '%s' is incorrect: %s.",
+							value, name, err.Error(),
+						),
+					)
+				}
+
+				devices[address] = value
+
+				continue
+			}
+
+			if mac, err := net.ParseMAC(address); err != nil || len(mac) != 6 {
+				PrintError(
+					fmt.Sprintf(
+						"Connection profiles: Provided device address '%s' for profile '%s' is incorrect.",
+						address, name,
+					),
+				)
+			}
+
+			var uuids []string
+
+			value := config.String("connection-profiles." + name + "." + address)
+			for _, profileUUID := range strings.Split(value, ",") {
+				profileUUID = strings.TrimSpace(profileUUID)
+				if profileUUID == "" {
+					continue
+				}
+
+				if _, err := uuid.Parse(profileUUID); err != nil {
+					PrintError(
+						fmt.Sprintf(
+							"Connection profiles: Provided profile UUID '%s' for device '%s' in profile '%s' is incorrect.",
+							profileUUID, address, name,
+						),
+					)
+				}
+
+				uuids = append(uuids, strings.ToLower(profileUUID))
+			}
+
+			devices[strings.ToUpper(address)] = strings.Join(uuids, ",")
+		}
+
+		profiles[name] = devices
+	}
+
+	config.Delete("connection-profiles")
+	AddProperty("connection-profiles", profiles)
+}
+
+func cmdOptionProfile(b *bluez.Bluez) {
+	optionProfile := GetProperty("profile")
+	if optionProfile == "" {
+		return
+	}
+
+	devices, err := GetConnectionProfile(b, optionProfile)
+	if err != nil {
+		PrintError(err.Error())
+	}
+
+	if _, ok := GetConnectionProfileFilter(optionProfile); ok {
+		PrintWarn(fmt.Sprintf("The discovery filter of connection profile '%s' is only applied within the interface", optionProfile))
+	}
+
+	var failed bool
+
+	for _, device := range devices {
+		if err := device.Connect(b); err != nil {
+			PrintWarn(fmt.Sprintf("Could not connect to '%s' (%s): %s", device.Device.Name, device.Device.Address, err.Error()))
+			failed = true
+
+			continue
+		}
+
+		Print(fmt.Sprintf("Connected to '%s' (%s)", device.Device.Name, device.Device.Address))
+	}
+
+	if failed {
+		PrintError(fmt.Sprintf("Could not connect to all devices of connection profile '%s'", optionProfile))
+	}
+
+	Print(fmt.Sprintf("Connected to all devices of connection profile '%s'", optionProfile), 0)
+}
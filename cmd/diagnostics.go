@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/godbus/dbus/v5"
+)
+
+// diagnosticsFile describes a file in the diagnostics archive.
+type diagnosticsFile struct {
+	Name string
+	Data []byte
+}
+
+func cmdOptionCollectDiagnostics(b *bluez.Bluez) {
+	optionCollectDiagnostics := GetProperty("collect-diagnostics")
+	if optionCollectDiagnostics == "" {
+		return
+	}
+
+	files := []diagnosticsFile{
+		{"version.txt", diagnosticsVersion()},
+		{"config.json", diagnosticsConfig()},
+		{"adapters.json", diagnosticsAdapters(b)},
+		{"devices.json", diagnosticsDevices(b)},
+	}
+
+	if logPath, err := logFilePath(); err == nil {
+		if data, err := os.ReadFile(logPath); err == nil {
+			files = append(files, diagnosticsFile{"bluetuith.log", data})
+		}
+	}
+
+	if err := writeDiagnostics(optionCollectDiagnostics, files); err != nil {
+		PrintError(fmt.Sprintf("Could not write diagnostics to '%s': %s", optionCollectDiagnostics, err.Error()))
+	}
+
+	Print("Diagnostics written to "+optionCollectDiagnostics, 0)
+}
+
+// diagnosticsConfig returns the configuration, with the secrets redacted.
+func diagnosticsConfig() []byte {
+	raw := config.Raw()
+
+	for _, secret := range []string{"gsm-pin", "control-socket-token"} {
+		if value, ok := raw[secret].(string); ok && value != "" {
+			raw[secret] = "<redacted>"
+		}
+	}
+
+	return diagnosticsJSON(raw)
+}
+
+// diagnosticsVersion returns the versions of bluetuith and BlueZ.
+func diagnosticsVersion() []byte {
+	bluezVersion := "unknown"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, daemon := range []string{"bluetoothd", "/usr/lib/bluetooth/bluetoothd", "/usr/libexec/bluetooth/bluetoothd"} {
+		output, err := exec.CommandContext(ctx, daemon, "--version").Output()
+		if err == nil {
+			bluezVersion = strings.TrimSpace(string(output))
+			break
+		}
+	}
+
+	return []byte(fmt.Sprintf("bluetuith: %s\nbluez: %s\n", Version, bluezVersion))
+}
+
+// diagnosticsAdapters returns the properties of all adapters.
+func diagnosticsAdapters(b *bluez.Bluez) []byte {
+	adapters := make(map[string]map[string]string)
+
+	for _, adapter := range b.GetAdapters() {
+		props, err := b.GetAdapterProperties(adapter.Path)
+		if err != nil {
+			continue
+		}
+
+		adapters[bluez.GetAdapterID(adapter.Path)] = diagnosticsProperties(props)
+	}
+
+	return diagnosticsJSON(adapters)
+}
+
+// diagnosticsDevices returns the properties of all devices.
+func diagnosticsDevices(b *bluez.Bluez) []byte {
+	devices := make(map[string]map[string]string)
+
+	for _, device := range b.GetAllDevices() {
+		props, err := b.GetDeviceProperties(device.Path)
+		if err != nil {
+			continue
+		}
+
+		devices[device.Path] = diagnosticsProperties(props)
+	}
+
+	return diagnosticsJSON(devices)
+}
+
+// diagnosticsProperties converts D-Bus properties to their string representations.
+func diagnosticsProperties(props map[string]dbus.Variant) map[string]string {
+	properties := make(map[string]string, len(props))
+
+	for name, value := range props {
+		properties[name] = value.String()
+	}
+
+	return properties
+}
+
+// diagnosticsJSON returns the indented JSON representation of the data.
+func diagnosticsJSON(data interface{}) []byte {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return []byte(err.Error())
+	}
+
+	return content
+}
+
+// writeDiagnostics writes the files to a zip archive if the path has
+// a ".zip" extension, or to a gzip-compressed tarball otherwise.
+func writeDiagnostics(path string, files []diagnosticsFile) error {
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		err = writeDiagnosticsZip(file, files)
+	} else {
+		err = writeDiagnosticsTar(file, files)
+	}
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// writeDiagnosticsZip writes the files to a zip archive.
+func writeDiagnosticsZip(w io.Writer, files []diagnosticsFile) error {
+	archive := zip.NewWriter(w)
+
+	for _, file := range files {
+		fw, err := archive.Create("bluetuith-diagnostics/" + file.Name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fw.Write(file.Data); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// writeDiagnosticsTar writes the files to a gzip-compressed tarball.
+func writeDiagnosticsTar(w io.Writer, files []diagnosticsFile) error {
+	compressor := gzip.NewWriter(w)
+	archive := tar.NewWriter(compressor)
+
+	for _, file := range files {
+		header := &tar.Header{
+			Name:    "bluetuith-diagnostics/" + file.Name,
+			Mode:    0600,
+			Size:    int64(len(file.Data)),
+			ModTime: time.Now(),
+		}
+
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if _, err := archive.Write(file.Data); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+
+	return compressor.Close()
+}
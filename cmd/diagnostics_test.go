@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteDiagnostics(t *testing.T) {
+	files := []diagnosticsFile{
+		{"version.txt", []byte("bluetuith: test\n")},
+		{"config.json", []byte("{}")},
+	}
+
+	dir := t.TempDir()
+
+	zipPath := filepath.Join(dir, "diagnostics.zip")
+	if err := writeDiagnostics(zipPath, files); err != nil {
+		t.Fatalf("writeDiagnostics() returned error: %v", err)
+	}
+
+	zipArchive, err := zip.OpenReader(zipPath)
+	if err != nil {
+		t.Fatalf("zip.OpenReader() returned error: %v", err)
+	}
+	defer zipArchive.Close()
+
+	if len(zipArchive.File) != 2 || zipArchive.File[0].Name != "bluetuith-diagnostics/config.json" {
+		t.Errorf("zip archive has unexpected files")
+	}
+
+	tarPath := filepath.Join(dir, "diagnostics.tar.gz")
+	if err := writeDiagnostics(tarPath, files); err != nil {
+		t.Fatalf("writeDiagnostics() returned error: %v", err)
+	}
+
+	file, err := os.Open(tarPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	decompressor, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() returned error: %v", err)
+	}
+
+	var count int
+
+	archive := tar.NewReader(decompressor)
+	for {
+		if _, err := archive.Next(); err != nil {
+			break
+		}
+
+		count++
+	}
+
+	if count != 2 {
+		t.Errorf("tarball has %d files, want 2", count)
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+)
+
+// DiscoverableWindow describes a time-boxed pairing window, where the
+// adapter is discoverable and pairable for a limited duration.
+type DiscoverableWindow struct {
+	adapterPath string
+
+	discoverable, pairable bool
+	discoverableTimeout    uint32
+}
+
+// StartDiscoverableWindow makes the adapter discoverable and pairable for the
+// provided duration. The previous states of the adapter are returned, so that
+// they can be restored once the window is over.
+func StartDiscoverableWindow(b *bluez.Bluez, adapterPath string, duration time.Duration) (DiscoverableWindow, error) {
+	window := DiscoverableWindow{adapterPath: adapterPath}
+
+	props, err := b.GetAdapterProperties(adapterPath)
+	if err != nil {
+		return window, err
+	}
+
+	window.discoverable, _ = props["Discoverable"].Value().(bool)
+	window.pairable, _ = props["Pairable"].Value().(bool)
+	window.discoverableTimeout, _ = props["DiscoverableTimeout"].Value().(uint32)
+
+	for _, property := range []struct {
+		Name  string
+		Value interface{}
+	}{
+		{"DiscoverableTimeout", uint32(duration.Seconds())},
+		{"Pairable", true},
+		{"Discoverable", true},
+	} {
+		if err := b.SetAdapterProperty(adapterPath, property.Name, property.Value); err != nil {
+			window.Stop(b)
+			return window, err
+		}
+	}
+
+	logger.write("discoverable", fmt.Sprintf("%s: Discoverable for %s", bluez.GetAdapterID(adapterPath), duration))
+
+	return window, nil
+}
+
+// Stop restores the previous states of the adapter.
+func (w DiscoverableWindow) Stop(b *bluez.Bluez) error {
+	var failed error
+
+	for _, property := range []struct {
+		Name  string
+		Value interface{}
+	}{
+		{"Discoverable", w.discoverable},
+		{"Pairable", w.pairable},
+		{"DiscoverableTimeout", w.discoverableTimeout},
+	} {
+		if err := b.SetAdapterProperty(w.adapterPath, property.Name, property.Value); err != nil && failed == nil {
+			failed = err
+		}
+	}
+
+	logger.write("discoverable", fmt.Sprintf("%s: Discoverable window ended", bluez.GetAdapterID(w.adapterPath)))
+
+	return failed
+}
+
+func cmdOptionDiscoverable(b *bluez.Bluez) {
+	optionDiscoverable := GetProperty("discoverable")
+	if optionDiscoverable == "" {
+		return
+	}
+
+	seconds, err := strconv.ParseUint(optionDiscoverable, 10, 32)
+	if err != nil || seconds == 0 {
+		PrintError(
+			fmt.Sprintf(
+				"Provided discoverable duration '%s' is incorrect.\nThe duration must be a number of seconds greater than 0.",
+				optionDiscoverable,
+			),
+		)
+	}
+
+	adapter := b.GetCurrentAdapter()
+	duration := time.Duration(seconds) * time.Second
+
+	window, err := StartDiscoverableWindow(b, adapter.Path, duration)
+	if err != nil {
+		PrintError("Could not make the adapter discoverable", err)
+	}
+
+	Print(fmt.Sprintf("Adapter %s is discoverable and pairable for %s", bluez.GetAdapterID(adapter.Path), duration))
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case <-time.After(duration):
+	case <-interrupt:
+	}
+
+	if err := window.Stop(b); err != nil {
+		PrintError("Could not restore the adapter states", err)
+	}
+
+	Print(fmt.Sprintf("Adapter %s is no longer discoverable", bluez.GetAdapterID(adapter.Path)), 0)
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+)
+
+// exportedDevices describes the file written by the "export-devices" option.
+//
+// Only the alias and the trusted state of a device are restored by the
+// "import-devices" option. The link keys of paired devices are not exported,
+// so paired devices have to be paired again, and the class and profiles are
+// only exported for reference.
+type exportedDevices struct {
+	Adapter string           `json:"adapter"`
+	Devices []exportedDevice `json:"devices"`
+}
+
+// exportedDevice describes a device in the exported devices file.
+type exportedDevice struct {
+	Address  string   `json:"address"`
+	Name     string   `json:"name"`
+	Alias    string   `json:"alias"`
+	Trusted  bool     `json:"trusted"`
+	Paired   bool     `json:"paired"`
+	Class    uint32   `json:"class"`
+	Profiles []string `json:"profiles"`
+}
+
+// importScanTimeout is the default duration for which the "import-devices"
+// option scans for devices which are not in range.
+const importScanTimeout = 30 * time.Second
+
+// parseExportedDevices parses and validates the contents of an exported devices file.
+func parseExportedDevices(data []byte) (exportedDevices, error) {
+	var exported exportedDevices
+
+	if err := json.Unmarshal(data, &exported); err != nil {
+		return exported, err
+	}
+
+	for i, device := range exported.Devices {
+		if mac, err := net.ParseMAC(device.Address); err != nil || len(mac) != 6 {
+			return exported, fmt.Errorf("The address '%s' of device %d is incorrect", device.Address, i+1)
+		}
+
+		exported.Devices[i].Address = strings.ToUpper(device.Address)
+	}
+
+	return exported, nil
+}
+
+func cmdOptionExportDevices(b *bluez.Bluez) {
+	optionExportDevices := GetProperty("export-devices")
+	if optionExportDevices == "" {
+		return
+	}
+
+	adapter := b.GetCurrentAdapter()
+	if adapter.Path == "" {
+		PrintError("No adapters found.")
+	}
+
+	exported := exportedDevices{
+		Adapter: adapter.Address,
+		Devices: []exportedDevice{},
+	}
+
+	for _, device := range b.GetDevices() {
+		profiles := make([]string, 0, len(device.UUIDs))
+		for _, serviceUUID := range device.UUIDs {
+			profiles = append(profiles, bluez.ServiceType(serviceUUID))
+		}
+
+		exported.Devices = append(exported.Devices, exportedDevice{
+			Address:  device.Address,
+			Name:     device.Name,
+			Alias:    device.Alias,
+			Trusted:  device.Trusted,
+			Paired:   device.Paired,
+			Class:    device.Class,
+			Profiles: profiles,
+		})
+	}
+
+	data, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		PrintError("Cannot encode the devices", err)
+	}
+
+	if err := os.WriteFile(optionExportDevices, append(data, '\n'), 0600); err != nil {
+		PrintError(fmt.Sprintf("Cannot write the devices to '%s'", optionExportDevices), err)
+	}
+
+	Print(
+		fmt.Sprintf(
+			"Exported %d device(s) of adapter '%s' to '%s'.\nOnly the aliases and trusted states are restored on import, paired devices have to be paired again.",
+			len(exported.Devices), filepath.Base(adapter.Path), optionExportDevices,
+		), 0,
+	)
+}
+
+func cmdOptionImportDevices(b *bluez.Bluez) {
+	optionImportDevices := GetProperty("import-devices")
+	if optionImportDevices == "" {
+		return
+	}
+
+	data, err := os.ReadFile(optionImportDevices)
+	if err != nil {
+		PrintError(fmt.Sprintf("Cannot read the devices from '%s'", optionImportDevices), err)
+	}
+
+	exported, err := parseExportedDevices(data)
+	if err != nil {
+		PrintError(fmt.Sprintf("Cannot parse the devices from '%s'", optionImportDevices), err)
+	}
+	if len(exported.Devices) == 0 {
+		Print(fmt.Sprintf("No devices to import from '%s'.", optionImportDevices), 0)
+	}
+
+	timeout := importScanTimeout
+	if optionTimeout := GetProperty("timeout"); optionTimeout != "" {
+		if err := validateDuration(optionTimeout); err != nil {
+			PrintError(
+				fmt.Sprintf(
+					"Provided timeout '%s' is incorrect: %s",
+					optionTimeout, err.Error(),
+				),
+			)
+		}
+
+		timeout, _ = time.ParseDuration(optionTimeout)
+	}
+
+	adapter := b.GetCurrentAdapter()
+	if adapter.Path == "" {
+		PrintError("No adapters found.")
+	}
+
+	pending := make(map[string]exportedDevice, len(exported.Devices))
+	for _, device := range exported.Devices {
+		pending[device.Address] = device
+	}
+
+	var imported, failed int
+
+	importDevice := func(device bluez.Device) {
+		exportedDevice, ok := pending[strings.ToUpper(device.Address)]
+		if !ok || device.Adapter != adapter.Path {
+			return
+		}
+
+		delete(pending, exportedDevice.Address)
+
+		if exportedDevice.Alias != "" && exportedDevice.Alias != device.Alias {
+			if err := b.SetAlias(device.Path, exportedDevice.Alias); err != nil {
+				PrintWarn(fmt.Sprintf("%s: Could not set the alias: %s", device.Address, err.Error()))
+				failed++
+
+				return
+			}
+		}
+
+		if exportedDevice.Trusted != device.Trusted {
+			if err := b.SetTrusted(device.Path, exportedDevice.Trusted); err != nil {
+				PrintWarn(fmt.Sprintf("%s: Could not set the trusted state: %s", device.Address, err.Error()))
+				failed++
+
+				return
+			}
+		}
+
+		imported++
+		Print(fmt.Sprintf("[+] %s: imported '%s'", device.Address, exportedDevice.Alias))
+	}
+
+	for _, device := range b.GetDevices() {
+		importDevice(device)
+	}
+
+	if len(pending) > 0 {
+		signals := b.WatchSignal()
+
+		if err := b.StartDiscovery(adapter.Path); err != nil {
+			PrintError(
+				fmt.Sprintf(
+					"Could not start discovery on adapter '%s': %s",
+					filepath.Base(adapter.Path), err.Error(),
+				),
+			)
+		}
+
+		Print(fmt.Sprintf("Scanning for %d device(s) which are not in range...", len(pending)))
+
+		scanTimeout := time.After(timeout)
+
+	Scan:
+		for len(pending) > 0 {
+			select {
+			case <-scanTimeout:
+				break Scan
+
+			case signal, ok := <-signals:
+				if !ok {
+					PrintError("The bluez DBus connection was closed.")
+				}
+
+				switch data := b.ParseSignalData(signal).(type) {
+				case bluez.Adapter:
+					if data.Path == adapter.Path && !data.Discovering {
+						b.StartDiscovery(adapter.Path)
+					}
+
+				case map[string][]bluez.Device:
+					for _, devices := range data {
+						for _, device := range devices {
+							importDevice(device)
+						}
+					}
+				}
+			}
+		}
+
+		b.StopDiscovery(adapter.Path)
+		b.Conn().RemoveSignal(signals)
+	}
+
+	for address := range pending {
+		PrintWarn(fmt.Sprintf("%s: not in range", address))
+	}
+
+	status := 0
+	if failed > 0 || len(pending) > 0 {
+		status = 1
+	}
+
+	Print(
+		fmt.Sprintf(
+			"Imported %d of %d device(s).\nOnly the aliases and trusted states were restored, paired devices have to be paired again.",
+			imported, len(exported.Devices),
+		), status,
+	)
+}
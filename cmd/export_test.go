@@ -0,0 +1,34 @@
+package cmd
+
+import "testing"
+
+func TestParseExportedDevices(t *testing.T) {
+	exported, err := parseExportedDevices([]byte(`{
+		"adapter": "00:11:22:33:44:55",
+		"devices": [
+			{"address": "aa:bb:cc:dd:ee:ff", "alias": "Headphones", "trusted": true}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("parseExportedDevices returned an error: %v", err)
+	}
+
+	if len(exported.Devices) != 1 {
+		t.Fatalf("parseExportedDevices returned %d devices, want 1", len(exported.Devices))
+	}
+
+	device := exported.Devices[0]
+	if device.Address != "AA:BB:CC:DD:EE:FF" || device.Alias != "Headphones" || !device.Trusted {
+		t.Errorf("parseExportedDevices returned %+v", device)
+	}
+
+	for _, invalid := range []string{
+		`{"devices": [{"address": "AA:BB:CC"}]}`,
+		`{"devices": [{"alias": "Headphones"}]}`,
+		`[]`,
+	} {
+		if _, err := parseExportedDevices([]byte(invalid)); err == nil {
+			t.Errorf("parseExportedDevices(%q) did not return an error", invalid)
+		}
+	}
+}
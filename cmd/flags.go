@@ -1,23 +1,37 @@
 package cmd
 
 import (
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/darkhz/bluetuith/bluez"
 	"github.com/darkhz/bluetuith/theme"
+	"github.com/fatih/color"
+	"github.com/godbus/dbus/v5"
+	"github.com/google/uuid"
 	"github.com/knadh/koanf/parsers/hjson"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/providers/posflag"
 	flag "github.com/spf13/pflag"
 )
 
-// Option describes a command-line option.
+// Option describes a command-line option. IsAction is set for the
+// options which perform a one-time action, such as listing devices.
 type Option struct {
-	Name, Description, Value string
-	IsBoolean                bool
+	Name, Description, Value    string
+	IsBoolean, IsList, IsAction bool
 }
 
 var options = []Option{
@@ -25,15 +39,111 @@ var options = []Option{
 		Name:        "list-adapters",
 		Description: "List available adapters.",
 		IsBoolean:   true,
+		IsAction:    true,
 	},
 	{
 		Name:        "adapter",
 		Description: "Specify an adapter to use. (For example, hci0)",
 	},
+	{
+		Name:        "find",
+		Description: "Search the devices of all adapters by name or address, and exit. (For example, 'headset')",
+		IsAction:    true,
+	},
+	{
+		Name:        "list-devices",
+		Description: "List the devices of the current adapter, or of the adapter specified with 'adapter', and exit.",
+		IsBoolean:   true,
+		IsAction:    true,
+	},
+	{
+		Name:        "assert",
+		Description: "Check the comma-separated assertions against the adapter and device states, and exit with a non-zero status if any assertion fails. (For example, 'powered:hci0,connected:AA:BB:CC:DD:EE:FF,battery:AA:BB:CC:DD:EE:FF>=20')",
+		IsAction:    true,
+	},
+	{
+		Name:        "tag",
+		Description: "Only list the devices with the provided tag, when listing devices.",
+	},
+	{
+		Name:        "collect-diagnostics",
+		Description: "Write the debug log, configuration, adapter and device information to a tarball or zip archive for bug reports, and exit.",
+		IsAction:    true,
+	},
+	{
+		Name:        "output-format",
+		Description: "Specify the format to print the output of 'list-adapters', 'find', 'list-devices', 'config-keys' and 'dump-keybindings' in, either 'text' or 'json'.",
+		Value:       "text",
+	},
+	{
+		Name:        "json",
+		Description: "Print the output in the JSON format. This is the same as '--output-format json'.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "dbus-system-address",
+		Description: "Specify the address of the D-Bus system bus to connect to, instead of the default system bus. (For example, 'unix:path=/run/dbus/system_bus_socket')",
+	},
+	{
+		Name:        "adapter-info",
+		Description: "Show information about the adapter, including its supported roles.",
+		IsBoolean:   true,
+		IsAction:    true,
+	},
+	{
+		Name:        "receive",
+		Description: "Accept all incoming file transfers into the directory specified with 'receive-dir' without the interface, until interrupted.",
+		IsBoolean:   true,
+		IsAction:    true,
+	},
+	{
+		Name:        "auto-accept-trusted",
+		Description: "Accept file transfers from trusted devices without confirmation. Each accepted transfer is logged.",
+		IsBoolean:   true,
+	},
 	{
 		Name:        "receive-dir",
 		Description: "Specify a directory to store received files.",
 	},
+	{
+		Name:        "receive-device-dirs",
+		Description: "Save received files into subdirectories of the receive directory, which are named after the alias or address of the sending device.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "receive-conflict",
+		Description: "Specify how a received file is saved if a file with the same name exists, either 'rename' to append a counter to its name, 'overwrite' to replace the existing file, or 'skip' to discard the received file.",
+		Value:       "rename",
+	},
+	{
+		Name:        "receive-max-size",
+		Description: "Specify the maximum size in bytes of a received file. Larger files are rejected before they are received. A value of 0 allows files of any size.",
+		Value:       "0",
+	},
+	{
+		Name:        "ipc-socket",
+		Description: "Listen for commands on a Unix socket at the provided path. Each command is sent on a line ('connect <address>', 'disconnect <address>', 'scan <on|off>', 'power <on|off>', 'discoverable <seconds>' or 'list-devices'), and is replied to with a JSON response.",
+	},
+	{
+		Name:        "max-concurrent-connections",
+		Description: "Specify the maximum number of devices to connect simultaneously.",
+		Value:       "1",
+	},
+	{
+		Name:        "max-concurrent-transfers",
+		Description: "Specify the maximum number of file transfers to send simultaneously. Files are sent to a device one at a time.",
+		Value:       "1",
+	},
+	{
+		Name:        "transfer-retries",
+		Description: "Specify the number of times a failed file transfer is retried from the start.",
+		Value:       "0",
+	},
+	{
+		Name:        "transfer-retry-delay",
+		Description: "Specify the delay before retrying a failed file transfer.",
+		Value:       "5s",
+	},
 	{
 		Name:        "gsm-apn",
 		Description: "Specify GSM APN to connect to. (Required for DUN)",
@@ -42,17 +152,151 @@ var options = []Option{
 		Name:        "gsm-number",
 		Description: "Specify GSM number to dial. (Required for DUN)",
 	},
+	{
+		Name:        "gsm-pin",
+		Description: "Specify the SIM PIN to unlock the modem before dialing. (DUN only, never written to the generated configuration)",
+	},
+	{
+		Name:        "control-socket-token",
+		Description: "Require each command sent on the control socket to start with the provided token, and reject the commands without it. (For example, '<token> connect <address>')",
+	},
 	{
 		Name:        "adapter-states",
 		Description: "Specify adapter states to enable/disable. (For example, 'powered:yes,discoverable:yes,pairable:yes,scan:no')",
 	},
+	{
+		Name:        "discovery-filter",
+		Description: "Specify a discovery filter to apply while scanning. (For example, 'transport:le,rssi:-70,uuid:0000110b-0000-1000-8000-00805f9b34fb')",
+	},
+	{
+		Name:        "discoverable-presets",
+		Description: "Specify three durations for the discoverable presets, where 'permanent' disables the timeout. (For example, '1m,5m,permanent')",
+		Value:       "1m,5m,permanent",
+	},
+	{
+		Name:        "device-sort",
+		Description: "Specify a list of device properties with the sort order to sort the device list by. (For example, 'favorite:desc,connected:desc,battery:asc')",
+	},
+	{
+		Name:        "statusbar-segments",
+		Description: "Specify a list of segments with their alignment to display in the status bar. Valid segments are '" + strings.Join(statusbarSegments, "', '") + "'. (For example, 'adapter-name:left,scan-state:left,clock:right')",
+	},
+	{
+		Name:        "rssi-format",
+		Description: "Specify the format to display the signal strength in, either 'dbm' or 'bars'.",
+		Value:       "dbm",
+	},
+	{
+		Name:        "rssi-thresholds",
+		Description: "Specify four increasing signal strengths in dBm, from which the signal bars are displayed. (For example, '-90,-80,-70,-60')",
+		Value:       "-90,-80,-70,-60",
+	},
+	{
+		Name:        "presence-check-interval",
+		Description: "Specify the interval at which to briefly scan for paired devices, to detect whether they are out of range. A value of 0 disables the presence check. (For example, '5m')",
+		Value:       "0",
+	},
+	{
+		Name:        "scan-refresh-interval",
+		Description: "Specify the interval in seconds at which the device list is checked against bluez during a scan, to remove devices which have disappeared and refresh device properties. A value of 0 disables the check.",
+		Value:       "0",
+	},
 	{
 		Name:        "connect-bdaddr",
-		Description: "Specify device address to connect (For example, 'AA:BB:CC:DD:EE:FF')",
+		Description: "Specify a comma-separated list of device addresses to connect to in sequence. Devices which could not be found or connected to are reported when the application is closed, with a non-zero exit status. (For example, 'AA:BB:CC:DD:EE:FF,11:22:33:44:55:66')",
+		IsAction:    true,
+	},
+	{
+		Name:        "profile",
+		Description: "Connect to the devices of a connection profile, and exit. (For example, 'work')",
+		IsAction:    true,
+	},
+	{
+		Name:        "await-connect",
+		Description: "Wait for a device to come into range, connect to it and exit. (For example, 'AA:BB:CC:DD:EE:FF')",
+		IsAction:    true,
+	},
+	{
+		Name:        "maintain-bdaddr",
+		Description: "Connect to a device and keep it connected, reconnecting to it whenever it disconnects, until interrupted. (For example, 'AA:BB:CC:DD:EE:FF')",
+		IsAction:    true,
+	},
+	{
+		Name:        "reconnect-retries",
+		Description: "Specify the number of consecutive failed reconnection attempts after which 'maintain-bdaddr' exits. A value of 0 retries indefinitely.",
+		Value:       "0",
+	},
+	{
+		Name:        "reconnect-delay",
+		Description: "Specify the delay between reconnection attempts with 'maintain-bdaddr'.",
+		Value:       "10s",
+	},
+	{
+		Name:        "send-file",
+		Description: "Send a file to the device specified with 'send-to', and exit. This option can be specified multiple times to send multiple files.",
+		IsList:      true,
+		IsAction:    true,
+	},
+	{
+		Name:        "send-to",
+		Description: "Specify the address of the device to send files to with 'send-file'. (For example, 'AA:BB:CC:DD:EE:FF')",
+		IsAction:    true,
+	},
+	{
+		Name:        "export-devices",
+		Description: "Export the devices of the adapter to a file, and exit. Only the aliases and trusted states of the devices can be restored with 'import-devices'.",
+		IsAction:    true,
+	},
+	{
+		Name:        "import-devices",
+		Description: "Restore the aliases and trusted states of the devices exported to a file with 'export-devices', and exit. Devices which are not in range are scanned for until 'timeout' (30s by default). Paired devices have to be paired again.",
+		IsAction:    true,
+	},
+	{
+		Name:        "connect-timeout",
+		Description: "Specify the number of seconds or the duration to scan for the devices specified with 'connect-bdaddr', if they are not found on the adapter. (For example, '30' or '1m')",
+	},
+	{
+		Name:        "timeout",
+		Description: "Specify the duration to wait for a device with 'await-connect', or for devices with 'import-devices'. (For example, '5m')",
+	},
+	{
+		Name:        "discoverable",
+		Description: "Make the adapter discoverable and pairable for the provided number of seconds, restore its previous states, and exit.",
+		IsAction:    true,
+	},
+	{
+		Name:        "airplane",
+		Description: "Enable or disable airplane mode, which powers off all adapters, and exit. (For example, 'on')",
+		IsAction:    true,
+	},
+	{
+		Name:        "airplane-rfkill",
+		Description: "Also soft-block all bluetooth devices via rfkill when airplane mode is enabled.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "remove-protection",
+		Description: "Specify how the removal of trusted or favorited devices is protected, either 'confirm' or 'block'.",
+		Value:       "confirm",
+	},
+	{
+		Name:        "force",
+		Description: "Remove trusted or favorited devices without additional confirmation.",
+		IsBoolean:   true,
 	},
 	{
 		Name:        "theme",
-		Description: "Specify a theme in the HJSON format. (For example, '{ Adapter: \"red\" }')",
+		Description: "Specify a theme in the HJSON format, or a path or http(s) URL to a HJSON theme file. Colors can be color names or hex values. (For example, '{ Adapter: \"red\", Device: \"#1a2b3c\" }')",
+	},
+	{
+		Name:        "theme-preset",
+		Description: "Specify a built-in theme ('" + strings.Join(theme.ThemePresetNames(), "', '") + "'), which can be overridden by the theme option.",
+	},
+	{
+		Name:        "no-color",
+		Description: "Disable all colors and styles, in the interface and in the printed output. The printed output is never colored if it is not a terminal.",
+		IsBoolean:   true,
 	},
 	{
 		Name:        "no-warning",
@@ -64,24 +308,189 @@ var options = []Option{
 		Description: "Do not display help keybindings in the application.",
 		IsBoolean:   true,
 	},
+	{
+		Name:        "no-device-grouping",
+		Description: "Do not group stereo earbuds into a single device entry. Two devices are grouped if they have the same name and audio class, and their addresses differ by one in the last octet.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "device-stats",
+		Description: "Track and display per-device connection statistics.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "persist-state-changes",
+		Description: "Save the time of the last connection state change of each device across sessions.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "show-state-changes",
+		Description: "Show the time since the last connection state change of each device in the device list.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "auto-connect",
+		Description: "Specify a comma-separated list of device addresses to connect to automatically whenever they are seen, if they are paired and not blocked. Failed connections are retried with an increasing delay. (For example, 'AA:BB:CC:DD:EE:FF,11:22:33:44:55:66')",
+	},
+	{
+		Name:        "manual-disconnect-cooldown",
+		Description: "Specify the duration for which a manually disconnected device is not automatically reconnected, or 'session' to not reconnect it until the next session. A value of 0 disables the cooldown. (For example, '30m')",
+		Value:       "0",
+	},
+	{
+		Name:        "auto-reconnect",
+		Description: "On startup, reconnect to the devices which were connected when bluetuith was last closed.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "prefer-last-audio",
+		Description: "On startup, or when the adapter is powered on, connect to the most recently manually connected audio device, and only fall back to other trusted audio devices if it is unavailable.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "stop-scan-on-connect",
+		Description: "Stop scanning on the adapter while connecting to a device.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "resume-scan-after-connect",
+		Description: "Resume scanning after connecting, if it was stopped by the 'stop-scan-on-connect' option.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "no-agent",
+		Description: "Do not register the pairing agent, for example if another agent is used.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "agent-capability",
+		Description: "Specify the input and output capability which the pairing agent registers with bluez, either 'DisplayOnly', 'DisplayYesNo', 'KeyboardOnly', 'NoInputNoOutput' or 'KeyboardDisplay'. For example, 'NoInputNoOutput' can be used on headless setups to pair without any prompts.",
+		Value:       "KeyboardDisplay",
+	},
+	{
+		Name:        "auto-accept-pair",
+		Description: "Accept all pairing requests without confirmation, for example on headless setups along with the 'receive' option. Each accepted request is logged.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "auto-accept-pair-prefixes",
+		Description: "Specify a comma-separated list of address prefixes, to only accept pairing requests from devices whose addresses begin with one of them if the 'auto-accept-pair' option is set. (For example, 'AA:BB:CC,11:22')",
+	},
+	{
+		Name:        "agent-prompt",
+		Description: "Prompt for the pincode or passkey when a device requests one during pairing, instead of providing the default pincode (0000) or passkey (001024).",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "agent-timeout",
+		Description: "Specify the duration after which an unanswered pairing prompt is rejected. A value of 0 disables the timeout. (For example, '30s')",
+		Value:       "30s",
+	},
+	{
+		Name:        "player-seek-step",
+		Description: "Specify the duration by which the media player seeks forward or backward, by fast-forwarding or rewinding the track until the position has changed by the duration. A value of 0 fast-forwards or rewinds until playback is resumed. (For example, '10s')",
+		Value:       "10s",
+	},
+	{
+		Name:        "log-agent",
+		Description: "Log all agent interactions to the debug log (bluetuith.log in the configuration directory).",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "log-file",
+		Description: "Write the debug log to the provided file, and log the adapter and device operations, errors and file transfers to it.",
+	},
+	{
+		Name:        "log-level",
+		Description: "Specify the level of detail of the log file, either 'error', 'warn', 'info' or 'debug'. The 'debug' level includes the arguments of the adapter and device operations, and the bluez signals.",
+		Value:       "info",
+	},
 	{
 		Name:        "confirm-on-quit",
 		Description: "Ask for confirmation before quitting the application.",
 		IsBoolean:   true,
 	},
+	{
+		Name:        "no-wizard",
+		Description: "Do not run the setup wizard when the application is started for the first time.",
+		IsBoolean:   true,
+	},
+	{
+		Name:        "config-keys",
+		Description: "List all recognized configuration keys with their types and defaults.",
+		IsBoolean:   true,
+		IsAction:    true,
+	},
+	{
+		Name:        "dump-keybindings",
+		Description: "List the keybindings in effect, including the keybindings from the configuration.",
+		IsBoolean:   true,
+		IsAction:    true,
+	},
 	{
 		Name:        "generate",
 		Description: "Generate configuration.",
 		IsBoolean:   true,
+		IsAction:    true,
 	},
 	{
 		Name:        "version",
 		Description: "Print version information.",
 		IsBoolean:   true,
+		IsAction:    true,
 	},
 }
 
+// deviceOptions holds the per-device properties that can be specified
+// in the "devices" section of the configuration, and their validators.
+var deviceOptions = map[string]func(value string) error{
+	"adapter-fallback":  validateAdapterList,
+	"auto-disconnect":   validateDuration,
+	"connect-direction": validateConnectDirection,
+	"favorite":          validateBoolean,
+	"preferred-adapter": validateAdapterID,
+	"profile-order":     validateUUIDList,
+	"tags":              validateTags,
+}
+
+// statusbarSegments holds the segments that can be displayed in the status bar.
+var statusbarSegments = []string{
+	"adapter-name",
+	"adapter-address",
+	"clock",
+	"connected-count",
+	"scan-state",
+}
+
+// agentCapabilities holds the capabilities that the pairing agent can be registered with.
+var agentCapabilities = []string{
+	"DisplayOnly",
+	"DisplayYesNo",
+	"KeyboardOnly",
+	"NoInputNoOutput",
+	"KeyboardDisplay",
+}
+
+// sortKeys holds the device properties that the device list can be sorted by.
+var sortKeys = []string{
+	"favorite",
+	"connected",
+	"paired",
+	"trusted",
+	"blocked",
+	"name",
+	"alias",
+	"address",
+	"type",
+	"battery",
+	"rssi",
+}
+
 func parse() {
+	if stat, err := os.Stat(filepath.Join(config.path, "bluetuith.conf")); err != nil || stat.Size() == 0 {
+		firstRun = true
+	}
+
 	configFile, err := ConfigPath("bluetuith.conf")
 	if err != nil {
 		PrintError("Cannot get config directory")
@@ -106,20 +515,125 @@ func parse() {
 			case "adapter-states":
 				s += " [<property>:<state>]"
 
+			case "discovery-filter":
+				s += " [<parameter>:<value>]"
+
+			case "send-file", "log-file", "ipc-socket":
+				s += " <path>"
+
+			case "export-devices", "import-devices":
+				s += " <file>"
+
 			case "connect-bdaddr":
+				s += " <address>[,<address>...]"
+
+			case "await-connect", "maintain-bdaddr", "send-to":
+				s += " <address>"
+
+			case "timeout":
+				s += " <duration>"
+
+			case "connect-timeout":
+				s += " <seconds|duration>"
+
+			case "find":
+				s += " <query>"
+
+			case "discoverable":
+				s += " <seconds>"
+
+			case "dbus-system-address":
 				s += " <address>"
 
+			case "assert":
+				s += " <kind>:<target>[<op><number>],..."
+
+			case "tag":
+				s += " <name>"
+
+			case "collect-diagnostics":
+				s += " <file>"
+
+			case "profile":
+				s += " <name>"
+
+			case "airplane":
+				s += " <on|off>"
+
+			case "remove-protection":
+				s += " <confirm|block>"
+
+			case "rssi-format":
+				s += " <dbm|bars>"
+
+			case "rssi-thresholds":
+				s += " <dbm>,<dbm>,<dbm>,<dbm>"
+
+			case "device-sort":
+				s += " [<property>:<order>]"
+
+			case "statusbar-segments":
+				s += " [<segment>:<alignment>]"
+
+			case "discoverable-presets":
+				s += " <duration>,<duration>,<duration>"
+
 			case "receive-dir":
 				s += " <dir>"
 
+			case "receive-max-size":
+				s += " <bytes>"
+
+			case "receive-conflict":
+				s += " <rename|overwrite|skip>"
+
+			case "max-concurrent-transfers", "max-concurrent-connections":
+				s += " <number>"
+
+			case "transfer-retries", "reconnect-retries":
+				s += " <count>"
+
+			case "scan-refresh-interval":
+				s += " <seconds>"
+
+			case "manual-disconnect-cooldown":
+				s += " <duration|session>"
+
+			case "transfer-retry-delay", "presence-check-interval", "reconnect-delay", "agent-timeout", "player-seek-step":
+				s += " <duration>"
+
 			case "gsm-apn":
 				s += " <apn>"
 
 			case "gsm-number":
 				s += " <number>"
 
-			case "set-theme":
+			case "gsm-pin":
+				s += " <pin>"
+
+			case "control-socket-token":
+				s += " <token>"
+
+			case "theme":
 				s += " <theme>"
+
+			case "theme-preset":
+				s += " <name>"
+
+			case "output-format":
+				s += " <text|json>"
+
+			case "log-level":
+				s += " <error|warn|info|debug>"
+
+			case "agent-capability":
+				s += " <capability>"
+
+			case "auto-connect":
+				s += " <address>,<address>,..."
+
+			case "auto-accept-pair-prefixes":
+				s += " <prefix>,<prefix>,..."
 			}
 
 			if len(s) <= 4 {
@@ -144,6 +658,11 @@ func parse() {
 			continue
 		}
 
+		if option.IsList {
+			fs.StringArray(option.Name, nil, option.Description)
+			continue
+		}
+
 		fs.String(option.Name, option.Value, option.Description)
 	}
 
@@ -151,13 +670,30 @@ func parse() {
 		PrintError(err.Error())
 	}
 
-	if err := config.Load(file.Provider(configFile), hjson.Parser()); err != nil {
+	config.provider = file.Provider(configFile)
+	if err := config.Load(config.provider, hjson.Parser()); err != nil {
 		PrintError(err.Error())
 	}
+	config.themeFlag = fs.Changed("theme")
 
 	if err := config.Load(posflag.Provider(fs, ".", config.Koanf), nil); err != nil {
 		PrintError(err.Error())
 	}
+
+	switch optionOutputFormat := GetProperty("output-format"); optionOutputFormat {
+	case "text", "json":
+		if IsPropertyEnabled("json") {
+			AddProperty("output-format", "json")
+		}
+
+	default:
+		PrintError(
+			fmt.Sprintf(
+				"Provided output format '%s' is incorrect.\nValid formats are 'text' or 'json'.",
+				optionOutputFormat,
+			),
+		)
+	}
 }
 
 func cmdOptionAdapter(b *bluez.Bluez) {
@@ -177,41 +713,273 @@ func cmdOptionAdapter(b *bluez.Bluez) {
 	PrintError(optionAdapter + ": The adapter does not exist.")
 }
 
-func cmdOptionListAdapters(b *bluez.Bluez) {
-	var adapters string
-
-	if !IsPropertyEnabled("list-adapters") {
+func cmdOptionAdapterInfo(b *bluez.Bluez) {
+	if !IsPropertyEnabled("adapter-info") {
 		return
 	}
 
-	adapters += "List of adapters:\n"
-	for _, adapter := range b.GetAdapters() {
-		adapters += "- " + filepath.Base(adapter.Path) + "\n"
+	adapter := b.GetCurrentAdapter()
+	if adapter.Path == "" {
+		PrintError("No adapters found.")
 	}
 
-	Print(strings.TrimRight(adapters, "\n"), 0)
+	yesno := func(val bool) string {
+		if !val {
+			return "no"
+		}
+
+		return "yes"
+	}
+
+	info := "Adapter information (" + filepath.Base(adapter.Path) + "):\n"
+	for _, prop := range [][]string{
+		{"Name", adapter.Name},
+		{"Alias", adapter.Alias},
+		{"Address", adapter.Address},
+		{"Powered", yesno(adapter.Powered)},
+		{"Discoverable", yesno(adapter.Discoverable)},
+		{"Pairable", yesno(adapter.Pairable)},
+		{"Discovering", yesno(adapter.Discovering)},
+		{"Roles", bluez.GetAdapterRoles(adapter)},
+	} {
+		info += "- " + prop[0] + ": " + prop[1] + "\n"
+	}
+
+	Print(strings.TrimRight(info, "\n"), 0)
 }
 
-func cmdOptionAdapterStates() {
-	optionAdapterStates := GetProperty("adapter-states")
-	if optionAdapterStates == "" {
+func cmdOptionFind(b *bluez.Bluez) {
+	optionFind := GetProperty("find")
+	if optionFind == "" {
 		return
 	}
 
-	properties := make(map[string]string)
-	propertyAndStates := strings.Split(optionAdapterStates, ",")
+	query := strings.ToLower(optionFind)
 
-	propertyOptions := []string{
-		"powered",
-		"scan",
-		"discoverable",
-		"pairable",
-	}
+	found := getListedDevices(b, func(device bluez.Device) bool {
+		return strings.Contains(strings.ToLower(device.Name), query) ||
+			strings.Contains(strings.ToLower(device.Alias), query) ||
+			strings.Contains(strings.ToLower(device.Address), query)
+	})
 
-	stateOptions := []string{
-		"yes", "no",
-		"y", "n",
-		"on", "off",
+	printListedDevices(
+		found,
+		fmt.Sprintf("Devices matching '%s':", optionFind),
+		fmt.Sprintf("No devices were found matching '%s'.", optionFind),
+	)
+}
+
+func cmdOptionListDevices(b *bluez.Bluez) {
+	if !IsPropertyEnabled("list-devices") {
+		return
+	}
+
+	optionTag := GetProperty("tag")
+
+	adapter := b.GetCurrentAdapter()
+	if adapter.Path == "" {
+		PrintError("No adapters found.")
+	}
+
+	devices := getListedDevices(b, func(device bluez.Device) bool {
+		return device.Adapter == adapter.Path &&
+			(optionTag == "" || HasDeviceTag(device.Address, optionTag))
+	})
+
+	adapterID := filepath.Base(adapter.Path)
+
+	header, empty := "Devices on "+adapterID+":", "No devices were found on "+adapterID+"."
+	if optionTag != "" {
+		header = fmt.Sprintf("Devices tagged '%s' on %s:", optionTag, adapterID)
+		empty = fmt.Sprintf("No devices were found tagged '%s' on %s.", optionTag, adapterID)
+	}
+
+	printListedDevices(devices, header, empty)
+}
+
+// listedAdapter describes an adapter in the output of the "list-adapters" option.
+type listedAdapter struct {
+	Path         string `json:"path"`
+	Name         string `json:"name"`
+	Address      string `json:"address"`
+	Powered      bool   `json:"powered"`
+	Discoverable bool   `json:"discoverable"`
+	Pairable     bool   `json:"pairable"`
+}
+
+// listedDevice describes a device in the output of the "find" and "list-devices" options.
+type listedDevice struct {
+	Name      string   `json:"name"`
+	Alias     string   `json:"alias"`
+	Address   string   `json:"address"`
+	Adapter   string   `json:"adapter"`
+	Paired    bool     `json:"paired"`
+	Connected bool     `json:"connected"`
+	Trusted   bool     `json:"trusted"`
+	Blocked   bool     `json:"blocked"`
+	RSSI      int16    `json:"rssi,omitempty"`
+	Tags      []string `json:"tags"`
+}
+
+// getListedDevices returns the devices of all adapters which match the filter,
+// sorted by their adapters and addresses.
+func getListedDevices(b *bluez.Bluez, filter func(device bluez.Device) bool) []listedDevice {
+	listed := []listedDevice{}
+
+	for _, device := range b.GetAllDevices() {
+		if !filter(device) {
+			continue
+		}
+
+		tags := GetDeviceTags(device.Address)
+		if tags == nil {
+			tags = []string{}
+		}
+
+		listed = append(listed, listedDevice{
+			Name:      device.Name,
+			Alias:     device.Alias,
+			Address:   device.Address,
+			Adapter:   filepath.Base(device.Adapter),
+			Paired:    device.Paired,
+			Connected: device.Connected,
+			Trusted:   device.Trusted,
+			Blocked:   device.Blocked,
+			RSSI:      device.RSSI,
+			Tags:      tags,
+		})
+	}
+
+	sort.Slice(listed, func(i, j int) bool {
+		if listed[i].Adapter != listed[j].Adapter {
+			return listed[i].Adapter < listed[j].Adapter
+		}
+
+		return listed[i].Address < listed[j].Address
+	})
+
+	return listed
+}
+
+// printListedDevices prints the devices and exits, with a non-zero
+// exit status if there are no devices.
+func printListedDevices(listed []listedDevice, header, empty string) {
+	status := 0
+	if len(listed) == 0 {
+		status = 1
+	}
+
+	if GetProperty("output-format") == "json" {
+		data, err := json.MarshalIndent(listed, "", "  ")
+		if err != nil {
+			PrintError(err.Error())
+		}
+
+		Print(string(data), status)
+	}
+
+	if len(listed) == 0 {
+		PrintError(empty)
+	}
+
+	devices := header + "\n"
+	for _, device := range listed {
+		var states []string
+
+		for _, state := range []struct {
+			name    string
+			enabled bool
+		}{
+			{"paired", device.Paired},
+			{"connected", device.Connected},
+			{"trusted", device.Trusted},
+			{"blocked", device.Blocked},
+		} {
+			if state.enabled {
+				states = append(states, state.name)
+			}
+		}
+
+		devices += "- " + device.Name
+		if device.Alias != "" && device.Alias != device.Name {
+			devices += " '" + device.Alias + "'"
+		}
+		devices += fmt.Sprintf(" (%s) on %s", device.Address, device.Adapter)
+		if device.RSSI < 0 {
+			devices += fmt.Sprintf(" %d dBm", device.RSSI)
+		}
+		if states != nil {
+			devices += " [" + strings.Join(states, ", ") + "]"
+		}
+		if len(device.Tags) > 0 {
+			devices += " {" + strings.Join(device.Tags, ", ") + "}"
+		}
+		devices += "\n"
+	}
+
+	Print(strings.TrimRight(devices, "\n"), status)
+}
+
+func cmdOptionListAdapters(b *bluez.Bluez) {
+	var adapters string
+
+	if !IsPropertyEnabled("list-adapters") {
+		return
+	}
+
+	if GetProperty("output-format") == "json" {
+		listed := []listedAdapter{}
+		for _, adapter := range b.GetAdapters() {
+			listed = append(listed, listedAdapter{
+				Path:         adapter.Path,
+				Name:         adapter.Name,
+				Address:      adapter.Address,
+				Powered:      adapter.Powered,
+				Discoverable: adapter.Discoverable,
+				Pairable:     adapter.Pairable,
+			})
+		}
+
+		sort.Slice(listed, func(i, j int) bool {
+			return listed[i].Path < listed[j].Path
+		})
+
+		data, err := json.MarshalIndent(listed, "", "  ")
+		if err != nil {
+			PrintError(err.Error())
+		}
+
+		Print(string(data), 0)
+	}
+
+	adapters += "List of adapters:\n"
+	for _, adapter := range b.GetAdapters() {
+		adapters += "- " + filepath.Base(adapter.Path) + "\n"
+	}
+
+	Print(strings.TrimRight(adapters, "\n"), 0)
+}
+
+func cmdOptionAdapterStates() {
+	optionAdapterStates := GetProperty("adapter-states")
+	if optionAdapterStates == "" {
+		return
+	}
+
+	properties := make(map[string]string)
+	propertyAndStates := strings.Split(optionAdapterStates, ",")
+
+	propertyOptions := []string{
+		"powered",
+		"scan",
+		"discoverable",
+		"pairable",
+	}
+
+	stateOptions := []string{
+		"yes", "no",
+		"y", "n",
+		"on", "off",
 	}
 
 	sequence := []string{}
@@ -270,101 +1038,1324 @@ func cmdOptionAdapterStates() {
 	AddProperty("adapter-states", properties)
 }
 
-func cmdOptionConnectBDAddr(b *bluez.Bluez) {
-	optionConnectBDAddr := GetProperty("connect-bdaddr")
-	if optionConnectBDAddr == "" {
+func cmdOptionDiscoveryFilter() {
+	optionDiscoveryFilter := GetProperty("discovery-filter")
+	if optionDiscoveryFilter == "" {
 		return
 	}
 
-	adapter := b.GetCurrentAdapter()
-	if adapter == (bluez.Adapter{}) {
-		return
+	if _, err := bluez.ParseDiscoveryFilter(optionDiscoveryFilter); err != nil {
+		PrintError(err.Error() + ".")
 	}
+}
 
-	for _, device := range b.GetDevices() {
-		if device.Address == optionConnectBDAddr {
-			AddProperty("connect-bdaddr", device.Address)
-			return
+func cmdOptionDiscoverablePresets() {
+	optionDiscoverablePresets := GetProperty("discoverable-presets")
+
+	presets := strings.Split(optionDiscoverablePresets, ",")
+	if len(presets) != 3 {
+		PrintError(
+			fmt.Sprintf(
+				"Provided discoverable presets '%s' are incorrect.\nExactly three durations must be specified.",
+				optionDiscoverablePresets,
+			),
+		)
+	}
+
+	for i, preset := range presets {
+		preset = strings.TrimSpace(preset)
+		presets[i] = preset
+
+		if preset != "permanent" {
+			if err := validateDuration(preset); err != nil {
+				PrintError(
+					fmt.Sprintf(
+						"Provided discoverable preset '%s' is incorrect: %s",
+						preset, err.Error(),
+					),
+				)
+			}
 		}
+
+		key := []Key{
+			KeyAdapterDiscoverablePreset1,
+			KeyAdapterDiscoverablePreset2,
+			KeyAdapterDiscoverablePreset3,
+		}[i]
+		OperationKeys[key].Title = "Discoverable (" + preset + ")"
 	}
 
-	PrintError(
-		fmt.Sprintf(
-			"No device with address '%s' found on adapter '%s' (%s)",
-			optionConnectBDAddr,
-			adapter.Name,
-			filepath.Base(adapter.Path),
-		),
-	)
+	AddProperty("discoverable-presets", strings.Join(presets, ","))
 }
 
-func cmdOptionReceiveDir() {
-	optionReceiveDir := GetProperty("receive-dir")
-	if optionReceiveDir == "" {
+func cmdOptionDeviceSort() {
+	optionDeviceSort := GetProperty("device-sort")
+	if optionDeviceSort == "" {
 		return
 	}
 
-	if statpath, err := os.Stat(optionReceiveDir); err == nil && statpath.IsDir() {
-		AddProperty("receive-dir", optionReceiveDir)
-		return
+	var sortSpec []string
+
+	for _, ko := range strings.Split(optionDeviceSort, ",") {
+		keyOrder := strings.FieldsFunc(ko, func(r rune) bool {
+			return r == ' ' || r == ':'
+		})
+		if len(keyOrder) == 1 {
+			keyOrder = append(keyOrder, "asc")
+		}
+		if len(keyOrder) != 2 {
+			PrintError(
+				fmt.Sprintf(
+					"Provided property:order format '%s' is incorrect.",
+					ko,
+				),
+			)
+		}
+
+		for _, key := range sortKeys {
+			if keyOrder[0] == key {
+				goto CheckOrder
+			}
+		}
+		PrintError(
+			fmt.Sprintf(
+				"Provided sort property '%s' is incorrect.\nValid properties are '%s'.",
+				keyOrder[0],
+				strings.Join(sortKeys, ", "),
+			),
+		)
+
+	CheckOrder:
+		switch keyOrder[1] {
+		case "asc", "desc":
+
+		default:
+			PrintError(
+				fmt.Sprintf(
+					"Provided order '%s' for sort property '%s' is incorrect.\nValid orders are 'asc, desc'.",
+					keyOrder[1], keyOrder[0],
+				),
+			)
+		}
+
+		sortSpec = append(sortSpec, keyOrder[0]+":"+keyOrder[1])
 	}
 
-	PrintError(optionReceiveDir + ": Directory is not accessible.")
+	AddProperty("device-sort", strings.Join(sortSpec, ","))
 }
 
-func cmdOptionGsm() {
-	optionGsmNumber := GetProperty("gsm-number")
-	optionGsmApn := GetProperty("gsm-apn")
-
-	if optionGsmNumber == "" && optionGsmApn != "" {
-		PrintError("Specify GSM Number.")
+func cmdOptionStatusbarSegments() {
+	optionStatusbarSegments := GetProperty("statusbar-segments")
+	if optionStatusbarSegments == "" {
+		return
 	}
 
-	number := "*99#"
-	if optionGsmNumber != "" {
-		number = optionGsmNumber
+	var segmentSpec []string
+
+	for _, sa := range strings.Split(optionStatusbarSegments, ",") {
+		segmentAlign := strings.FieldsFunc(sa, func(r rune) bool {
+			return r == ' ' || r == ':'
+		})
+		if len(segmentAlign) == 1 {
+			segmentAlign = append(segmentAlign, "left")
+		}
+		if len(segmentAlign) != 2 {
+			PrintError(
+				fmt.Sprintf(
+					"Provided segment:alignment format '%s' is incorrect.",
+					sa,
+				),
+			)
+		}
+
+		for _, segment := range statusbarSegments {
+			if segmentAlign[0] == segment {
+				goto CheckAlignment
+			}
+		}
+		PrintError(
+			fmt.Sprintf(
+				"Provided status bar segment '%s' is incorrect.\nValid segments are '%s'.",
+				segmentAlign[0],
+				strings.Join(statusbarSegments, ", "),
+			),
+		)
+
+	CheckAlignment:
+		switch segmentAlign[1] {
+		case "left", "right":
+
+		default:
+			PrintError(
+				fmt.Sprintf(
+					"Provided alignment '%s' for status bar segment '%s' is incorrect.\nValid alignments are 'left, right'.",
+					segmentAlign[1], segmentAlign[0],
+				),
+			)
+		}
+
+		segmentSpec = append(segmentSpec, segmentAlign[0]+":"+segmentAlign[1])
 	}
 
-	AddProperty("gsm-apn", optionGsmApn)
-	AddProperty("gsm-number", number)
+	AddProperty("statusbar-segments", strings.Join(segmentSpec, ","))
 }
 
-func cmdOptionTheme() {
-	if !config.Exists("theme") {
+func cmdOptionConnectBDAddr(b *bluez.Bluez) {
+	optionConnectBDAddr := GetProperty("connect-bdaddr")
+	if optionConnectBDAddr == "" {
 		return
 	}
 
-	optionTheme := config.Get("theme")
-	if t, ok := optionTheme.(string); ok {
-		themeConfig, err := hjson.Parser().Unmarshal([]byte(t))
-		if err != nil {
-			PrintError("Provided theme format is invalid", err)
+	adapter := b.GetCurrentAdapter()
+	if adapter.Path == "" {
+		return
+	}
+
+	var timeout <-chan time.Time
+	var duration time.Duration
+	if optionConnectTimeout := GetProperty("connect-timeout"); optionConnectTimeout != "" {
+		// The timeout is a number of seconds, or a duration like the "timeout" option.
+		seconds, err := strconv.Atoi(optionConnectTimeout)
+		if err == nil {
+			duration = time.Duration(seconds) * time.Second
+		} else {
+			duration, err = time.ParseDuration(optionConnectTimeout)
 		}
 
-		optionTheme = themeConfig
+		if err != nil || duration <= 0 {
+			PrintError(
+				fmt.Sprintf(
+					"Provided connect timeout '%s' is incorrect.\nThe value must be a number of seconds or a duration greater than 0, for example '30' or '1m'.",
+					optionConnectTimeout,
+				),
+			)
+		}
+
+		timeout = time.After(duration)
 	}
 
-	config.Set("theme", optionTheme)
+	var addresses, errors []string
 
-	themeMap := config.StringMap("theme")
-	if len(themeMap) == 0 {
-		return
+	for _, address := range strings.Split(optionConnectBDAddr, ",") {
+		address = strings.ToUpper(strings.TrimSpace(address))
+		if address == "" {
+			continue
+		}
+
+		var device bluez.Device
+		for _, d := range b.GetDevices() {
+			if d.Address == address {
+				device = d
+				break
+			}
+		}
+
+		if device.Path == "" && timeout != nil {
+			Print(fmt.Sprintf("Waiting up to %s for device '%s'...", duration, address))
+
+			device = awaitDevice(b, adapter, address, timeout)
+			if device.Path == "" {
+				errors = append(errors, fmt.Sprintf("Device '%s' did not come into range within %s.", address, duration))
+				continue
+			}
+
+			Print(fmt.Sprintf("Found device '%s' (%s)", device.Name, device.Address))
+		}
+
+		switch {
+		case device.Path == "":
+			errors = append(
+				errors,
+				fmt.Sprintf(
+					"No device with address '%s' found on adapter '%s' (%s)",
+					address,
+					adapter.Name,
+					filepath.Base(adapter.Path),
+				),
+			)
+
+		case GetDeviceConnectDirection(device.Address) == "incoming":
+			errors = append(
+				errors,
+				fmt.Sprintf(
+					"Device '%s' only accepts incoming connections, and must initiate the connection itself.",
+					device.Address,
+				),
+			)
+
+		default:
+			addresses = append(addresses, device.Address)
+		}
 	}
 
-	if err := theme.ParseThemeConfig(themeMap); err != nil {
-		PrintError(err.Error())
+	if addresses == nil && errors != nil {
+		for _, err := range errors[:len(errors)-1] {
+			PrintWarn(err)
+		}
+
+		PrintError(errors[len(errors)-1])
 	}
+
+	// Connect to the devices which were found, and report the
+	// other devices after the application is closed.
+	for _, err := range errors {
+		AddExitError(err)
+	}
+
+	AddProperty("connect-bdaddr", strings.Join(addresses, ","))
 }
 
-func cmdOptionGenerate() {
-	optionGenerate := IsPropertyEnabled("generate")
-	if !optionGenerate {
+func cmdOptionAwaitConnect(b *bluez.Bluez) {
+	optionAwaitConnect := strings.ToUpper(GetProperty("await-connect"))
+	if optionAwaitConnect == "" {
 		return
 	}
 
-	generate()
+	if mac, err := net.ParseMAC(optionAwaitConnect); err != nil || len(mac) != 6 {
+		PrintError(
+			fmt.Sprintf(
+				"Provided device address '%s' is incorrect.",
+				optionAwaitConnect,
+			),
+		)
+	}
 
-	os.Exit(0)
+	var timeout <-chan time.Time
+	if optionTimeout := GetProperty("timeout"); optionTimeout != "" {
+		if err := validateDuration(optionTimeout); err != nil {
+			PrintError(
+				fmt.Sprintf(
+					"Provided timeout '%s' is incorrect: %s",
+					optionTimeout, err.Error(),
+				),
+			)
+		}
+
+		duration, _ := time.ParseDuration(optionTimeout)
+		timeout = time.After(duration)
+	}
+
+	adapter := b.GetCurrentAdapter()
+	if adapter.Path == "" {
+		PrintError("No adapters found.")
+	}
+
+	Print(fmt.Sprintf("Waiting for device '%s'...", optionAwaitConnect))
+
+	device := awaitDevice(b, adapter, optionAwaitConnect, timeout)
+	if device.Path == "" {
+		PrintError(
+			fmt.Sprintf(
+				"Device '%s' did not come into range.",
+				optionAwaitConnect,
+			),
+		)
+	}
+
+	if err := b.Connect(device.Path); err != nil {
+		PrintError(
+			fmt.Sprintf(
+				"Could not connect to device '%s': %s",
+				optionAwaitConnect, err.Error(),
+			),
+		)
+	}
+
+	Print(fmt.Sprintf("Connected to '%s' (%s)", device.Name, device.Address), 0)
+}
+
+// awaitDevice scans on the adapter until the device with the provided address comes
+// into range, and returns the device. If the timeout elapses before the device comes
+// into range, an empty device is returned.
+func awaitDevice(b *bluez.Bluez, adapter bluez.Adapter, address string, timeout <-chan time.Time) bluez.Device {
+	signals := b.WatchSignal()
+	defer b.Conn().RemoveSignal(signals)
+
+	if err := b.StartDiscovery(adapter.Path); err != nil {
+		PrintError(
+			fmt.Sprintf(
+				"Could not start discovery on adapter '%s': %s",
+				filepath.Base(adapter.Path), err.Error(),
+			),
+		)
+	}
+	defer b.StopDiscovery(adapter.Path)
+
+	for {
+		var device bluez.Device
+
+		select {
+		case <-timeout:
+			return bluez.Device{}
+
+		case signal, ok := <-signals:
+			if !ok {
+				PrintError("The bluez DBus connection was closed.")
+			}
+
+			inRange := signal.Name == "org.freedesktop.DBus.ObjectManager.InterfacesAdded"
+			if signal.Name == "org.freedesktop.DBus.Properties.PropertiesChanged" && len(signal.Body) > 1 {
+				if props, ok := signal.Body[1].(map[string]dbus.Variant); ok {
+					_, inRange = props["RSSI"]
+				}
+			}
+
+			switch data := b.ParseSignalData(signal).(type) {
+			case bluez.Adapter:
+				if data.Path == adapter.Path && !data.Discovering {
+					b.StartDiscovery(adapter.Path)
+				}
+
+			case bluez.Device:
+				device = data
+
+			case map[string][]bluez.Device:
+				for _, devices := range data {
+					for _, d := range devices {
+						device = d
+					}
+				}
+			}
+
+			if !inRange || device.Adapter != adapter.Path ||
+				strings.ToUpper(device.Address) != address {
+				continue
+			}
+		}
+
+		return device
+	}
+}
+
+func cmdOptionAirplane(b *bluez.Bluez) {
+	var enable bool
+
+	optionAirplane := GetProperty("airplane")
+	switch optionAirplane {
+	case "":
+		return
+
+	case "on":
+		enable = true
+
+	case "off":
+
+	default:
+		PrintError(
+			fmt.Sprintf(
+				"Provided airplane mode state '%s' is incorrect.\nValid states are 'on' or 'off'.",
+				optionAirplane,
+			),
+		)
+	}
+
+	if err := SetAirplaneMode(b, enable); err != nil {
+		PrintError(err.Error())
+	}
+
+	Print("Airplane mode is "+optionAirplane, 0)
+}
+
+func cmdOptionRSSI() {
+	optionRSSIFormat := GetProperty("rssi-format")
+	if optionRSSIFormat != "dbm" && optionRSSIFormat != "bars" {
+		PrintError(
+			fmt.Sprintf(
+				"Provided signal strength format '%s' is incorrect.\nValid formats are 'dbm' or 'bars'.",
+				optionRSSIFormat,
+			),
+		)
+	}
+
+	optionRSSIThresholds := GetProperty("rssi-thresholds")
+
+	thresholds := strings.Split(optionRSSIThresholds, ",")
+	if len(thresholds) != 4 {
+		PrintError(
+			fmt.Sprintf(
+				"Provided signal strength thresholds '%s' are incorrect.\nExactly four thresholds must be specified.",
+				optionRSSIThresholds,
+			),
+		)
+	}
+
+	previous := math.MinInt16
+	for i, threshold := range thresholds {
+		threshold = strings.TrimSpace(threshold)
+		thresholds[i] = threshold
+
+		value, err := strconv.ParseInt(threshold, 10, 16)
+		if err != nil || int(value) <= previous {
+			PrintError(
+				fmt.Sprintf(
+					"Provided signal strength thresholds '%s' are incorrect.\nThe thresholds must be increasing numbers in dBm.",
+					optionRSSIThresholds,
+				),
+			)
+		}
+
+		previous = int(value)
+	}
+
+	AddProperty("rssi-thresholds", strings.Join(thresholds, ","))
+}
+
+func cmdOptionRemoveProtection() {
+	optionRemoveProtection := GetProperty("remove-protection")
+
+	switch optionRemoveProtection {
+	case "confirm", "block":
+		return
+	}
+
+	PrintError(
+		fmt.Sprintf(
+			"Provided remove protection '%s' is incorrect.\nValid values are 'confirm' or 'block'.",
+			optionRemoveProtection,
+		),
+	)
+}
+
+func cmdOptionReceiveDir() {
+	optionReceiveDir := GetProperty("receive-dir")
+	if optionReceiveDir == "" {
+		return
+	}
+
+	if statpath, err := os.Stat(optionReceiveDir); err == nil && statpath.IsDir() {
+		AddProperty("receive-dir", optionReceiveDir)
+		return
+	}
+
+	PrintError(optionReceiveDir + ": Directory is not accessible.")
+}
+
+func cmdOptionLogFile(b *bluez.Bluez) {
+	optionLogFile := GetProperty("log-file")
+	if optionLogFile == "" {
+		return
+	}
+
+	logFile, err := filepath.Abs(optionLogFile)
+	if err != nil {
+		PrintError(fmt.Sprintf("Provided log file '%s' is incorrect", optionLogFile), err)
+	}
+
+	file, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		PrintError(fmt.Sprintf("Could not open log file '%s'", optionLogFile), err)
+	}
+	file.Close()
+
+	AddProperty("log-file", logFile)
+
+	b.SetOperationLogger(func(path, operation string, args []interface{}, err error) {
+		if err != nil {
+			LogError("dbus", "%s: %s: %s", logObject(b, path), operation, err)
+			return
+		}
+
+		if IsLogLevelEnabled(LogLevelDebug) {
+			LogDebug("dbus", "%s: %s %v", path, operation, args)
+		}
+
+		Log("dbus", "%s: %s", logObject(b, path), operation)
+	})
+}
+
+func cmdOptionLogLevel() {
+	optionLogLevel := GetProperty("log-level")
+
+	level, ok := logLevels[optionLogLevel]
+	if !ok {
+		PrintError(
+			fmt.Sprintf(
+				"Provided log level '%s' is incorrect.\nValid levels are 'error', 'warn', 'info' or 'debug'.",
+				optionLogLevel,
+			),
+		)
+	}
+
+	logLevel = level
+}
+
+func cmdOptionReceiveConflict() {
+	optionReceiveConflict := GetProperty("receive-conflict")
+
+	switch optionReceiveConflict {
+	case "rename", "overwrite", "skip":
+		return
+	}
+
+	PrintError(
+		fmt.Sprintf(
+			"Provided receive conflict mode '%s' is incorrect.\nValid modes are 'rename', 'overwrite' or 'skip'.",
+			optionReceiveConflict,
+		),
+	)
+}
+
+func cmdOptionReceiveMaxSize() {
+	optionReceiveMaxSize := GetProperty("receive-max-size")
+
+	if _, err := strconv.ParseUint(optionReceiveMaxSize, 10, 64); err != nil {
+		PrintError(
+			fmt.Sprintf(
+				"Provided maximum receive size '%s' is incorrect.\nThe value must be 0 or a number of bytes, for example '104857600'.",
+				optionReceiveMaxSize,
+			),
+		)
+	}
+}
+
+func cmdOptionIPCSocket() {
+	optionIPCSocket := GetProperty("ipc-socket")
+	if optionIPCSocket == "" {
+		return
+	}
+
+	socketPath, err := filepath.Abs(optionIPCSocket)
+	if err != nil {
+		PrintError(fmt.Sprintf("Provided IPC socket path '%s' is incorrect", optionIPCSocket), err)
+	}
+
+	if statpath, err := os.Stat(filepath.Dir(socketPath)); err != nil || !statpath.IsDir() {
+		PrintError(filepath.Dir(socketPath) + ": Directory is not accessible.")
+	}
+
+	if statpath, err := os.Lstat(socketPath); err == nil {
+		if statpath.Mode()&os.ModeSocket == 0 {
+			PrintError(socketPath + ": File exists and is not a socket.")
+		}
+
+		// Remove the socket if it was left behind by an instance which has exited.
+		if conn, err := net.Dial("unix", socketPath); err == nil {
+			conn.Close()
+			PrintError(socketPath + ": Another instance is listening on the socket.")
+		}
+
+		if err := os.Remove(socketPath); err != nil {
+			PrintError(fmt.Sprintf("Could not remove the stale socket '%s'", socketPath), err)
+		}
+	}
+
+	AddProperty("ipc-socket", socketPath)
+
+	if token := GetProperty("control-socket-token"); strings.ContainsAny(token, " \t") {
+		PrintError("Provided control socket token is incorrect.\nThe token must not contain any spaces.")
+	}
+}
+
+func cmdOptionMaxConcurrentConnections(b *bluez.Bluez) {
+	optionMaxConnections := GetProperty("max-concurrent-connections")
+
+	maxConnections, err := strconv.Atoi(optionMaxConnections)
+	if err != nil || maxConnections < 1 {
+		PrintError(
+			fmt.Sprintf(
+				"Provided maximum concurrent connections '%s' is incorrect.\nThe value must be a number greater than 0.",
+				optionMaxConnections,
+			),
+		)
+	}
+
+	b.SetConnectConcurrency(maxConnections)
+}
+
+func cmdOptionMaxConcurrentTransfers() {
+	optionMaxTransfers := GetProperty("max-concurrent-transfers")
+
+	maxTransfers, err := strconv.Atoi(optionMaxTransfers)
+	if err != nil || maxTransfers < 1 {
+		PrintError(
+			fmt.Sprintf(
+				"Provided maximum concurrent transfers '%s' is incorrect.\nThe value must be a number greater than 0.",
+				optionMaxTransfers,
+			),
+		)
+	}
+}
+
+func cmdOptionTransferRetries() {
+	optionTransferRetries := GetProperty("transfer-retries")
+
+	retries, err := strconv.Atoi(optionTransferRetries)
+	if err != nil || retries < 0 {
+		PrintError(
+			fmt.Sprintf(
+				"Provided transfer retries '%s' is incorrect.\nThe value must be a number greater than or equal to 0.",
+				optionTransferRetries,
+			),
+		)
+	}
+
+	optionTransferRetryDelay := GetProperty("transfer-retry-delay")
+	if delay, err := time.ParseDuration(optionTransferRetryDelay); err != nil || delay < 0 {
+		PrintError(
+			fmt.Sprintf(
+				"Provided transfer retry delay '%s' is incorrect.\nThe value must be a duration, for example '5s'.",
+				optionTransferRetryDelay,
+			),
+		)
+	}
+}
+
+func cmdOptionPresenceCheck() {
+	optionPresenceCheck := GetProperty("presence-check-interval")
+	if optionPresenceCheck == "0" {
+		return
+	}
+
+	if interval, err := time.ParseDuration(optionPresenceCheck); err != nil || interval < time.Minute {
+		PrintError(
+			fmt.Sprintf(
+				"Provided presence check interval '%s' is incorrect.\nThe value must be 0 or a duration of at least one minute, for example '5m'.",
+				optionPresenceCheck,
+			),
+		)
+	}
+}
+
+func cmdOptionAutoConnect() {
+	optionAutoConnect := GetProperty("auto-connect")
+	if optionAutoConnect == "" {
+		return
+	}
+
+	for _, address := range strings.Split(optionAutoConnect, ",") {
+		address = strings.TrimSpace(address)
+
+		if mac, err := net.ParseMAC(address); err != nil || len(mac) != 6 {
+			PrintError(
+				fmt.Sprintf(
+					"Provided auto-connect address '%s' is incorrect.\nThe value must be a device address, for example 'AA:BB:CC:DD:EE:FF'.",
+					address,
+				),
+			)
+		}
+	}
+}
+
+func cmdOptionAgentCapability() {
+	optionAgentCapability := GetProperty("agent-capability")
+
+	for _, capability := range agentCapabilities {
+		if optionAgentCapability == capability {
+			return
+		}
+	}
+
+	PrintError(
+		fmt.Sprintf(
+			"Provided agent capability '%s' is incorrect.\nValid capabilities are '%s'.",
+			optionAgentCapability, strings.Join(agentCapabilities, "', '"),
+		),
+	)
+}
+
+func cmdOptionAutoAcceptPairPrefixes() {
+	optionPrefixes := GetProperty("auto-accept-pair-prefixes")
+	if optionPrefixes == "" {
+		return
+	}
+
+	for _, prefix := range strings.Split(optionPrefixes, ",") {
+		prefix = strings.TrimSpace(prefix)
+
+		octets := strings.Split(prefix, ":")
+		valid := len(octets) <= 6
+
+		for _, octet := range octets {
+			if _, err := strconv.ParseUint(octet, 16, 8); err != nil || len(octet) != 2 {
+				valid = false
+				break
+			}
+		}
+
+		if !valid {
+			PrintError(
+				fmt.Sprintf(
+					"Provided address prefix '%s' is incorrect.\nThe prefix must be the beginning of an address, for example 'AA:BB:CC'.",
+					prefix,
+				),
+			)
+		}
+	}
+}
+
+func cmdOptionAgentTimeout() {
+	optionAgentTimeout := GetProperty("agent-timeout")
+	if optionAgentTimeout == "0" {
+		return
+	}
+
+	if timeout, err := time.ParseDuration(optionAgentTimeout); err != nil || timeout < 0 {
+		PrintError(
+			fmt.Sprintf(
+				"Provided agent timeout '%s' is incorrect.\nThe value must be 0 or a duration, for example '30s'.",
+				optionAgentTimeout,
+			),
+		)
+	}
+}
+
+func cmdOptionPlayerSeekStep() {
+	optionPlayerSeekStep := GetProperty("player-seek-step")
+	if optionPlayerSeekStep == "0" {
+		return
+	}
+
+	if step, err := time.ParseDuration(optionPlayerSeekStep); err != nil || step < 0 {
+		PrintError(
+			fmt.Sprintf(
+				"Provided player seek step '%s' is incorrect.\nThe value must be 0 or a duration, for example '10s'.",
+				optionPlayerSeekStep,
+			),
+		)
+	}
+}
+
+func cmdOptionScanRefresh() {
+	optionScanRefresh := GetProperty("scan-refresh-interval")
+
+	if interval, err := strconv.Atoi(optionScanRefresh); err != nil || interval < 0 {
+		PrintError(
+			fmt.Sprintf(
+				"Provided scan refresh interval '%s' is incorrect.\nThe value must be 0 or a number of seconds, for example '10'.",
+				optionScanRefresh,
+			),
+		)
+	}
+}
+
+func cmdOptionManualDisconnectCooldown() {
+	optionCooldown := GetProperty("manual-disconnect-cooldown")
+	if optionCooldown == "0" || optionCooldown == "session" {
+		return
+	}
+
+	if cooldown, err := time.ParseDuration(optionCooldown); err != nil || cooldown < 0 {
+		PrintError(
+			fmt.Sprintf(
+				"Provided manual disconnect cooldown '%s' is incorrect.\nThe value must be 0, 'session' or a duration, for example '30m'.",
+				optionCooldown,
+			),
+		)
+	}
+}
+
+func cmdOptionGsm() {
+	optionGsmNumber := GetProperty("gsm-number")
+	optionGsmApn := GetProperty("gsm-apn")
+
+	if optionGsmNumber == "" && optionGsmApn != "" {
+		PrintError("Specify GSM Number.")
+	}
+
+	number := "*99#"
+	if optionGsmNumber != "" {
+		number = optionGsmNumber
+	}
+
+	optionGsmPin := GetProperty("gsm-pin")
+	if optionGsmPin != "" {
+		if err := validateGsmPin(optionGsmPin); err != nil {
+			PrintError(fmt.Sprintf("Provided GSM PIN is incorrect: %s", err.Error()))
+		}
+	}
+
+	AddProperty("gsm-apn", optionGsmApn)
+	AddProperty("gsm-number", number)
+	AddProperty("gsm-pin", optionGsmPin)
+}
+
+func cmdOptionThemePreset() {
+	optionThemePreset := GetProperty("theme-preset")
+	if optionThemePreset == "" {
+		return
+	}
+
+	if err := theme.ParseThemePreset(optionThemePreset); err != nil {
+		PrintError(err.Error())
+	}
+}
+
+func cmdOptionTheme() {
+	if !config.Exists("theme") {
+		return
+	}
+
+	themeMap, err := parseThemeOption(config.Get("theme"))
+	if err != nil {
+		PrintError(err.Error())
+	}
+
+	config.Set("theme", themeMap)
+	if len(themeMap) == 0 {
+		return
+	}
+
+	if err := theme.ParseThemeConfig(themeMap); err != nil {
+		PrintError(err.Error())
+	}
+}
+
+// parseThemeOption returns the theme configuration from the theme option,
+// which is either a map, or a HJSON theme, a path or a http(s) URL to a HJSON theme file.
+func parseThemeOption(optionTheme interface{}) (map[string]interface{}, error) {
+	if t, ok := optionTheme.(string); ok {
+		themeData := []byte(t)
+
+		switch {
+		case strings.HasPrefix(t, "http://"), strings.HasPrefix(t, "https://"):
+			data, err := fetchTheme(t)
+			if err != nil {
+				return nil, fmt.Errorf("Could not fetch theme from '%s': %s", t, err.Error())
+			}
+
+			themeData = data
+
+		default:
+			if statpath, err := os.Stat(t); err == nil && !statpath.IsDir() {
+				data, err := os.ReadFile(t)
+				if err != nil {
+					return nil, fmt.Errorf("Could not read theme file '%s': %s", t, err.Error())
+				}
+
+				themeData = data
+			}
+		}
+
+		themeConfig, err := hjson.Parser().Unmarshal(themeData)
+		if err != nil {
+			return nil, fmt.Errorf("Provided theme format is invalid: %s", err.Error())
+		}
+
+		optionTheme = themeConfig
+	}
+
+	themeMap, _ := optionTheme.(map[string]interface{})
+
+	return themeMap, nil
+}
+
+func cmdOptionDevices() {
+	if !config.Exists("devices") {
+		return
+	}
+
+	devices := make(map[string]interface{})
+
+	for _, address := range config.MapKeys("devices") {
+		if mac, err := net.ParseMAC(address); err != nil || len(mac) != 6 {
+			PrintError(
+				fmt.Sprintf(
+					"Devices: Provided device address '%s' is incorrect.",
+					address,
+				),
+			)
+		}
+
+		properties := make(map[string]interface{})
+
+		for _, property := range config.MapKeys("devices." + address) {
+			validate, ok := deviceOptions[property]
+			if !ok {
+				var deviceProperties []string
+				for name := range deviceOptions {
+					deviceProperties = append(deviceProperties, name)
+				}
+				sort.Strings(deviceProperties)
+
+				PrintError(
+					fmt.Sprintf(
+						"Devices: Provided property '%s' for device '%s' is incorrect.\nValid properties are '%s'.",
+						property, address,
+						strings.Join(deviceProperties, ", "),
+					),
+				)
+			}
+
+			value := config.String("devices." + address + "." + property)
+			if err := validate(value); err != nil {
+				PrintError(
+					fmt.Sprintf(
+						"Devices: Provided value '%s' for property '%s' of device '%s' is incorrect: %s",
+						value, property, address, err.Error(),
+					),
+				)
+			}
+
+			properties[property] = value
+		}
+
+		devices[strings.ToUpper(address)] = properties
+	}
+
+	config.Delete("devices")
+	AddProperty("devices", devices)
+}
+
+func validateBoolean(value string) error {
+	_, err := strconv.ParseBool(value)
+
+	return err
+}
+
+func validateAdapterID(value string) error {
+	if !strings.HasPrefix(value, "hci") {
+		return fmt.Errorf("The adapter must be specified as 'hci<number>'")
+	}
+
+	if _, err := strconv.ParseUint(strings.TrimPrefix(value, "hci"), 10, 16); err != nil {
+		return fmt.Errorf("The adapter must be specified as 'hci<number>'")
+	}
+
+	return nil
+}
+
+func validateAdapterList(value string) error {
+	for _, adapterID := range strings.Split(value, ",") {
+		if err := validateAdapterID(strings.TrimSpace(adapterID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateUUIDList(value string) error {
+	for _, profileUUID := range strings.Split(value, ",") {
+		profileUUID = strings.TrimSpace(profileUUID)
+		if profileUUID == "" {
+			continue
+		}
+
+		if _, err := uuid.Parse(profileUUID); err != nil {
+			return fmt.Errorf("The profile UUID '%s' is incorrect", profileUUID)
+		}
+	}
+
+	return nil
+}
+
+func validateConnectDirection(value string) error {
+	switch value {
+	case "outgoing", "incoming", "both":
+		return nil
+	}
+
+	return fmt.Errorf("The connection direction must be 'outgoing', 'incoming' or 'both'")
+}
+
+func validateTags(value string) error {
+	for _, tag := range strings.Split(value, ",") {
+		if err := ValidateTag(strings.TrimSpace(tag)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateGsmPin(value string) error {
+	if len(value) < 4 || len(value) > 8 {
+		return fmt.Errorf("The PIN must be 4 to 8 digits long")
+	}
+
+	for _, digit := range value {
+		if digit < '0' || digit > '9' {
+			return fmt.Errorf("The PIN must only contain digits")
+		}
+	}
+
+	return nil
+}
+
+func validateDuration(value string) error {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+
+	if duration <= 0 {
+		return fmt.Errorf("The duration must be greater than zero")
+	}
+
+	return nil
+}
+
+// fetchTheme returns the theme from the provided URL. If the theme was cached
+// in the configuration directory, the cached theme is returned, and the cache
+// is refreshed in the background, so that an updated theme is used on the next
+// launch. Otherwise, the theme is downloaded and cached.
+func fetchTheme(themeURL string) ([]byte, error) {
+	cachePath := filepath.Join(
+		config.path,
+		fmt.Sprintf("theme-%x.hjson", sha256.Sum256([]byte(themeURL))),
+	)
+
+	if cached, err := os.ReadFile(cachePath); err == nil {
+		go func() {
+			if err := cacheTheme(themeURL, cachePath); err != nil {
+				LogError("theme", "Could not refresh the cached theme from %s: %s", themeURL, err)
+			}
+		}()
+
+		return cached, nil
+	}
+
+	data, err := downloadTheme(themeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(cachePath, data, 0600); err != nil {
+		PrintWarn("Theme: The downloaded theme could not be cached")
+	}
+
+	return data, nil
+}
+
+// cacheTheme downloads the theme from the provided URL, and replaces the
+// cached theme with it. The theme is written to a temporary file first, so
+// that the cached theme is not left incomplete if the application exits.
+func cacheTheme(themeURL, cachePath string) error {
+	data, err := downloadTheme(themeURL)
+	if err != nil {
+		return err
+	}
+
+	tempPath := cachePath + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, cachePath)
+}
+
+// downloadTheme downloads and validates a theme from the provided URL.
+func downloadTheme(themeURL string) ([]byte, error) {
+	const maxThemeSize = 64 * 1024
+
+	client := http.Client{Timeout: 10 * time.Second}
+
+	response, err := client.Get(themeURL)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Server returned status '%s'", response.Status)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	switch {
+	case contentType == "", strings.HasPrefix(contentType, "text/"),
+		contentType == "application/json", contentType == "application/hjson",
+		contentType == "application/octet-stream":
+
+	default:
+		return nil, fmt.Errorf("Content type '%s' is not supported", contentType)
+	}
+
+	if response.ContentLength > maxThemeSize {
+		return nil, fmt.Errorf("Theme is larger than %d bytes", maxThemeSize)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxThemeSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxThemeSize {
+		return nil, fmt.Errorf("Theme is larger than %d bytes", maxThemeSize)
+	}
+
+	if _, err := hjson.Parser().Unmarshal(data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func cmdOptionGenerate() {
+	optionGenerate := IsPropertyEnabled("generate")
+	if !optionGenerate {
+		return
+	}
+
+	generate()
+
+	os.Exit(0)
+}
+
+func cmdOptionConfigKeys() {
+	type configKey struct {
+		Name        string `json:"name"`
+		Type        string `json:"type"`
+		Default     string `json:"default"`
+		Description string `json:"description"`
+	}
+
+	if !IsPropertyEnabled("config-keys") {
+		return
+	}
+
+	var deviceProperties []string
+	for name := range deviceOptions {
+		deviceProperties = append(deviceProperties, name)
+	}
+	sort.Strings(deviceProperties)
+
+	keys := make([]configKey, 0, len(options)+4)
+	for _, option := range options {
+		// The theme is listed with the other configuration maps below.
+		if option.Name == "theme" {
+			continue
+		}
+
+		key := configKey{
+			Name:        option.Name,
+			Type:        "string",
+			Default:     option.Value,
+			Description: option.Description,
+		}
+		if option.IsBoolean {
+			key.Type = "boolean"
+			key.Default = "false"
+		}
+		if option.IsList {
+			key.Type = "list"
+		}
+
+		keys = append(keys, key)
+	}
+
+	keys = append(keys, []configKey{
+		{
+			Name:        "keybindings",
+			Type:        "map",
+			Description: "Specify keybindings, as a map of key names to key combinations. (For example, '{ DeviceConnect: \"Ctrl+c\" }')",
+		},
+		{
+			Name:        "theme",
+			Type:        "map",
+			Description: "Specify a theme, as a map of theme contexts to colors or style attributes ('" + strings.Join(theme.StyleAttributes, "', '") + "'). Colors can be color names, or quoted hex values in the '#rrggbb' or '#rgb' format. The theme can also be a path or http(s) URL to a HJSON theme file. (For example, '{ Adapter: \"red\", Device: { color: \"#1a2b3c\", bold: true } }')",
+		},
+		{
+			Name:        "devices",
+			Type:        "map",
+			Description: "Specify per-device properties, keyed by the device address. Valid properties are '" + strings.Join(deviceProperties, "', '") + "'.",
+		},
+		{
+			Name:        "connection-profiles",
+			Type:        "map",
+			Description: "Specify connection profiles, as a map of names to device addresses with optional comma-separated profile UUIDs. A profile can also include a 'discovery-filter', which is set on the current adapter when the profile is activated.",
+		},
+	}...)
+
+	if GetProperty("output-format") == "json" {
+		data, err := json.MarshalIndent(keys, "", "  ")
+		if err != nil {
+			PrintError(err.Error())
+		}
+
+		Print(string(data), 0)
+	}
+
+	var text string
+	for _, key := range keys {
+		text += fmt.Sprintf("%s (%s", key.Name, key.Type)
+		if key.Default != "" {
+			text += ", default '" + key.Default + "'"
+		}
+		text += ")\n    " + key.Description + "\n"
+	}
+
+	Print(strings.TrimRight(text, "\n"), 0)
+}
+
+func cmdOptionDumpKeybindings() {
+	type dumpedKey struct {
+		Name    string `json:"name"`
+		Title   string `json:"title"`
+		Context string `json:"context"`
+		Key     string `json:"key"`
+	}
+
+	if !IsPropertyEnabled("dump-keybindings") {
+		return
+	}
+
+	validateKeybindings()
+
+	keys := make([]dumpedKey, 0, len(OperationKeys))
+	for name, data := range OperationKeys {
+		keys = append(keys, dumpedKey{
+			Name:    string(name),
+			Title:   data.Title,
+			Context: string(data.Context),
+			Key:     KeyName(data.Kb),
+		})
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Context != keys[j].Context {
+			return keys[i].Context < keys[j].Context
+		}
+
+		return keys[i].Name < keys[j].Name
+	})
+
+	if GetProperty("output-format") == "json" {
+		data, err := json.MarshalIndent(keys, "", "  ")
+		if err != nil {
+			PrintError(err.Error())
+		}
+
+		Print(string(data), 0)
+	}
+
+	var text, context string
+	for _, key := range keys {
+		if key.Context != context {
+			context = key.Context
+			text += context + ":\n"
+		}
+
+		text += fmt.Sprintf("  %-32s %-28s %s\n", key.Name, key.Title, key.Key)
+	}
+
+	Print(strings.TrimRight(text, "\n"), 0)
+}
+
+func cmdOptionDBusSystemAddress() {
+	optionDBusSystemAddress := GetProperty("dbus-system-address")
+	if optionDBusSystemAddress == "" {
+		return
+	}
+
+	for _, address := range strings.Split(optionDBusSystemAddress, ";") {
+		transport, params, ok := strings.Cut(address, ":")
+		if !ok || transport == "" || params == "" {
+			PrintError(
+				fmt.Sprintf(
+					"Provided D-Bus address '%s' is incorrect.\nThe address must be in the 'transport:key=value' format, for example 'unix:path=/run/dbus/system_bus_socket'.",
+					optionDBusSystemAddress,
+				),
+			)
+		}
+	}
+}
+
+func cmdOptionNoColor() {
+	if !IsPropertyEnabled("no-color") && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" {
+		return
+	}
+
+	color.NoColor = true
+	theme.DisableColors()
 }
 
 func cmdOptionVersion() {
@@ -43,17 +43,39 @@ const (
 	KeyAdapterChange               Key = "AdapterChange"
 	KeyAdapterTogglePower          Key = "AdapterTogglePower"
 	KeyAdapterToggleDiscoverable   Key = "AdapterToggleDiscoverable"
+	KeyAdapterDiscoverablePreset1  Key = "AdapterDiscoverablePreset1"
+	KeyAdapterDiscoverablePreset2  Key = "AdapterDiscoverablePreset2"
+	KeyAdapterDiscoverablePreset3  Key = "AdapterDiscoverablePreset3"
 	KeyAdapterTogglePairable       Key = "AdapterTogglePairable"
 	KeyAdapterToggleScan           Key = "AdapterToggleScan"
+	KeyAdapterToggleAirplane       Key = "AdapterToggleAirplane"
+	KeyAdapterToggleFilter         Key = "AdapterToggleFilter"
+	KeyAdapterToggleTransport      Key = "AdapterToggleTransport"
+	KeyAdapterDisconnectAll        Key = "AdapterDisconnectAll"
+	KeyAdapterRemoveUnpaired       Key = "AdapterRemoveUnpaired"
+	KeyAdapterInfo                 Key = "AdapterInfo"
+	KeyConnectionProfiles          Key = "ConnectionProfiles"
+	KeyToggleRSSIFormat            Key = "ToggleRSSIFormat"
 	KeyDeviceSendFiles             Key = "DeviceSendFiles"
+	KeyDeviceBrowseFiles           Key = "DeviceBrowseFiles"
 	KeyDeviceNetwork               Key = "DeviceNetwork"
+	KeyDeviceTether                Key = "DeviceTether"
 	KeyDeviceConnect               Key = "DeviceConnect"
 	KeyDevicePair                  Key = "DevicePair"
 	KeyDeviceTrust                 Key = "DeviceTrust"
 	KeyDeviceBlock                 Key = "DeviceBlock"
 	KeyDeviceAudioProfiles         Key = "DeviceAudioProfiles"
+	KeyDeviceProfiles              Key = "DeviceProfiles"
+	KeyDeviceGatt                  Key = "DeviceGatt"
 	KeyDeviceInfo                  Key = "DeviceInfo"
+	KeyDeviceServiceRecords        Key = "DeviceServiceRecords"
 	KeyDeviceRemove                Key = "DeviceRemove"
+	KeyDeviceRename                Key = "DeviceRename"
+	KeyDeviceEditTags              Key = "DeviceEditTags"
+	KeyDeviceFilterTag             Key = "DeviceFilterTag"
+	KeyDeviceSearch                Key = "DeviceSearch"
+	KeyDeviceFilterClass           Key = "DeviceFilterClass"
+	KeyDeviceToggleSort            Key = "DeviceToggleSort"
 	KeyPlayerShow                  Key = "PlayerShow"
 	KeyPlayerHide                  Key = "PlayerHide"
 	KeyFilebrowserDirForward       Key = "FilebrowserDirForward"
@@ -68,12 +90,22 @@ const (
 	KeyProgressTransferSuspend     Key = "ProgressTransferSuspend"
 	KeyProgressTransferResume      Key = "ProgressTransferResume"
 	KeyProgressTransferCancel      Key = "ProgressTransferCancel"
+	KeyProgressTransferPreview     Key = "ProgressTransferPreview"
+	KeyProgressTransferRetry       Key = "ProgressTransferRetry"
+	KeyProgressTransferMoveUp      Key = "ProgressTransferMoveUp"
+	KeyProgressTransferMoveDown    Key = "ProgressTransferMoveDown"
+	KeyProgressTransferRemove      Key = "ProgressTransferRemove"
 	KeyPlayerTogglePlay            Key = "PlayerTogglePlay"
 	KeyPlayerNext                  Key = "PlayerNext"
 	KeyPlayerPrevious              Key = "PlayerPrevious"
 	KeyPlayerSeekForward           Key = "PlayerSeekForward"
 	KeyPlayerSeekBackward          Key = "PlayerSeekBackward"
 	KeyPlayerStop                  Key = "PlayerStop"
+	KeyPlayerVolumeUp              Key = "PlayerVolumeUp"
+	KeyPlayerVolumeDown            Key = "PlayerVolumeDown"
+	KeyGattRead                    Key = "GattRead"
+	KeyGattWrite                   Key = "GattWrite"
+	KeyGattToggleNotify            Key = "GattToggleNotify"
 	KeyNavigateUp                  Key = "NavigateUp"
 	KeyNavigateDown                Key = "NavigateDown"
 	KeyNavigateRight               Key = "NavigateRight"
@@ -92,6 +124,7 @@ const (
 	KeyContextDevice   KeyContext = "Device"
 	KeyContextFiles    KeyContext = "Files"
 	KeyContextProgress KeyContext = "Progress"
+	KeyContextGatt     KeyContext = "Gatt"
 )
 
 var (
@@ -184,6 +217,21 @@ var (
 			Context: KeyContextDevice,
 			Kb:      Keybinding{tcell.KeyRune, 'S', tcell.ModNone},
 		},
+		KeyAdapterDiscoverablePreset1: {
+			Title:   "Discoverable (1m)",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, '1', tcell.ModNone},
+		},
+		KeyAdapterDiscoverablePreset2: {
+			Title:   "Discoverable (5m)",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, '2', tcell.ModNone},
+		},
+		KeyAdapterDiscoverablePreset3: {
+			Title:   "Discoverable (permanent)",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, '3', tcell.ModNone},
+		},
 		KeyAdapterTogglePairable: {
 			Title:   "Pairable",
 			Context: KeyContextDevice,
@@ -194,6 +242,46 @@ var (
 			Context: KeyContextDevice,
 			Kb:      Keybinding{tcell.KeyRune, 's', tcell.ModNone},
 		},
+		KeyAdapterToggleFilter: {
+			Title:   "Discovery Filter",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'F', tcell.ModNone},
+		},
+		KeyAdapterToggleTransport: {
+			Title:   "Scan Transport",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'W', tcell.ModNone},
+		},
+		KeyAdapterDisconnectAll: {
+			Title:   "Disconnect All",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'X', tcell.ModNone},
+		},
+		KeyAdapterRemoveUnpaired: {
+			Title:   "Remove Unpaired",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'Z', tcell.ModNone},
+		},
+		KeyAdapterToggleAirplane: {
+			Title:   "Airplane Mode",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'w', tcell.ModNone},
+		},
+		KeyAdapterInfo: {
+			Title:   "Info",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'I', tcell.ModNone},
+		},
+		KeyConnectionProfiles: {
+			Title:   "Connection Profiles",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'C', tcell.ModNone},
+		},
+		KeyToggleRSSIFormat: {
+			Title:   "Signal Bars",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'r', tcell.ModNone},
+		},
 		KeyAdapterChange: {
 			Title:   "Change",
 			Context: KeyContextDevice,
@@ -224,26 +312,81 @@ var (
 			Context: KeyContextDevice,
 			Kb:      Keybinding{tcell.KeyRune, 'f', tcell.ModNone},
 		},
+		KeyDeviceBrowseFiles: {
+			Title:   "Browse Files",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'B', tcell.ModNone},
+		},
 		KeyDeviceNetwork: {
 			Title:   "Network Options",
 			Context: KeyContextDevice,
 			Kb:      Keybinding{tcell.KeyRune, 'n', tcell.ModNone},
 		},
+		KeyDeviceTether: {
+			Title:   "Tethering",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'U', tcell.ModNone},
+		},
 		KeyDeviceAudioProfiles: {
 			Title:   "Audio Profiles",
 			Context: KeyContextDevice,
 			Kb:      Keybinding{tcell.KeyRune, 'A', tcell.ModNone},
 		},
+		KeyDeviceProfiles: {
+			Title:   "Bluetooth Profiles",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'H', tcell.ModNone},
+		},
+		KeyDeviceGatt: {
+			Title:   "GATT Characteristics",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'G', tcell.ModNone},
+		},
 		KeyDeviceInfo: {
 			Title:   "Info",
 			Context: KeyContextDevice,
 			Kb:      Keybinding{tcell.KeyRune, 'i', tcell.ModNone},
 		},
+		KeyDeviceServiceRecords: {
+			Title:   "Service Records",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'R', tcell.ModNone},
+		},
 		KeyDeviceRemove: {
 			Title:   "Remove",
 			Context: KeyContextDevice,
 			Kb:      Keybinding{tcell.KeyRune, 'd', tcell.ModNone},
 		},
+		KeyDeviceRename: {
+			Title:   "Rename",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'N', tcell.ModNone},
+		},
+		KeyDeviceEditTags: {
+			Title:   "Edit Tags",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'E', tcell.ModNone},
+		},
+		KeyDeviceSearch: {
+			Title:   "Search",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, '/', tcell.ModNone},
+		},
+		KeyDeviceFilterTag: {
+			Title:   "Filter By Tag",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'T', tcell.ModNone},
+		},
+		KeyDeviceFilterClass: {
+			Title:   "Filter By Class",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'L', tcell.ModNone},
+		},
+		KeyDeviceToggleSort: {
+			Title:   "Toggle Sort",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, 'O', tcell.ModNone},
+		},
 		KeyPlayerShow: {
 			Title:   "Show Media Player",
 			Context: KeyContextDevice,
@@ -284,6 +427,31 @@ var (
 			Context: KeyContextDevice,
 			Kb:      Keybinding{tcell.KeyRune, ']', tcell.ModNone},
 		},
+		KeyPlayerVolumeUp: {
+			Title:   "Volume Up",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, '+', tcell.ModNone},
+		},
+		KeyPlayerVolumeDown: {
+			Title:   "Volume Down",
+			Context: KeyContextDevice,
+			Kb:      Keybinding{tcell.KeyRune, '-', tcell.ModNone},
+		},
+		KeyGattRead: {
+			Title:   "Read Value",
+			Context: KeyContextGatt,
+			Kb:      Keybinding{tcell.KeyRune, 'r', tcell.ModNone},
+		},
+		KeyGattWrite: {
+			Title:   "Write Value",
+			Context: KeyContextGatt,
+			Kb:      Keybinding{tcell.KeyRune, 'w', tcell.ModNone},
+		},
+		KeyGattToggleNotify: {
+			Title:   "Toggle Notifications",
+			Context: KeyContextGatt,
+			Kb:      Keybinding{tcell.KeyRune, 'n', tcell.ModNone},
+		},
 		KeyFilebrowserConfirmSelection: {
 			Title:   "Confirm Selection",
 			Context: KeyContextFiles,
@@ -334,6 +502,31 @@ var (
 			Context: KeyContextProgress,
 			Kb:      Keybinding{tcell.KeyRune, 'x', tcell.ModNone},
 		},
+		KeyProgressTransferPreview: {
+			Title:   "Preview Received File",
+			Context: KeyContextProgress,
+			Kb:      Keybinding{tcell.KeyRune, 'e', tcell.ModNone},
+		},
+		KeyProgressTransferRetry: {
+			Title:   "Retry Transfer",
+			Context: KeyContextProgress,
+			Kb:      Keybinding{tcell.KeyRune, 'r', tcell.ModNone},
+		},
+		KeyProgressTransferMoveUp: {
+			Title:   "Move Transfer Up",
+			Context: KeyContextProgress,
+			Kb:      Keybinding{tcell.KeyRune, '[', tcell.ModNone},
+		},
+		KeyProgressTransferMoveDown: {
+			Title:   "Move Transfer Down",
+			Context: KeyContextProgress,
+			Kb:      Keybinding{tcell.KeyRune, ']', tcell.ModNone},
+		},
+		KeyProgressTransferRemove: {
+			Title:   "Remove Transfer",
+			Context: KeyContextProgress,
+			Kb:      Keybinding{tcell.KeyRune, 'd', tcell.ModNone},
+		},
 		KeyProgressView: {
 			Title:   "View Downloads",
 			Context: KeyContextProgress,
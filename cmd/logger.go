@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+)
+
+// Logger describes the debug log.
+type Logger struct {
+	*log.Logger
+
+	file *os.File
+	lock sync.Mutex
+}
+
+// LogLevel describes the level of detail of the log file.
+type LogLevel int
+
+// The different log levels.
+const (
+	LogLevelError LogLevel = iota
+	LogLevelWarn
+	LogLevelInfo
+	LogLevelDebug
+)
+
+// logLevels matches the names of the log levels with their values.
+var logLevels = map[string]LogLevel{
+	"error": LogLevelError,
+	"warn":  LogLevelWarn,
+	"info":  LogLevelInfo,
+	"debug": LogLevelDebug,
+}
+
+var (
+	logger   Logger
+	logLevel = LogLevelInfo
+)
+
+// LogAgent writes an agent interaction to the debug log,
+// if the "log-agent" option is enabled.
+func LogAgent(format string, v ...interface{}) {
+	if !IsPropertyEnabled("log-agent") {
+		return
+	}
+
+	logger.write("agent", fmt.Sprintf(format, v...))
+}
+
+// Log writes an event to the log file.
+func Log(category, format string, v ...interface{}) {
+	logLevelWrite(LogLevelInfo, category, format, v...)
+}
+
+// LogError writes an error to the log file.
+func LogError(category, format string, v ...interface{}) {
+	logLevelWrite(LogLevelError, category, format, v...)
+}
+
+// LogWarn writes a warning to the log file.
+func LogWarn(category, format string, v ...interface{}) {
+	logLevelWrite(LogLevelWarn, category, format, v...)
+}
+
+// LogDebug writes a debug message to the log file.
+func LogDebug(category, format string, v ...interface{}) {
+	logLevelWrite(LogLevelDebug, category, format, v...)
+}
+
+// IsLogLevelEnabled returns if messages of the provided level are written to the log file.
+func IsLogLevelEnabled(level LogLevel) bool {
+	return level <= logLevel && GetProperty("log-file") != ""
+}
+
+// logLevelWrite writes a message to the log file, if the "log-file" option
+// is set and the level of the message is within the "log-level" option.
+func logLevelWrite(level LogLevel, category, format string, v ...interface{}) {
+	if !IsLogLevelEnabled(level) {
+		return
+	}
+
+	logger.write(category, fmt.Sprintf(format, v...))
+}
+
+// LogReceive prints and logs an event of the "receive" option.
+func LogReceive(format string, v ...interface{}) {
+	message := fmt.Sprintf(format, v...)
+
+	Print(time.Now().Format("15:04:05") + " " + message)
+	logger.write("receive", message)
+}
+
+// CloseLog closes the debug log.
+func CloseLog() {
+	logger.lock.Lock()
+	defer logger.lock.Unlock()
+
+	if logger.file == nil {
+		return
+	}
+
+	logger.file.Close()
+
+	logger.file = nil
+	logger.Logger = nil
+}
+
+// write writes a message to the debug log. The log file is
+// opened when the first message is written.
+func (l *Logger) write(category, message string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.Logger == nil {
+		logPath, err := logFilePath()
+		if err != nil {
+			return
+		}
+
+		file, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return
+		}
+
+		l.file = file
+		l.Logger = log.New(file, "", log.LstdFlags|log.Lmicroseconds)
+	}
+
+	l.Printf("[%s] %s", category, message)
+	l.file.Sync()
+}
+
+// logFilePath returns the path of the log file from the "log-file" option,
+// or the default log file in the configuration directory.
+func logFilePath() (string, error) {
+	if logPath := GetProperty("log-file"); logPath != "" {
+		return logPath, nil
+	}
+
+	return ConfigPath("bluetuith.log")
+}
+
+// logObject returns the adapter and device addresses of an adapter or a device path.
+func logObject(b *bluez.Bluez, path string) string {
+	if device := b.GetDevice(path); device.Address != "" {
+		return fmt.Sprintf(
+			"%s (%s) %s",
+			bluez.GetAdapterID(device.Adapter), b.GetAdapter(device.Adapter).Address, device.Address,
+		)
+	}
+
+	if adapter := b.GetAdapter(path); adapter.Address != "" {
+		return fmt.Sprintf("%s (%s)", bluez.GetAdapterID(path), adapter.Address)
+	}
+
+	return path
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+)
+
+func cmdOptionMaintainBDAddr(b *bluez.Bluez) {
+	optionMaintainBDAddr := strings.ToUpper(GetProperty("maintain-bdaddr"))
+	if optionMaintainBDAddr == "" {
+		return
+	}
+
+	if mac, err := net.ParseMAC(optionMaintainBDAddr); err != nil || len(mac) != 6 {
+		PrintError(
+			fmt.Sprintf(
+				"Provided device address '%s' is incorrect.",
+				optionMaintainBDAddr,
+			),
+		)
+	}
+
+	optionRetries := GetProperty("reconnect-retries")
+	retries, err := strconv.Atoi(optionRetries)
+	if err != nil || retries < 0 {
+		PrintError(
+			fmt.Sprintf(
+				"Provided reconnect retries '%s' is incorrect.\nThe value must be a number greater than or equal to 0.",
+				optionRetries,
+			),
+		)
+	}
+
+	optionDelay := GetProperty("reconnect-delay")
+	if err := validateDuration(optionDelay); err != nil {
+		PrintError(
+			fmt.Sprintf(
+				"Provided reconnect delay '%s' is incorrect: %s",
+				optionDelay, err.Error(),
+			),
+		)
+	}
+	delay, _ := time.ParseDuration(optionDelay)
+
+	adapter := b.GetCurrentAdapter()
+	if adapter.Path == "" {
+		PrintError("No adapters found.")
+	}
+
+	var device bluez.Device
+	for _, d := range b.GetDevices() {
+		if d.Address == optionMaintainBDAddr {
+			device = d
+			break
+		}
+	}
+	if device.Path == "" {
+		PrintError(
+			fmt.Sprintf(
+				"No device with address '%s' found on adapter '%s' (%s)",
+				optionMaintainBDAddr,
+				adapter.Name,
+				bluez.GetAdapterID(adapter.Path),
+			),
+		)
+	}
+	if device.Blocked {
+		PrintError(
+			fmt.Sprintf(
+				"Device '%s' is blocked, and must be unblocked before connecting to it.",
+				device.Address,
+			),
+		)
+	}
+	if GetDeviceConnectDirection(device.Address) == "incoming" {
+		PrintError(
+			fmt.Sprintf(
+				"Device '%s' only accepts incoming connections, and must initiate the connection itself.",
+				device.Address,
+			),
+		)
+	}
+
+	signals := b.WatchSignal()
+	defer b.Conn().RemoveSignal(signals)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	var failures int
+	var reconnect <-chan time.Time
+
+	powered := b.GetAdapter(adapter.Path).Powered
+	if !device.Connected {
+		reconnect = time.After(0)
+	}
+
+	maintainEvent(fmt.Sprintf("Maintaining the connection to '%s' (%s)", device.Name, device.Address))
+
+	for {
+		select {
+		case <-interrupt:
+			Print(fmt.Sprintf("Stopped maintaining the connection to '%s' (%s)", device.Name, device.Address), 0)
+
+		case <-reconnect:
+			reconnect = nil
+
+			if !powered {
+				maintainEvent(fmt.Sprintf("Adapter %s is not powered, waiting for it to be powered on", bluez.GetAdapterID(adapter.Path)))
+				continue
+			}
+
+			maintainEvent(fmt.Sprintf("Connecting to '%s'", device.Name))
+
+			if err := b.Connect(device.Path); err != nil {
+				failures++
+				AddDeviceConnectFailure(device.Address)
+
+				if retries > 0 && failures > retries {
+					PrintError(fmt.Sprintf("Could not reconnect to '%s' after %d attempts", device.Name, retries), err)
+				}
+
+				maintainEvent(fmt.Sprintf("Could not connect to '%s', retrying in %s: %s", device.Name, delay, err.Error()))
+				reconnect = time.After(delay)
+
+				continue
+			}
+
+			failures = 0
+
+		case dbusSignal, ok := <-signals:
+			if !ok {
+				PrintError("The bluez DBus connection was closed.")
+			}
+
+			switch data := b.ParseSignalData(dbusSignal).(type) {
+			case bluez.Adapter:
+				if data.Path != adapter.Path || data.Powered == powered {
+					continue
+				}
+
+				powered = data.Powered
+				if powered {
+					maintainEvent(fmt.Sprintf("Adapter %s was powered on", bluez.GetAdapterID(adapter.Path)))
+					reconnect = time.After(0)
+				} else {
+					maintainEvent(fmt.Sprintf("Adapter %s was powered off", bluez.GetAdapterID(adapter.Path)))
+				}
+
+			case bluez.Device:
+				if data.Path != device.Path || data.Connected == device.Connected {
+					continue
+				}
+
+				device = data
+				UpdateDeviceConnection(device.Address, device.Connected)
+
+				if device.Connected {
+					maintainEvent(fmt.Sprintf("Connected to '%s'", device.Name))
+					reconnect = nil
+
+					continue
+				}
+
+				maintainEvent(fmt.Sprintf("'%s' disconnected, reconnecting in %s", device.Name, delay))
+				reconnect = time.After(delay)
+			}
+		}
+	}
+}
+
+// maintainEvent prints and logs an event of the 'maintain-bdaddr' option.
+func maintainEvent(message string) {
+	Print(time.Now().Format("15:04:05") + " " + message)
+	logger.write("maintain", message)
+}
@@ -1,11 +1,20 @@
 package cmd
 
 import (
+	"bufio"
 	"os"
+	"strings"
+	"sync"
 
 	"github.com/fatih/color"
 )
 
+// exitErrors holds the errors which are printed after the application is closed.
+var exitErrors struct {
+	messages []string
+	lock     sync.Mutex
+}
+
 // Print displays a message.
 func Print(message string, status ...int) {
 	color.New(color.FgWhite, color.Bold).Println(message)
@@ -15,6 +24,48 @@ func Print(message string, status ...int) {
 	}
 }
 
+// PromptConfirm asks a yes/no question, and returns the reply. If the
+// standard input is not a terminal, the default reply is returned.
+func PromptConfirm(question string, defaultReply bool) bool {
+	if stat, err := os.Stdin.Stat(); err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return defaultReply
+	}
+
+	choices := " [y/N]: "
+	if defaultReply {
+		choices = " [Y/n]: "
+	}
+
+	color.New(color.FgWhite, color.Bold).Print(question + choices)
+
+	reply, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return defaultReply
+	}
+
+	switch strings.ToLower(strings.TrimSpace(reply)) {
+	case "y", "yes":
+		return true
+
+	case "n", "no":
+		return false
+	}
+
+	return defaultReply
+}
+
+// IsInteractive returns if the application was launched from a terminal
+// to show the interface, in which case the user can be prompted.
+func IsInteractive() bool {
+	if isActionOptionSet() {
+		return false
+	}
+
+	stat, err := os.Stdin.Stat()
+
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
 // PrintWarn prints a warning to the screen.
 func PrintWarn(message string) {
 	message = "[-] " + message
@@ -22,10 +73,46 @@ func PrintWarn(message string) {
 	color.New(color.FgYellow, color.Bold).Println(message)
 }
 
-// PrintError prints an error to the screen.
+// PrintError prints an error to the screen. If an error is
+// provided, it is appended to the message.
 func PrintError(message string, err ...error) {
 	message = "[!] " + message
+	if err != nil && err[0] != nil {
+		message += ": " + err[0].Error()
+	}
 
 	color.New(color.FgRed, color.Bold).Println(message)
 	os.Exit(1)
 }
+
+// AddExitError records an error which is printed after the application
+// is closed, and causes the application to exit with a non-zero status.
+func AddExitError(message string) {
+	exitErrors.lock.Lock()
+	defer exitErrors.lock.Unlock()
+
+	exitErrors.messages = append(exitErrors.messages, message)
+}
+
+// GetExitErrors returns the errors recorded with AddExitError.
+func GetExitErrors() []string {
+	exitErrors.lock.Lock()
+	defer exitErrors.lock.Unlock()
+
+	return append([]string{}, exitErrors.messages...)
+}
+
+// PrintExitErrors prints the errors recorded with AddExitError, and
+// exits with a non-zero status if any error was recorded.
+func PrintExitErrors() {
+	messages := GetExitErrors()
+	if len(messages) == 0 {
+		return
+	}
+
+	for _, message := range messages[:len(messages)-1] {
+		PrintWarn(message)
+	}
+
+	PrintError(messages[len(messages)-1])
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/godbus/dbus/v5"
+)
+
+func cmdOptionSendFile(b *bluez.Bluez) {
+	files := config.Strings("send-file")
+	optionSendTo := strings.ToUpper(GetProperty("send-to"))
+
+	switch {
+	case len(files) == 0 && optionSendTo == "":
+		return
+
+	case len(files) == 0:
+		PrintError("Specify the files to send with 'send-file'.")
+
+	case optionSendTo == "":
+		PrintError("Specify the device to send files to with 'send-to'.")
+	}
+
+	for _, file := range files {
+		stat, err := os.Stat(file)
+		if err != nil {
+			PrintError(fmt.Sprintf("Cannot send file '%s'", file), err)
+		}
+		if !stat.Mode().IsRegular() {
+			PrintError(fmt.Sprintf("Cannot send file '%s': It is not a regular file.", file))
+		}
+	}
+
+	var device bluez.Device
+	for _, d := range b.GetAllDevices() {
+		if d.Address == optionSendTo {
+			device = d
+			break
+		}
+	}
+	if device.Path == "" {
+		PrintError(fmt.Sprintf("No device with address '%s' was found.", optionSendTo))
+	}
+	if !device.Paired {
+		PrintError(fmt.Sprintf("Device '%s' (%s) is not paired. Pair with the device before sending files to it.", device.Name, device.Address))
+	}
+
+	obex, err := bluez.NewObex()
+	if err != nil {
+		PrintError("Could not initialize bluez OBEX DBus connection", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	sessionPath, err := obex.CreateSession(ctx, device.Address)
+	if err != nil {
+		PrintError(fmt.Sprintf("Could not create an OBEX session with '%s'", device.Name), err)
+	}
+
+	signals := obex.WatchSignal()
+
+	for _, file := range files {
+		err := sendFile(ctx, obex, sessionPath, signals, file)
+		if err == nil {
+			continue
+		}
+
+		obex.Conn().RemoveSignal(signals)
+		obex.RemoveSession(sessionPath)
+
+		PrintError(fmt.Sprintf("Could not send '%s' to '%s'", filepath.Base(file), device.Name), err)
+	}
+
+	obex.Conn().RemoveSignal(signals)
+	obex.RemoveSession(sessionPath)
+
+	Print(fmt.Sprintf("Sent %d file(s) to '%s' (%s)", len(files), device.Name, device.Address), 0)
+}
+
+// sendFile sends a file within the OBEX session, and prints the progress
+// of the transfer to the standard error until the transfer is finished.
+func sendFile(ctx context.Context, obex *bluez.Obex, sessionPath dbus.ObjectPath, signals chan *dbus.Signal, file string) error {
+	transferPath, props, err := obex.SendFile(sessionPath, file)
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(file)
+
+	for {
+		select {
+		case <-ctx.Done():
+			obex.CancelTransfer(transferPath)
+			fmt.Fprintln(os.Stderr)
+
+			return ctx.Err()
+
+		case dbusSignal, ok := <-signals:
+			if !ok {
+				return fmt.Errorf("The bluez OBEX DBus connection was closed")
+			}
+
+			if dbusSignal.Path != transferPath {
+				continue
+			}
+
+			properties, ok := obex.ParseSignalData(dbusSignal).(bluez.ObexProperties)
+			if !ok {
+				continue
+			}
+
+			transfer := properties.TransferProperties
+			if props.Size > 0 {
+				fmt.Fprintf(
+					os.Stderr, "\r%s: %d%% (%d/%d bytes)",
+					name, transfer.Transferred*100/props.Size, transfer.Transferred, props.Size,
+				)
+			}
+
+			switch transfer.Status {
+			case "complete":
+				fmt.Fprintf(os.Stderr, "\r%s: 100%% (%d/%d bytes)\n", name, props.Size, props.Size)
+				return nil
+
+			case "error":
+				fmt.Fprintln(os.Stderr)
+				return fmt.Errorf("The transfer has failed")
+			}
+		}
+	}
+}
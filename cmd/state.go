@@ -0,0 +1,505 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// State describes the persistent application state, which is
+// stored in the configuration directory.
+type State struct {
+	DeviceStats  map[string]*DeviceStats `json:"device-stats,omitempty"`
+	StateChanges map[string]StateChange  `json:"state-changes,omitempty"`
+	Airplane     *AirplaneState          `json:"airplane,omitempty"`
+
+	LastAudioDevice  string              `json:"last-audio-device,omitempty"`
+	ReconnectDevices []string            `json:"reconnect-devices,omitempty"`
+	DeviceTags       map[string][]string `json:"device-tags,omitempty"`
+
+	loaded bool
+	lock   sync.Mutex
+}
+
+// DeviceStats describes the connection statistics of a device.
+type DeviceStats struct {
+	Connects      int           `json:"connects"`
+	Failures      int           `json:"failures"`
+	ConnectedTime time.Duration `json:"connected-time"`
+	LastConnected time.Time     `json:"last-connected"`
+
+	connectedAt time.Time
+}
+
+// StateChange describes the last connection state change of a device.
+type StateChange struct {
+	Connected bool      `json:"connected"`
+	Time      time.Time `json:"time"`
+}
+
+// sessionStates holds the connection states of the devices, the
+// state changes which occurred during the current session, the
+// times at which the devices were manually disconnected, and the
+// failed automatic connection attempts.
+type sessionStates struct {
+	known        map[string]bool
+	changes      map[string]StateChange
+	disconnects  map[string]time.Time
+	autoConnects map[string]autoConnectBackoff
+
+	lock sync.Mutex
+}
+
+// autoConnectBackoff describes the failed automatic connection attempts
+// to a device, and the time after which the connection can be retried.
+type autoConnectBackoff struct {
+	failures int
+	retryAt  time.Time
+}
+
+const (
+	autoConnectBaseDelay = 30 * time.Second
+	autoConnectMaxDelay  = 30 * time.Minute
+)
+
+var (
+	state    State
+	sessions sessionStates
+)
+
+// RecordStateChange records the time of a change in the connection state of a device.
+// The first state recorded for a device is only used to detect subsequent changes.
+// If the "persist-state-changes" option is enabled, the change is saved.
+func RecordStateChange(address string, connected bool) {
+	address = strings.ToUpper(address)
+
+	sessions.lock.Lock()
+	if sessions.known == nil {
+		sessions.known = make(map[string]bool)
+		sessions.changes = make(map[string]StateChange)
+	}
+
+	previous, ok := sessions.known[address]
+	sessions.known[address] = connected
+	if !ok || previous == connected {
+		sessions.lock.Unlock()
+		return
+	}
+
+	change := StateChange{Connected: connected, Time: time.Now()}
+	sessions.changes[address] = change
+	sessions.lock.Unlock()
+
+	if !IsPropertyEnabled("persist-state-changes") {
+		return
+	}
+
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	state.load()
+
+	state.StateChanges[address] = change
+	state.save()
+}
+
+// GetStateChange returns the last connection state change of a device. If the device
+// has not changed its state during the current session, and the "persist-state-changes"
+// option is enabled, the state change from a previous session is returned.
+func GetStateChange(address string) (StateChange, bool) {
+	address = strings.ToUpper(address)
+
+	sessions.lock.Lock()
+	change, ok := sessions.changes[address]
+	sessions.lock.Unlock()
+
+	if ok || !IsPropertyEnabled("persist-state-changes") {
+		return change, ok
+	}
+
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	state.load()
+
+	change, ok = state.StateChanges[address]
+
+	return change, ok
+}
+
+// SetManualDisconnect records that the device was manually disconnected, or clears
+// the record if the device was manually connected, so that automatic connections
+// to the device can be suppressed according to the "manual-disconnect-cooldown" option.
+func SetManualDisconnect(address string, disconnected bool) {
+	address = strings.ToUpper(address)
+
+	sessions.lock.Lock()
+	defer sessions.lock.Unlock()
+
+	if !disconnected {
+		delete(sessions.disconnects, address)
+		return
+	}
+
+	if sessions.disconnects == nil {
+		sessions.disconnects = make(map[string]time.Time)
+	}
+
+	sessions.disconnects[address] = time.Now()
+}
+
+// IsAutoConnectSuppressed returns if automatic connections to the device must be
+// suppressed, since the device was manually disconnected within the cooldown duration.
+func IsAutoConnectSuppressed(address string) bool {
+	optionCooldown := GetProperty("manual-disconnect-cooldown")
+
+	sessions.lock.Lock()
+	disconnectedAt, ok := sessions.disconnects[strings.ToUpper(address)]
+	sessions.lock.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	if optionCooldown == "session" {
+		return true
+	}
+
+	cooldown, err := time.ParseDuration(optionCooldown)
+	if err != nil {
+		return false
+	}
+
+	return time.Since(disconnectedAt) < cooldown
+}
+
+// IsAutoConnectDevice returns if the device is listed in the "auto-connect" option.
+func IsAutoConnectDevice(address string) bool {
+	for _, autoConnect := range strings.Split(GetProperty("auto-connect"), ",") {
+		if strings.EqualFold(strings.TrimSpace(autoConnect), address) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanAutoConnect returns if an automatic connection to the device can be attempted,
+// that is, if the backoff after its previous failed attempts has elapsed.
+func CanAutoConnect(address string) bool {
+	sessions.lock.Lock()
+	defer sessions.lock.Unlock()
+
+	backoff, ok := sessions.autoConnects[strings.ToUpper(address)]
+
+	return !ok || !time.Now().Before(backoff.retryAt)
+}
+
+// SetAutoConnectResult records the result of an automatic connection attempt to a device.
+// If the attempt failed, the delay until the connection can be retried is returned,
+// which doubles with each consecutive failure.
+func SetAutoConnectResult(address string, failed bool) time.Duration {
+	address = strings.ToUpper(address)
+
+	sessions.lock.Lock()
+	defer sessions.lock.Unlock()
+
+	if !failed {
+		delete(sessions.autoConnects, address)
+		return 0
+	}
+
+	if sessions.autoConnects == nil {
+		sessions.autoConnects = make(map[string]autoConnectBackoff)
+	}
+
+	backoff := sessions.autoConnects[address]
+	backoff.failures++
+
+	delay := autoConnectDelay(backoff.failures)
+	backoff.retryAt = time.Now().Add(delay)
+
+	sessions.autoConnects[address] = backoff
+
+	return delay
+}
+
+// autoConnectDelay returns the delay before an automatic connection
+// is retried, after the provided number of consecutive failures.
+func autoConnectDelay(failures int) time.Duration {
+	delay := autoConnectBaseDelay
+	for i := 1; i < failures && delay < autoConnectMaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > autoConnectMaxDelay {
+		delay = autoConnectMaxDelay
+	}
+
+	return delay
+}
+
+// TrackDeviceConnection starts tracking the connected time of an
+// already connected device, without counting it as a new connection.
+func TrackDeviceConnection(address string) {
+	updateDeviceStats(address, func(stats *DeviceStats) bool {
+		if !stats.connectedAt.IsZero() {
+			return false
+		}
+
+		stats.connectedAt = time.Now()
+
+		return false
+	})
+}
+
+// UpdateDeviceConnection updates the connection statistics of a device
+// if its connection state has changed.
+func UpdateDeviceConnection(address string, connected bool) {
+	updateDeviceStats(address, func(stats *DeviceStats) bool {
+		switch {
+		case connected && stats.connectedAt.IsZero():
+			stats.Connects++
+			stats.connectedAt = time.Now()
+			stats.LastConnected = stats.connectedAt
+
+		case !connected && !stats.connectedAt.IsZero():
+			stats.ConnectedTime += time.Since(stats.connectedAt).Truncate(time.Second)
+			stats.connectedAt = time.Time{}
+
+		default:
+			return false
+		}
+
+		return true
+	})
+}
+
+// AddDeviceConnectFailure records a failed connection attempt to a device.
+func AddDeviceConnectFailure(address string) {
+	updateDeviceStats(address, func(stats *DeviceStats) bool {
+		stats.Failures++
+
+		return true
+	})
+}
+
+// GetDeviceStats returns the connection statistics of a device.
+// The connected time includes the duration of the current connection.
+func GetDeviceStats(address string) (DeviceStats, bool) {
+	if !IsPropertyEnabled("device-stats") {
+		return DeviceStats{}, false
+	}
+
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	state.load()
+
+	stats, ok := state.DeviceStats[strings.ToUpper(address)]
+	if !ok {
+		return DeviceStats{}, false
+	}
+
+	deviceStats := *stats
+	if !deviceStats.connectedAt.IsZero() {
+		deviceStats.ConnectedTime += time.Since(deviceStats.connectedAt).Truncate(time.Second)
+	}
+
+	return deviceStats, true
+}
+
+// SetLastAudioDevice saves the address of the most recently manually
+// connected audio device, if the "prefer-last-audio" option is enabled.
+func SetLastAudioDevice(address string) {
+	if !IsPropertyEnabled("prefer-last-audio") {
+		return
+	}
+
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	state.load()
+
+	address = strings.ToUpper(address)
+	if state.LastAudioDevice == address {
+		return
+	}
+
+	state.LastAudioDevice = address
+	state.save()
+}
+
+// GetLastAudioDevice returns the address of the most recently manually connected audio device.
+func GetLastAudioDevice() string {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	state.load()
+
+	return state.LastAudioDevice
+}
+
+// SetReconnectDevices saves the addresses of the devices which were connected
+// when the application was closed, if the "auto-reconnect" option is enabled.
+func SetReconnectDevices(addresses []string) {
+	if !IsPropertyEnabled("auto-reconnect") {
+		return
+	}
+
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	state.load()
+
+	state.ReconnectDevices = make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		state.ReconnectDevices = append(state.ReconnectDevices, strings.ToUpper(address))
+	}
+
+	state.save()
+}
+
+// GetReconnectDevices returns the addresses of the devices which were
+// connected when the application was last closed.
+func GetReconnectDevices() []string {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	state.load()
+
+	return append([]string{}, state.ReconnectDevices...)
+}
+
+// SetDeviceTags validates and saves the tags of a device to the state file.
+// The tags replace the tags of the device from the configuration, and
+// an empty list removes all tags from the device.
+func SetDeviceTags(address string, tags []string) error {
+	for _, tag := range tags {
+		if err := ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	state.load()
+
+	if state.DeviceTags == nil {
+		state.DeviceTags = make(map[string][]string)
+	}
+	state.DeviceTags[strings.ToUpper(address)] = append([]string{}, tags...)
+
+	state.save()
+
+	return nil
+}
+
+// getStateDeviceTags returns the tags of a device from the state file,
+// and whether the tags were set.
+func getStateDeviceTags(address string) ([]string, bool) {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	state.load()
+
+	tags, ok := state.DeviceTags[strings.ToUpper(address)]
+	if len(tags) == 0 {
+		return nil, ok
+	}
+
+	return append([]string{}, tags...), ok
+}
+
+// SaveState adds the durations of the current connections to the
+// connection statistics, and saves the application state.
+func SaveState() {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	if !state.loaded {
+		return
+	}
+
+	for _, stats := range state.DeviceStats {
+		if stats.connectedAt.IsZero() {
+			continue
+		}
+
+		stats.ConnectedTime += time.Since(stats.connectedAt).Truncate(time.Second)
+		stats.connectedAt = time.Time{}
+	}
+
+	state.save()
+}
+
+// updateDeviceStats calls the update function with the connection statistics
+// of a device, and saves the state if the function reports a change.
+func updateDeviceStats(address string, update func(stats *DeviceStats) bool) {
+	if !IsPropertyEnabled("device-stats") {
+		return
+	}
+
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	state.load()
+
+	address = strings.ToUpper(address)
+
+	stats, ok := state.DeviceStats[address]
+	if !ok {
+		stats = &DeviceStats{}
+		state.DeviceStats[address] = stats
+	}
+
+	if update(stats) {
+		state.save()
+	}
+}
+
+// load loads the application state from the state file.
+func (s *State) load() {
+	if s.loaded {
+		return
+	}
+
+	s.loaded = true
+	s.DeviceStats = make(map[string]*DeviceStats)
+	s.StateChanges = make(map[string]StateChange)
+
+	statePath, err := ConfigPath("state.json")
+	if err != nil {
+		return
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil || len(data) == 0 {
+		return
+	}
+
+	if err := json.Unmarshal(data, s); err != nil || s.DeviceStats == nil {
+		s.DeviceStats = make(map[string]*DeviceStats)
+	}
+	if s.StateChanges == nil {
+		s.StateChanges = make(map[string]StateChange)
+	}
+}
+
+// save saves the application state to the state file.
+func (s *State) save() {
+	statePath, err := ConfigPath("state.json")
+	if err != nil {
+		return
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return
+	}
+
+	os.WriteFile(statePath, data, 0600)
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+)
+
+func TestDeviceStats(t *testing.T) {
+	config.path = t.TempDir()
+	config.Koanf = koanf.New(".")
+	AddProperty("device-stats", true)
+
+	state = State{}
+
+	const address = "aa:bb:cc:dd:ee:ff"
+
+	UpdateDeviceConnection(address, true)
+	UpdateDeviceConnection(address, true)
+	UpdateDeviceConnection(address, false)
+	AddDeviceConnectFailure(address)
+	UpdateDeviceConnection(address, true)
+
+	state = State{}
+
+	stats, ok := GetDeviceStats(address)
+	if !ok {
+		t.Fatal("GetDeviceStats() did not return the saved statistics")
+	}
+
+	if stats.Connects != 2 || stats.Failures != 1 || stats.LastConnected.IsZero() {
+		t.Errorf("GetDeviceStats() = %+v, want 2 connects and 1 failure", stats)
+	}
+
+	AddProperty("device-stats", false)
+	if _, ok := GetDeviceStats(address); ok {
+		t.Error("GetDeviceStats() returned statistics with device-stats disabled")
+	}
+}
+
+func TestStateChanges(t *testing.T) {
+	config.path = t.TempDir()
+	config.Koanf = koanf.New(".")
+	AddProperty("persist-state-changes", true)
+
+	state = State{}
+	sessions = sessionStates{}
+
+	const address = "aa:bb:cc:dd:ee:ff"
+
+	RecordStateChange(address, false)
+	if _, ok := GetStateChange(address); ok {
+		t.Fatal("GetStateChange() returned a change for the initial state")
+	}
+
+	RecordStateChange(address, false)
+	RecordStateChange(address, true)
+
+	change, ok := GetStateChange(address)
+	if !ok || !change.Connected || change.Time.IsZero() {
+		t.Fatalf("GetStateChange() = %+v, %v, want a connected state change", change, ok)
+	}
+
+	state = State{}
+	sessions = sessionStates{}
+
+	if persisted, ok := GetStateChange(address); !ok || !persisted.Time.Equal(change.Time) {
+		t.Errorf("GetStateChange() = %+v, %v, want the persisted state change", persisted, ok)
+	}
+}
+
+func TestManualDisconnectCooldown(t *testing.T) {
+	config.Koanf = koanf.New(".")
+	sessions = sessionStates{}
+
+	const address = "aa:bb:cc:dd:ee:ff"
+
+	AddProperty("manual-disconnect-cooldown", "0")
+	SetManualDisconnect(address, true)
+	if IsAutoConnectSuppressed(address) {
+		t.Error("IsAutoConnectSuppressed() = true with the cooldown disabled")
+	}
+
+	AddProperty("manual-disconnect-cooldown", "1h")
+	if !IsAutoConnectSuppressed(address) {
+		t.Error("IsAutoConnectSuppressed() = false within the cooldown")
+	}
+
+	AddProperty("manual-disconnect-cooldown", "session")
+	if !IsAutoConnectSuppressed("AA:BB:CC:DD:EE:FF") {
+		t.Error("IsAutoConnectSuppressed() = false within the session")
+	}
+
+	SetManualDisconnect(address, false)
+	if IsAutoConnectSuppressed(address) {
+		t.Error("IsAutoConnectSuppressed() = true after a manual connection")
+	}
+}
+
+func TestAutoConnect(t *testing.T) {
+	config.Koanf = koanf.New(".")
+	AddProperty("auto-connect", "AA:BB:CC:DD:EE:FF, 11:22:33:44:55:66")
+
+	if !IsAutoConnectDevice("aa:bb:cc:dd:ee:ff") || !IsAutoConnectDevice("11:22:33:44:55:66") {
+		t.Error("IsAutoConnectDevice() = false for a listed device")
+	}
+	if IsAutoConnectDevice("AA:BB:CC:DD:EE:00") {
+		t.Error("IsAutoConnectDevice() = true for an unlisted device")
+	}
+
+	sessions = sessionStates{}
+
+	const address = "aa:bb:cc:dd:ee:ff"
+
+	if delay := SetAutoConnectResult(address, true); delay != autoConnectBaseDelay {
+		t.Errorf("SetAutoConnectResult() = %s after one failure, want %s", delay, autoConnectBaseDelay)
+	}
+	if delay := SetAutoConnectResult(address, true); delay != 2*autoConnectBaseDelay {
+		t.Errorf("SetAutoConnectResult() = %s after two failures, want %s", delay, 2*autoConnectBaseDelay)
+	}
+	if CanAutoConnect(address) {
+		t.Error("CanAutoConnect() = true during the backoff")
+	}
+
+	SetAutoConnectResult(address, false)
+	if !CanAutoConnect(address) {
+		t.Error("CanAutoConnect() = false after a successful connection")
+	}
+
+	if delay := autoConnectDelay(100); delay != autoConnectMaxDelay {
+		t.Errorf("autoConnectDelay(100) = %s, want %s", delay, autoConnectMaxDelay)
+	}
+	if delay := autoConnectDelay(3); delay != 2*time.Minute {
+		t.Errorf("autoConnectDelay(3) = %s, want 2m0s", delay)
+	}
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/fatih/color"
+)
+
+// Wizard describes the first-run setup wizard.
+type Wizard struct {
+	reader *bufio.Reader
+}
+
+// firstRun stores whether the configuration file did not exist
+// when the application was started.
+var firstRun bool
+
+// cmdOptionWizard runs the setup wizard if the application is run for the
+// first time to show the interface, and the "no-wizard" option is not set.
+func cmdOptionWizard(b *bluez.Bluez) {
+	if !firstRun || IsPropertyEnabled("no-wizard") || isActionOptionSet() {
+		return
+	}
+
+	if stat, err := os.Stdin.Stat(); err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return
+	}
+
+	wizard := Wizard{
+		reader: bufio.NewReader(os.Stdin),
+	}
+
+	Print("Welcome to bluetuith! This wizard will help you set up the configuration.")
+	Print("Press Enter to accept the default values. This wizard can be skipped with '--no-wizard'.\n")
+
+	wizard.adapter(b)
+	wizard.receiveDir()
+	wizard.theme()
+	wizard.autoConnect(b)
+
+	generate()
+
+	Print("\nThe configuration has been written to " + filepath.Join(config.path, "bluetuith.conf") + "\n")
+}
+
+// adapter asks for the adapter to use.
+func (w *Wizard) adapter(b *bluez.Bluez) {
+	adapters := b.GetAdapters()
+	if len(adapters) == 0 {
+		return
+	}
+
+	var choices []string
+	for _, adapter := range adapters {
+		choices = append(choices, fmt.Sprintf("%s (%s)", filepath.Base(adapter.Path), adapter.Name))
+	}
+
+	choice := w.choose("Select the adapter to use:", choices)
+	if choice < 0 {
+		return
+	}
+
+	AddProperty("adapter", filepath.Base(adapters[choice].Path))
+	b.SetCurrentAdapter(adapters[choice])
+}
+
+// receiveDir asks for the directory to store received files.
+func (w *Wizard) receiveDir() {
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return
+	}
+
+	defaultDir := filepath.Join(homedir, "bluetuith")
+
+	for {
+		dir := w.prompt("Directory to store received files", defaultDir)
+		if strings.HasPrefix(dir, "~/") {
+			dir = filepath.Join(homedir, dir[2:])
+		}
+
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			PrintWarn(dir + ": Directory is not accessible.")
+			continue
+		}
+
+		AddProperty("receive-dir", dir)
+
+		return
+	}
+}
+
+// theme asks for the theme to use.
+func (w *Wizard) theme() {
+	choice := w.choose("Select the theme to use:", []string{
+		"Default",
+		"Custom (HJSON, a path or a http(s) URL to a theme file)",
+	})
+	if choice != 1 {
+		return
+	}
+
+	if theme := w.prompt("Theme", ""); theme != "" {
+		AddProperty("theme", theme)
+	}
+}
+
+// autoConnect asks for the device to connect to on startup.
+func (w *Wizard) autoConnect(b *bluez.Bluez) {
+	var devices []bluez.Device
+	var choices []string
+
+	for _, device := range b.GetDevices() {
+		if !device.Paired {
+			continue
+		}
+
+		devices = append(devices, device)
+		choices = append(choices, fmt.Sprintf("%s (%s)", device.Name, device.Address))
+	}
+	if devices == nil {
+		return
+	}
+
+	choices = append([]string{"None"}, choices...)
+
+	choice := w.choose("Select a device to connect to on startup:", choices)
+	if choice <= 0 {
+		return
+	}
+
+	AddProperty("connect-bdaddr", devices[choice-1].Address)
+}
+
+// choose displays a list of choices and returns the index of the chosen item.
+// The first item is chosen by default.
+func (w *Wizard) choose(question string, choices []string) int {
+	Print(question)
+	for i, choice := range choices {
+		fmt.Printf("  %d) %s\n", i+1, choice)
+	}
+
+	for {
+		reply := w.prompt("Choice", "1")
+
+		choice, err := strconv.Atoi(reply)
+		if err != nil || choice < 1 || choice > len(choices) {
+			PrintWarn("Invalid choice, enter a number between 1 and " + strconv.Itoa(len(choices)))
+			continue
+		}
+
+		return choice - 1
+	}
+}
+
+// prompt asks a question and returns the reply, or the default value
+// if no reply was entered.
+func (w *Wizard) prompt(question, defaultValue string) string {
+	if defaultValue != "" {
+		question += " [" + defaultValue + "]"
+	}
+
+	color.New(color.FgWhite, color.Bold).Print(question + ": ")
+
+	reply, err := w.reader.ReadString('\n')
+	if err != nil {
+		PrintError("Setup wizard was interrupted")
+	}
+
+	reply = strings.TrimSpace(reply)
+	if reply == "" {
+		return defaultValue
+	}
+
+	return reply
+}
+
+// isActionOptionSet returns if any option which performs a one-time action is set.
+func isActionOptionSet() bool {
+	for _, option := range options {
+		if !option.IsAction {
+			continue
+		}
+
+		switch {
+		case option.IsBoolean:
+			if IsPropertyEnabled(option.Name) {
+				return true
+			}
+
+		case option.IsList:
+			if len(config.Strings(option.Name)) > 0 {
+				return true
+			}
+
+		default:
+			if GetProperty(option.Name) != "" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"strings"
+
 	"github.com/darkhz/bluetuith/agent"
 	"github.com/darkhz/bluetuith/bluez"
 	"github.com/darkhz/bluetuith/cmd"
@@ -13,7 +15,7 @@ func main() {
 
 	cmd.Parse()
 
-	bluezConn, err := bluez.NewBluez()
+	bluezConn, err := bluez.NewBluez(cmd.GetProperty("dbus-system-address"))
 	if err != nil {
 		cmd.PrintError("Could not initialize bluez DBus connection", err)
 	}
@@ -22,6 +24,14 @@ func main() {
 		cmd.PrintError("Could not setup bluez agent", err)
 	}
 
+	if agents := agent.ConflictingAgents(); agents != nil {
+		warn += "Other bluetooth agents (" + strings.Join(agents, ", ") + ") are running, which may interfere with pairing.\n\n"
+	}
+
+	if err := agent.DefaultAgentError(); err != nil {
+		warn += "Could not take over as the default bluetooth agent (" + err.Error() + "), another agent may handle the pairing requests.\n\n"
+	}
+
 	cmd.Init(bluezConn)
 
 	networkConn, err := network.NewNetwork()
@@ -41,9 +51,19 @@ func main() {
 	cmd.AddProperty("obex", err == nil)
 
 	ui.SetConnections(bluezConn, obexConn, networkConn, warn)
-	ui.StartUI()
-	ui.StopMediaPlayer()
+	if cmd.IsPropertyEnabled("receive") {
+		agent.Receive()
+	} else {
+		ui.StartUI()
+		ui.StopMediaPlayer()
+		ui.SaveConnectedDevices()
+	}
 
 	agent.RemoveObexAgent()
 	agent.RemoveAgent()
+
+	cmd.SaveState()
+	cmd.CloseLog()
+
+	cmd.PrintExitErrors()
 }
@@ -7,4 +7,11 @@ var (
 	NMConnectionError         = errors.New("Connection error occurred")
 
 	NMSettingModifyError = errors.New("Cannot modify connection settings")
+
+	NMGsmPinCheckFailed    = errors.New("The SIM PIN is incorrect, check the 'gsm-pin' option before retrying")
+	NMGsmSimPinRequired    = errors.New("The SIM is locked, specify its PIN with the 'gsm-pin' option")
+	NMGsmSimPukRequired    = errors.New("The SIM requires its PUK to be unlocked, unlock it on the device before retrying")
+	NMGsmSimWrong          = errors.New("The SIM is not supported by the modem")
+	NMGsmSimNotInserted    = errors.New("No SIM is inserted in the modem")
+	NMGsmRegistrationError = errors.New("The modem could not register with the network")
 )
@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	nm "github.com/Wifx/gonetworkmanager"
+	"github.com/darkhz/bluetuith/cmd"
 	"github.com/google/uuid"
 )
 
@@ -160,6 +161,10 @@ func (n *Network) CreateConnection(name, connType, bdaddr string) error {
 func (n *Network) ActivateConnection(conn nm.Connection, device nm.Device, bdaddr string) error {
 	var state nm.StateChange
 
+	if cmd.GetProperty("gsm-pin") != "" {
+		defer clearGsmPin(conn)
+	}
+
 	activeConn, err := n.Manager.ActivateConnection(conn, device, nil)
 	if err != nil {
 		return err
@@ -191,6 +196,16 @@ WatchState:
 	}
 
 	if state.State != nm.NmActiveConnectionStateActivated {
+		if err := getGsmError(device); err != nil {
+			// Do not retry with an incorrect PIN, since the SIM
+			// is locked after a few attempts.
+			if err == NMGsmPinCheckFailed {
+				cmd.SetRuntimeProperty("gsm-pin", "")
+			}
+
+			return err
+		}
+
 		return NMConnectionError
 	}
 
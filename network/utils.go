@@ -8,6 +8,7 @@ import (
 
 	nm "github.com/Wifx/gonetworkmanager"
 	"github.com/darkhz/bluetuith/cmd"
+	"github.com/godbus/dbus/v5"
 )
 
 // isDeviceAddrExist checks if the device's address is present
@@ -56,6 +57,8 @@ func checkSettings(conn nm.Connection, connType string) error {
 		gsmSettings["number"] = number
 	}
 
+	setGsmPin(gsmSettings)
+
 	delete(settings, "ipv6")
 
 	return conn.Update(settings)
@@ -88,11 +91,96 @@ func getSettings(bdaddr []byte, name, connType, uuid string) map[string]map[stri
 			"apn":    apn,
 			"number": number,
 		}
+
+		setGsmPin(settings["gsm"])
 	}
 
 	return settings
 }
 
+// setGsmPin sets the SIM PIN in the GSM settings, if it is specified.
+// Since there is no secret agent to provide the PIN when the connection
+// is activated, the PIN is stored with the connection, and is removed
+// by clearGsmPin once the activation is complete.
+func setGsmPin(gsmSettings map[string]interface{}) {
+	pin := cmd.GetProperty("gsm-pin")
+	if pin == "" {
+		delete(gsmSettings, "pin")
+		return
+	}
+
+	gsmSettings["pin"] = pin
+	delete(gsmSettings, "pin-flags")
+}
+
+// clearGsmPin removes the SIM PIN from the stored connection. The settings
+// of the connection do not include its secrets, so updating the connection
+// with its own settings removes the stored PIN.
+func clearGsmPin(conn nm.Connection) error {
+	settings, err := conn.GetSettings()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := settings["gsm"]; !ok {
+		return nil
+	}
+
+	delete(settings["gsm"], "pin")
+	delete(settings, "ipv6")
+
+	return conn.Update(settings)
+}
+
+// getGsmError returns an error which describes the GSM related reason
+// for the device's activation failure, if any.
+func getGsmError(device nm.Device) error {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil
+	}
+
+	variant, err := conn.Object(nm.NetworkManagerInterface, device.GetPath()).GetProperty(nm.DevicePropertyStateReason)
+	if err != nil {
+		return nil
+	}
+
+	stateReason, ok := variant.Value().([]interface{})
+	if !ok || len(stateReason) != 2 {
+		return nil
+	}
+
+	reason, ok := stateReason[1].(uint32)
+	if !ok {
+		return nil
+	}
+
+	switch nm.NmDeviceStateReason(reason) {
+	case nm.NmDeviceStateReasonGsmPinCheckFailed:
+		return NMGsmPinCheckFailed
+
+	case nm.NmDeviceStateReasonGsmSimPinRequired:
+		return NMGsmSimPinRequired
+
+	case nm.NmDeviceStateReasonGsmSimPukRequired:
+		return NMGsmSimPukRequired
+
+	case nm.NmDeviceStateReasonGsmSimWrong:
+		return NMGsmSimWrong
+
+	case nm.NmDeviceStateReasonGsmSimNotInserted:
+		return NMGsmSimNotInserted
+
+	case nm.NmDeviceStateReasonGsmRegistrationDenied,
+		nm.NmDeviceStateReasonGsmRegistrationFailed,
+		nm.NmDeviceStateReasonGsmRegistrationTimeout,
+		nm.NmDeviceStateReasonGsmRegistrationNotSearching:
+		return NMGsmRegistrationError
+	}
+
+	return nil
+}
+
 // getMacAddress gets a MAC address from a bluetooth address byte array.
 func getMacAddress(addr []byte) string {
 	var macAddr []string
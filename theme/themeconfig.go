@@ -3,6 +3,8 @@ package theme
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 )
 
 // ThemeContext describes the type of context to apply the color into.
@@ -21,6 +23,8 @@ const (
 	ThemeAdapterDiscoverable      ThemeContext = "AdapterDiscoverable"
 	ThemeAdapterScanning          ThemeContext = "AdapterScanning"
 	ThemeAdapterPairable          ThemeContext = "AdapterPairable"
+	ThemeAdapterAirplane          ThemeContext = "AdapterAirplane"
+	ThemeAdapterFilter            ThemeContext = "AdapterFilter"
 	ThemeDevice                   ThemeContext = "Device"
 	ThemeDeviceType               ThemeContext = "DeviceType"
 	ThemeDeviceAlias              ThemeContext = "DeviceAlias"
@@ -29,6 +33,9 @@ const (
 	ThemeDeviceProperty           ThemeContext = "DeviceProperty"
 	ThemeDevicePropertyConnected  ThemeContext = "DevicePropertyConnected"
 	ThemeDevicePropertyDiscovered ThemeContext = "DevicePropertyDiscovered"
+	ThemeDevicePropertyError      ThemeContext = "DevicePropertyError"
+	ThemeDevicePropertyAway       ThemeContext = "DevicePropertyAway"
+	ThemeDevicePropertyTrusted    ThemeContext = "DevicePropertyTrusted"
 	ThemeMenu                     ThemeContext = "Menu"
 	ThemeMenuBar                  ThemeContext = "MenuBar"
 	ThemeMenuItem                 ThemeContext = "MenuItem"
@@ -50,6 +57,8 @@ var ThemeConfig = map[ThemeContext]string{
 	ThemeAdapterDiscoverable: "aqua",
 	ThemeAdapterScanning:     "yellow",
 	ThemeAdapterPairable:     "mediumorchid",
+	ThemeAdapterAirplane:     "orange",
+	ThemeAdapterFilter:       "khaki",
 
 	ThemeDevice:                   "white",
 	ThemeDeviceType:               "white",
@@ -59,6 +68,9 @@ var ThemeConfig = map[ThemeContext]string{
 	ThemeDeviceProperty:           "grey",
 	ThemeDevicePropertyConnected:  "green",
 	ThemeDevicePropertyDiscovered: "orange",
+	ThemeDevicePropertyError:      "red",
+	ThemeDevicePropertyAway:       "darkgoldenrod",
+	ThemeDevicePropertyTrusted:    "deepskyblue",
 
 	ThemeMenu:     "white",
 	ThemeMenuBar:  "default",
@@ -68,23 +80,216 @@ var ThemeConfig = map[ThemeContext]string{
 	ThemeProgressText: "white",
 }
 
-// ParseThemeConfig parses the theme configuration.
-func ParseThemeConfig(themeConfig map[string]string) error {
-	for context, color := range themeConfig {
-		if !isValidElementColor(color) {
-			return errors.New(fmt.Sprintf("Theme configuration is incorrect for %s (%s)", context, color))
-		}
+// defaultThemeConfig stores the default colors for the modifier elements,
+// which includes the colors of the selected theme preset.
+var defaultThemeConfig = copyThemeConfig(ThemeConfig)
+
+// ThemeStyle describes the style attributes of a modifier element.
+type ThemeStyle struct {
+	Background string
+	Bold       bool
+	Underline  bool
+	Reverse    bool
+}
+
+// ThemeStyles stores the style attributes for the modifier elements.
+// Elements which are configured with only a color are not present.
+var ThemeStyles = map[ThemeContext]ThemeStyle{}
+
+// themeLock guards ThemeConfig and ThemeStyles, since the theme can
+// be reloaded while the UI is drawn.
+var themeLock sync.RWMutex
+
+// colorsDisabled is set if all colors and styles are disabled.
+var colorsDisabled bool
+
+// DisableColors disables all colors and styles of the modifier elements.
+func DisableColors() {
+	colorsDisabled = true
+}
+
+// ColorsDisabled returns if all colors and styles are disabled.
+func ColorsDisabled() bool {
+	return colorsDisabled
+}
+
+// StyleAttributes lists the attributes that can be specified for a
+// modifier element, in addition to its color.
+var StyleAttributes = []string{
+	"color",
+	"background",
+	"bold",
+	"underline",
+	"reverse",
+}
+
+// ParseThemeConfig parses the theme configuration. Each element can either be
+// specified as a color, or as a set of style attributes.
+func ParseThemeConfig(themeConfig map[string]interface{}) error {
+	themeLock.Lock()
+	defer themeLock.Unlock()
+
+	return parseThemeConfig(themeConfig, ThemeConfig, ThemeStyles)
+}
+
+// ReloadThemeConfig parses the theme configuration on top of the default
+// colors, and replaces the current theme with it. If the theme configuration
+// is invalid, the current theme is kept.
+func ReloadThemeConfig(themeConfig map[string]interface{}) error {
+	colors, styles := copyThemeConfig(defaultThemeConfig), make(map[ThemeContext]ThemeStyle)
 
-		switch color {
-		case "black":
-			color = "#000000"
+	if err := parseThemeConfig(themeConfig, colors, styles); err != nil {
+		return err
+	}
+
+	themeLock.Lock()
+	ThemeConfig, ThemeStyles = colors, styles
+	themeLock.Unlock()
+
+	return nil
+}
+
+// parseThemeConfig parses the theme configuration into the provided colors and styles.
+func parseThemeConfig(
+	themeConfig map[string]interface{},
+	colors map[ThemeContext]string, styles map[ThemeContext]ThemeStyle,
+) error {
+	for context, value := range themeConfig {
+		switch v := value.(type) {
+		case string:
+			color, err := parseElementColor(context, v)
+			if err != nil {
+				return err
+			}
+
+			colors[ThemeContext(context)] = color
+			delete(styles, ThemeContext(context))
+
+		case map[string]interface{}:
+			if err := parseElementStyle(context, v, colors, styles); err != nil {
+				return err
+			}
 
-		case "transparent":
-			color = "default"
+		default:
+			return fmt.Errorf("Theme configuration is incorrect for %s (%v)", context, value)
 		}
+	}
+
+	return nil
+}
+
+// parseElementStyle parses the style attributes of a modifier element.
+func parseElementStyle(
+	context string, attributes map[string]interface{},
+	colors map[ThemeContext]string, styles map[ThemeContext]ThemeStyle,
+) error {
+	var style ThemeStyle
+
+	for name, value := range attributes {
+		switch name {
+		case "color", "background":
+			color, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("Theme configuration is incorrect for %s (%s: %v)", context, name, value)
+			}
+
+			color, err := parseElementColor(context, color)
+			if err != nil {
+				return err
+			}
+
+			if name == "color" {
+				colors[ThemeContext(context)] = color
+			} else {
+				style.Background = color
+			}
 
-		ThemeConfig[ThemeContext(context)] = color
+		case "bold", "underline", "reverse":
+			enabled, ok := value.(bool)
+			if !ok {
+				return fmt.Errorf("Theme configuration is incorrect for %s (%s: %v)", context, name, value)
+			}
+
+			switch name {
+			case "bold":
+				style.Bold = enabled
+
+			case "underline":
+				style.Underline = enabled
+
+			case "reverse":
+				style.Reverse = enabled
+			}
+
+		default:
+			return fmt.Errorf(
+				"Theme configuration is incorrect for %s (%s)\nValid attributes are '%s'",
+				context, name, strings.Join(StyleAttributes, ", "),
+			)
+		}
 	}
 
+	styles[ThemeContext(context)] = style
+
 	return nil
 }
+
+// parseElementColor validates and returns the color of a modifier element.
+// Hex colors are accepted in the '#rrggbb' and '#rgb' formats.
+func parseElementColor(context, color string) (string, error) {
+	if strings.HasPrefix(color, "#") {
+		hex, err := parseHexColor(color)
+		if err != nil {
+			return "", fmt.Errorf("Theme configuration is incorrect for %s (%s): %s", context, color, err.Error())
+		}
+
+		return hex, nil
+	}
+
+	if !isValidElementColor(color) {
+		return "", errors.New(fmt.Sprintf("Theme configuration is incorrect for %s (%s)", context, color))
+	}
+
+	switch color {
+	case "black":
+		color = "#000000"
+
+	case "transparent":
+		color = "default"
+	}
+
+	return color, nil
+}
+
+// parseHexColor validates the hex color, and returns it in the '#rrggbb' format.
+func parseHexColor(color string) (string, error) {
+	hex := strings.ToLower(strings.TrimPrefix(color, "#"))
+
+	for _, c := range hex {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return "", fmt.Errorf("'%c' is not a hex digit", c)
+		}
+	}
+
+	switch len(hex) {
+	case 6:
+
+	case 3:
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+
+	default:
+		return "", errors.New("hex colors must be in the '#rrggbb' or '#rgb' format")
+	}
+
+	return "#" + hex, nil
+}
+
+// copyThemeConfig returns a copy of the provided element colors.
+func copyThemeConfig(colors map[ThemeContext]string) map[ThemeContext]string {
+	copied := make(map[ThemeContext]string, len(colors))
+	for context, color := range colors {
+		copied[context] = color
+	}
+
+	return copied
+}
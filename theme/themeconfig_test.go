@@ -0,0 +1,116 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestParseThemeConfig(t *testing.T) {
+	err := ParseThemeConfig(map[string]interface{}{
+		"Adapter": "red",
+		"Device": map[string]interface{}{
+			"color":      "green",
+			"background": "black",
+			"underline":  true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("ParseThemeConfig() returned error: %v", err)
+	}
+
+	if ThemeConfig[ThemeAdapter] != "red" || ThemeConfig[ThemeDevice] != "green" {
+		t.Errorf("ParseThemeConfig() did not set the element colors")
+	}
+
+	if wrapped := ColorWrap(ThemeDevice, "text"); wrapped != "[green:#000000:u]text[-:-:-]" {
+		t.Errorf("ColorWrap() = %q", wrapped)
+	}
+
+	if wrapped := ColorWrap(ThemeAdapter, "text", "::bu"); wrapped != "[red::bu]text[-:-:-]" {
+		t.Errorf("ColorWrap() = %q", wrapped)
+	}
+
+	for _, invalid := range []map[string]interface{}{
+		{"Device": map[string]interface{}{"italic": true}},
+		{"Device": map[string]interface{}{"bold": "yes"}},
+		{"Device": map[string]interface{}{"color": "notacolor"}},
+		{"Device": 1},
+	} {
+		if err := ParseThemeConfig(invalid); err == nil {
+			t.Errorf("ParseThemeConfig(%v) did not return an error", invalid)
+		}
+	}
+}
+
+func TestReloadThemeConfig(t *testing.T) {
+	if err := ReloadThemeConfig(map[string]interface{}{"Text": "red"}); err != nil {
+		t.Fatalf("ReloadThemeConfig() returned error: %v", err)
+	}
+
+	if ThemeConfig[ThemeText] != "red" || ThemeConfig[ThemeBorder] != defaultThemeConfig[ThemeBorder] {
+		t.Errorf("ReloadThemeConfig() did not apply the theme on top of the defaults")
+	}
+
+	if err := ReloadThemeConfig(map[string]interface{}{"Text": "notacolor"}); err == nil {
+		t.Errorf("ReloadThemeConfig() did not return an error")
+	}
+
+	if ThemeConfig[ThemeText] != "red" {
+		t.Errorf("ReloadThemeConfig() did not keep the previous theme, Text = %q", ThemeConfig[ThemeText])
+	}
+
+	if err := ReloadThemeConfig(nil); err != nil || ThemeConfig[ThemeText] != defaultThemeConfig[ThemeText] {
+		t.Errorf("ReloadThemeConfig() did not reset the theme to the defaults")
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	for color, expected := range map[string]string{
+		"#1a2b3c": "#1a2b3c",
+		"#1A2B3C": "#1a2b3c",
+		"#abc":    "#aabbcc",
+	} {
+		if hex, err := parseHexColor(color); err != nil || hex != expected {
+			t.Errorf("parseHexColor(%q) = %q, %v; want %q", color, hex, err, expected)
+		}
+	}
+
+	for _, color := range []string{"#", "#1a2b3", "#1a2b3c4d", "#1g2b3c", "#+12345"} {
+		if _, err := parseHexColor(color); err == nil {
+			t.Errorf("parseHexColor(%q) did not return an error", color)
+		}
+	}
+
+	err := ParseThemeConfig(map[string]interface{}{"Adapter": "#12345"})
+	if err == nil || !strings.Contains(err.Error(), "Adapter") {
+		t.Errorf("ParseThemeConfig() error does not name the element: %v", err)
+	}
+
+	if err := ParseThemeConfig(map[string]interface{}{"Device": map[string]interface{}{"color": "#ABC"}}); err != nil {
+		t.Fatalf("ParseThemeConfig() returned error: %v", err)
+	}
+
+	if GetColor(ThemeDevice) != tcell.NewHexColor(0xaabbcc) {
+		t.Errorf("GetColor() = %v", GetColor(ThemeDevice))
+	}
+}
+
+func TestParseThemePreset(t *testing.T) {
+	for _, name := range ThemePresetNames() {
+		if err := ParseThemePreset(name); err != nil {
+			t.Errorf("ParseThemePreset(%q) returned error: %v", name, err)
+		}
+	}
+
+	if ThemeConfig[ThemeBackground] != ThemePresets["solarized"]["Background"] ||
+		defaultThemeConfig[ThemeBackground] != ThemePresets["solarized"]["Background"] {
+		t.Errorf("ParseThemePreset() did not apply the preset colors")
+	}
+
+	err := ParseThemePreset("notapreset")
+	if err == nil || !strings.Contains(err.Error(), "dark', 'light', 'nord', 'solarized") {
+		t.Errorf("ParseThemePreset() did not list the available presets: %v", err)
+	}
+}
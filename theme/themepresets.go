@@ -0,0 +1,183 @@
+package theme
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ThemePresets stores the built-in themes, which can be selected
+// and then overridden by the theme configuration.
+var ThemePresets = map[string]map[string]interface{}{
+	"dark": {
+		"Text":        "#d0d0d0",
+		"Border":      "#6c6c6c",
+		"Background":  "#1c1c1c",
+		"StatusInfo":  "#d0d0d0",
+		"StatusError": "#ff5f5f",
+
+		"Adapter":             "#d0d0d0",
+		"AdapterPowered":      "#87d787",
+		"AdapterNotPowered":   "#ff5f5f",
+		"AdapterDiscoverable": "#5fd7d7",
+		"AdapterScanning":     "#ffd75f",
+		"AdapterPairable":     "#af87d7",
+		"AdapterAirplane":     "#ffaf5f",
+		"AdapterFilter":       "#d7d787",
+
+		"Device":                   "#d0d0d0",
+		"DeviceType":               "#d0d0d0",
+		"DeviceAlias":              "#d0d0d0",
+		"DeviceConnected":          "#ffffff",
+		"DeviceDiscovered":         "#d0d0d0",
+		"DeviceProperty":           "#8a8a8a",
+		"DevicePropertyConnected":  "#87d787",
+		"DevicePropertyDiscovered": "#ffaf5f",
+		"DevicePropertyError":      "#ff5f5f",
+		"DevicePropertyAway":       "#d7af5f",
+		"DevicePropertyTrusted":    "#5fafff",
+
+		"Menu":     "#d0d0d0",
+		"MenuBar":  "#262626",
+		"MenuItem": "#d0d0d0",
+
+		"ProgressBar":  "#5fafff",
+		"ProgressText": "#d0d0d0",
+	},
+	"light": {
+		"Text":        "#303030",
+		"Border":      "#8a8a8a",
+		"Background":  "#eeeeee",
+		"StatusInfo":  "#303030",
+		"StatusError": "#d70000",
+
+		"Adapter":             "#303030",
+		"AdapterPowered":      "#008700",
+		"AdapterNotPowered":   "#d70000",
+		"AdapterDiscoverable": "#008787",
+		"AdapterScanning":     "#af8700",
+		"AdapterPairable":     "#8700af",
+		"AdapterAirplane":     "#d75f00",
+		"AdapterFilter":       "#5f5f00",
+
+		"Device":                   "#303030",
+		"DeviceType":               "#303030",
+		"DeviceAlias":              "#303030",
+		"DeviceConnected":          "#000000",
+		"DeviceDiscovered":         "#303030",
+		"DeviceProperty":           "#6c6c6c",
+		"DevicePropertyConnected":  "#008700",
+		"DevicePropertyDiscovered": "#d75f00",
+		"DevicePropertyError":      "#d70000",
+		"DevicePropertyAway":       "#875f00",
+		"DevicePropertyTrusted":    "#005fd7",
+
+		"Menu":     "#303030",
+		"MenuBar":  "#d0d0d0",
+		"MenuItem": "#303030",
+
+		"ProgressBar":  "#005fd7",
+		"ProgressText": "#303030",
+	},
+	"solarized": {
+		"Text":        "#839496",
+		"Border":      "#586e75",
+		"Background":  "#002b36",
+		"StatusInfo":  "#93a1a1",
+		"StatusError": "#dc322f",
+
+		"Adapter":             "#93a1a1",
+		"AdapterPowered":      "#859900",
+		"AdapterNotPowered":   "#dc322f",
+		"AdapterDiscoverable": "#2aa198",
+		"AdapterScanning":     "#b58900",
+		"AdapterPairable":     "#6c71c4",
+		"AdapterAirplane":     "#cb4b16",
+		"AdapterFilter":       "#d33682",
+
+		"Device":                   "#839496",
+		"DeviceType":               "#839496",
+		"DeviceAlias":              "#839496",
+		"DeviceConnected":          "#93a1a1",
+		"DeviceDiscovered":         "#839496",
+		"DeviceProperty":           "#586e75",
+		"DevicePropertyConnected":  "#859900",
+		"DevicePropertyDiscovered": "#cb4b16",
+		"DevicePropertyError":      "#dc322f",
+		"DevicePropertyAway":       "#b58900",
+		"DevicePropertyTrusted":    "#268bd2",
+
+		"Menu":     "#93a1a1",
+		"MenuBar":  "#073642",
+		"MenuItem": "#839496",
+
+		"ProgressBar":  "#268bd2",
+		"ProgressText": "#839496",
+	},
+	"nord": {
+		"Text":        "#d8dee9",
+		"Border":      "#4c566a",
+		"Background":  "#2e3440",
+		"StatusInfo":  "#e5e9f0",
+		"StatusError": "#bf616a",
+
+		"Adapter":             "#eceff4",
+		"AdapterPowered":      "#a3be8c",
+		"AdapterNotPowered":   "#bf616a",
+		"AdapterDiscoverable": "#88c0d0",
+		"AdapterScanning":     "#ebcb8b",
+		"AdapterPairable":     "#b48ead",
+		"AdapterAirplane":     "#d08770",
+		"AdapterFilter":       "#8fbcbb",
+
+		"Device":                   "#d8dee9",
+		"DeviceType":               "#d8dee9",
+		"DeviceAlias":              "#d8dee9",
+		"DeviceConnected":          "#eceff4",
+		"DeviceDiscovered":         "#d8dee9",
+		"DeviceProperty":           "#7b88a1",
+		"DevicePropertyConnected":  "#a3be8c",
+		"DevicePropertyDiscovered": "#d08770",
+		"DevicePropertyError":      "#bf616a",
+		"DevicePropertyAway":       "#ebcb8b",
+		"DevicePropertyTrusted":    "#81a1c1",
+
+		"Menu":     "#eceff4",
+		"MenuBar":  "#3b4252",
+		"MenuItem": "#d8dee9",
+
+		"ProgressBar":  "#88c0d0",
+		"ProgressText": "#d8dee9",
+	},
+}
+
+// ParseThemePreset parses the built-in theme with the provided name. The colors
+// of the preset are used as the defaults when the theme is reloaded.
+func ParseThemePreset(name string) error {
+	preset, ok := ThemePresets[name]
+	if !ok {
+		return fmt.Errorf(
+			"Theme preset '%s' does not exist.\nAvailable presets are '%s'",
+			name, strings.Join(ThemePresetNames(), "', '"),
+		)
+	}
+
+	if err := ParseThemeConfig(preset); err != nil {
+		return err
+	}
+
+	defaultThemeConfig = copyThemeConfig(ThemeConfig)
+
+	return nil
+}
+
+// ThemePresetNames returns the sorted names of the built-in themes.
+func ThemePresetNames() []string {
+	names := make([]string, 0, len(ThemePresets))
+	for name := range ThemePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -3,6 +3,7 @@ package theme
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/alexeyco/simpletable"
 	"github.com/gdamore/tcell/v2"
@@ -10,14 +11,85 @@ import (
 
 // ColorWrap wraps the text content with the modifier element's color.
 func ColorWrap(elementName ThemeContext, elementContent string, attributes ...string) string {
+	if colorsDisabled {
+		return elementContent
+	}
+
 	attr := "::b"
 	if attributes != nil {
 		attr = attributes[0]
 	}
 
+	themeLock.RLock()
+	defer themeLock.RUnlock()
+
+	if style, ok := ThemeStyles[elementName]; ok {
+		attr = style.tag(attr)
+	}
+
 	return fmt.Sprintf("[%s%s]%s[-:-:-]", ThemeConfig[elementName], attr, elementContent)
 }
 
+// GetStyle returns the style of the modifier element. If no style attributes
+// are configured for the element, the provided attributes are used.
+func GetStyle(themeContext ThemeContext, attributes tcell.AttrMask) tcell.Style {
+	if colorsDisabled {
+		return tcell.StyleDefault
+	}
+
+	style := tcell.StyleDefault.Foreground(GetColor(themeContext))
+
+	themeLock.RLock()
+	themeStyle, ok := ThemeStyles[themeContext]
+	themeLock.RUnlock()
+
+	if !ok {
+		return style.Attributes(attributes)
+	}
+
+	if themeStyle.Background != "" {
+		style = style.Background(tcell.GetColor(themeStyle.Background))
+	}
+
+	return style.
+		Bold(themeStyle.Bold).
+		Underline(themeStyle.Underline).
+		Reverse(themeStyle.Reverse)
+}
+
+// tag returns the style tag attributes, in the ':<background>:<flags>' format,
+// with the configured style attributes applied to the provided attributes.
+func (s ThemeStyle) tag(attributes string) string {
+	var background, flags string
+
+	if parts := strings.SplitN(strings.TrimPrefix(attributes, ":"), ":", 2); len(parts) == 2 {
+		background = parts[0]
+	}
+
+	if s.Background != "" {
+		background = s.Background
+	}
+
+	for _, flag := range []struct {
+		Enabled bool
+		Flag    string
+	}{
+		{s.Bold, "b"},
+		{s.Underline, "u"},
+		{s.Reverse, "r"},
+	} {
+		if flag.Enabled {
+			flags += flag.Flag
+		}
+	}
+
+	if flags == "" {
+		flags = "-"
+	}
+
+	return ":" + background + ":" + flags
+}
+
 // ColorName returns the name of the provided color.
 func ColorName(color tcell.Color) string {
 	for n, h := range tcell.ColorNames {
@@ -42,7 +114,11 @@ func BackgroundColor(themeContext ThemeContext) tcell.Color {
 
 // GetColor returns the color of the modifier element.
 func GetColor(themeContext ThemeContext) tcell.Color {
-	color := ThemeConfig[themeContext]
+	if colorsDisabled {
+		return tcell.ColorDefault
+	}
+
+	color := GetColorName(themeContext)
 	if color == "black" {
 		return tcell.Color16
 	}
@@ -50,13 +126,23 @@ func GetColor(themeContext ThemeContext) tcell.Color {
 	return tcell.GetColor(color)
 }
 
-// GetElementData returns the element types and colors in a tabular format.
+// GetColorName returns the configured color name of the modifier element.
+func GetColorName(themeContext ThemeContext) string {
+	themeLock.RLock()
+	defer themeLock.RUnlock()
+
+	return ThemeConfig[themeContext]
+}
+
+// GetElementData returns the element types, colors and style attributes in a tabular format.
 func GetElementData() string {
 	var elements, colors []string
 
+	themeLock.RLock()
 	for element := range ThemeConfig {
 		elements = append(elements, string(element))
 	}
+	themeLock.RUnlock()
 	sort.Strings(elements)
 
 	for color := range tcell.ColorNames {
@@ -70,20 +156,22 @@ func GetElementData() string {
 		Cells: []*simpletable.Cell{
 			{Align: simpletable.AlignCenter, Text: "Theme Element Types"},
 			{Align: simpletable.AlignCenter, Text: "Theme Colors"},
+			{Align: simpletable.AlignCenter, Text: "Theme Style Attributes"},
 		},
 	}
 
-	for i, e := range elements {
-		r := []*simpletable.Cell{}
-		r = append(r, &simpletable.Cell{Text: e}, &simpletable.Cell{Text: colors[i]})
+	for i, c := range colors {
+		var e, a string
 
-		elementsTable.Body.Cells = append(elementsTable.Body.Cells, r)
-
-	}
+		if i < len(elements) {
+			e = elements[i]
+		}
+		if i < len(StyleAttributes) {
+			a = StyleAttributes[i]
+		}
 
-	for _, c := range colors[len(elements):] {
 		r := []*simpletable.Cell{}
-		r = append(r, &simpletable.Cell{Text: ""}, &simpletable.Cell{Text: c})
+		r = append(r, &simpletable.Cell{Text: e}, &simpletable.Cell{Text: c}, &simpletable.Cell{Text: a})
 
 		elementsTable.Body.Cells = append(elementsTable.Body.Cells, r)
 	}
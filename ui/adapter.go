@@ -17,6 +17,10 @@ import (
 // AdapterStatus describes the adapter status display.
 type AdapterStatus struct {
 	view *tview.TextView
+
+	paused  map[string]bool
+	powered map[string]bool
+	lock    sync.Mutex
 }
 
 var adapterStatus AdapterStatus
@@ -29,13 +33,100 @@ func adapterStatusView() *tview.TextView {
 	adapterStatus.view.SetTextAlign(tview.AlignRight)
 	adapterStatus.view.SetBackgroundColor(theme.GetColor(theme.ThemeMenuBar))
 
+	onThemeReload(func() {
+		adapterStatus.view.SetBackgroundColor(theme.GetColor(theme.ThemeMenuBar))
+	})
+
 	return adapterStatus.view
 }
 
+// pauseDiscovery stops discovery on the adapter before connecting to a device,
+// if the "stop-scan-on-connect" option is enabled. The returned function must be
+// called once connecting is complete, to resume discovery if required.
+func pauseDiscovery(adapterPath string) func() {
+	if !cmd.IsPropertyEnabled("stop-scan-on-connect") {
+		return func() {}
+	}
+
+	props, err := UI.Bluez.GetAdapterProperties(adapterPath)
+	if err != nil {
+		return func() {}
+	}
+
+	if discovering, ok := props["Discovering"].Value().(bool); !ok || !discovering {
+		return func() {}
+	}
+
+	if err := UI.Bluez.StopDiscovery(adapterPath); err != nil {
+		return func() {}
+	}
+
+	setDiscoveryPaused(adapterPath, true)
+
+	return func() {
+		setDiscoveryPaused(adapterPath, false)
+
+		if cmd.IsPropertyEnabled("resume-scan-after-connect") {
+			if err := UI.Bluez.StartDiscovery(adapterPath); err != nil {
+				ErrorMessage(err)
+			}
+		}
+	}
+}
+
+// setDiscoveryPaused sets whether discovery is paused on the adapter,
+// and updates the adapter status.
+func setDiscoveryPaused(adapterPath string, paused bool) {
+	adapterStatus.lock.Lock()
+	if adapterStatus.paused == nil {
+		adapterStatus.paused = make(map[string]bool)
+	}
+	if paused {
+		adapterStatus.paused[adapterPath] = true
+	} else {
+		delete(adapterStatus.paused, adapterPath)
+	}
+	adapterStatus.lock.Unlock()
+
+	if adapterPath != UI.Bluez.GetCurrentAdapter().Path {
+		return
+	}
+
+	UI.QueueUpdateDraw(func() {
+		updateAdapterStatus(UI.Bluez.GetCurrentAdapter())
+	})
+}
+
+// setAdapterPowered records the powered state of the adapter, and returns
+// if the adapter was powered on since its state was last recorded.
+func setAdapterPowered(adapter bluez.Adapter) bool {
+	adapterStatus.lock.Lock()
+	defer adapterStatus.lock.Unlock()
+
+	if adapterStatus.powered == nil {
+		adapterStatus.powered = make(map[string]bool)
+	}
+
+	powered, ok := adapterStatus.powered[adapter.Path]
+	adapterStatus.powered[adapter.Path] = adapter.Powered
+
+	return ok && !powered && adapter.Powered
+}
+
+// isDiscoveryPaused returns whether discovery is paused on the adapter.
+func isDiscoveryPaused(adapterPath string) bool {
+	adapterStatus.lock.Lock()
+	defer adapterStatus.lock.Unlock()
+
+	return adapterStatus.paused[adapterPath]
+}
+
 // adapterChange launches a popup with a list of adapters.
 // Changing the selection will change the currently selected adapter.
+// Discovery can be toggled on the highlighted adapter with the scan key,
+// without affecting the discovery state of the other adapters.
 func adapterChange() {
-	setContextMenu(
+	adapterMenu := setContextMenu(
 		"adapter", nil,
 		func(adapterMenu *tview.Table, row, col int) {
 			cell := adapterMenu.GetCell(row, 0)
@@ -97,10 +188,139 @@ func adapterChange() {
 						Background(theme.BackgroundColor(theme.ThemeAdapter)),
 					),
 				)
+				adapterMenu.SetCell(row, 2, tview.NewTableCell("").
+					SetAlign(tview.AlignRight).
+					SetTextColor(theme.GetColor(theme.ThemeAdapterScanning)).
+					SetSelectedStyle(tcell.Style{}.
+						Foreground(theme.GetColor(theme.ThemeAdapterScanning)).
+						Background(theme.BackgroundColor(theme.ThemeAdapter)),
+					),
+				)
+
+				setAdapterScanState(adapterMenu, row, adapter.Discovering)
 			}
 
-			return width, index
+			return width + len(" (Scanning)"), index
 		})
+
+	inputCapture := adapterMenu.GetInputCapture()
+	adapterMenu.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if cmd.KeyOperation(event) != cmd.KeyAdapterToggleScan {
+			return inputCapture(event)
+		}
+
+		row, _ := adapterMenu.GetSelection()
+
+		adapter, ok := adapterMenu.GetCell(row, 0).GetReference().(bluez.Adapter)
+		if ok {
+			go scanAdapter(adapter.Path)
+		}
+
+		return nil
+	})
+}
+
+// updateAdapterMenu updates the discovery state of the adapter
+// within the adapter menu, if it is open.
+func updateAdapterMenu(adapter bluez.Adapter) {
+	modal, ok := ModalExists("adapter")
+	if !ok {
+		return
+	}
+
+	for row := 0; row < modal.Table.GetRowCount(); row++ {
+		menuAdapter, ok := modal.Table.GetCell(row, 0).GetReference().(bluez.Adapter)
+		if !ok || menuAdapter.Path != adapter.Path {
+			continue
+		}
+
+		setAdapterScanState(modal.Table, row, adapter.Discovering)
+	}
+}
+
+// setAdapterScanState sets the discovery state of the adapter
+// at the specified row in the adapter menu.
+func setAdapterScanState(adapterMenu *tview.Table, row int, discovering bool) {
+	var state string
+
+	if discovering {
+		state = "(Scanning)"
+	}
+
+	adapterMenu.GetCell(row, 2).SetText(state)
+}
+
+// getAdapterInfo shows information about the current adapter.
+func getAdapterInfo() {
+	adapter := UI.Bluez.GetCurrentAdapter()
+	if adapter.Path == "" {
+		return
+	}
+
+	infoModal := NewModal("adapterinfo", "Adapter Information", nil, 40, 100)
+	setAdapterInfo(infoModal.Table, adapter)
+
+	infoModal.Height = infoModal.Table.GetRowCount() + 4
+	infoModal.Show()
+}
+
+// setAdapterInfo writes adapter information into the provided table.
+func setAdapterInfo(table *tview.Table, adapter bluez.Adapter) {
+	yesno := func(val bool) string {
+		if !val {
+			return "no"
+		}
+
+		return "yes"
+	}
+
+	table.Clear()
+
+	for row, prop := range [][]string{
+		{"Name", adapter.Name},
+		{"Alias", adapter.Alias},
+		{"Address", adapter.Address},
+		{"Adapter", bluez.GetAdapterID(adapter.Path)},
+		{"Powered", yesno(adapter.Powered)},
+		{"Discoverable", yesno(adapter.Discoverable)},
+		{"Pairable", yesno(adapter.Pairable)},
+		{"Discovering", yesno(adapter.Discovering)},
+		{"Roles", bluez.GetAdapterRoles(adapter)},
+	} {
+		table.SetCell(row, 0, tview.NewTableCell("[::b]"+prop[0]+":").
+			SetExpansion(1).
+			SetReference(adapter).
+			SetAlign(tview.AlignLeft).
+			SetTextColor(theme.GetColor(theme.ThemeText)).
+			SetSelectedStyle(tcell.Style{}.
+				Bold(true).
+				Underline(true),
+			),
+		)
+		table.SetCell(row, 1, tview.NewTableCell(prop[1]).
+			SetExpansion(1).
+			SetAlign(tview.AlignLeft).
+			SetTextColor(theme.GetColor(theme.ThemeText)),
+		)
+	}
+}
+
+// updateAdapterInfo refreshes the adapter information modal,
+// if it is currently displaying the provided adapter.
+func updateAdapterInfo(adapter bluez.Adapter) {
+	infoModal, ok := ModalExists("adapterinfo")
+	if !ok || infoModal.Table == nil {
+		return
+	}
+
+	ref, ok := infoModal.Table.GetCell(0, 0).GetReference().(bluez.Adapter)
+	if !ok || ref.Path != adapter.Path {
+		return
+	}
+
+	row, _ := infoModal.Table.GetSelection()
+	setAdapterInfo(infoModal.Table, adapter)
+	infoModal.Table.Select(row, 0)
 }
 
 // updateAdapterStatus updates the adapter status display.
@@ -129,11 +349,19 @@ func updateAdapterStatus(adapter bluez.Adapter) {
 		properties[name] = enabled
 	}
 
+	filter, _ := UI.Bluez.GetDiscoveryFilter(adapter.Path)
+
 	for _, status := range []struct {
 		Title   string
+		Info    string
 		Enabled bool
 		Color   theme.ThemeContext
 	}{
+		{
+			Title:   "Airplane Mode",
+			Enabled: cmd.IsAirplaneModeEnabled(),
+			Color:   theme.ThemeAdapterAirplane,
+		},
 		{
 			Title:   "Powered",
 			Enabled: properties["Powered"],
@@ -144,6 +372,17 @@ func updateAdapterStatus(adapter bluez.Adapter) {
 			Enabled: properties["Discovering"],
 			Color:   theme.ThemeAdapterScanning,
 		},
+		{
+			Title:   "Scan Paused",
+			Enabled: isDiscoveryPaused(adapter.Path),
+			Color:   theme.ThemeAdapterScanning,
+		},
+		{
+			Title:   "Filter",
+			Info:    filter.String(),
+			Enabled: properties["Discovering"] && !filter.IsEmpty(),
+			Color:   theme.ThemeAdapterFilter,
+		},
 		{
 			Title:   "Discoverable",
 			Enabled: properties["Discoverable"],
@@ -164,11 +403,21 @@ func updateAdapterStatus(adapter bluez.Adapter) {
 			}
 		}
 
-		textColor := theme.ColorName(theme.BackgroundColor(status.Color))
-		bgColor := theme.ThemeConfig[status.Color]
+		title := status.Title
+		if status.Info != "" {
+			title += ": " + tview.Escape(status.Info)
+		}
+
+		region := strings.ToLower(strings.ReplaceAll(status.Title, " ", ""))
+
+		if theme.ColorsDisabled() {
+			state += fmt.Sprintf("[\"%s\"][%s[][\"\"] ", region, title)
+		} else {
+			textColor := theme.ColorName(theme.BackgroundColor(status.Color))
+			bgColor := theme.GetColorName(status.Color)
 
-		region := strings.ToLower(status.Title)
-		state += fmt.Sprintf("[\"%s\"][%s:%s:b] %s [-:-:-][\"\"] ", region, textColor, bgColor, status.Title)
+			state += fmt.Sprintf("[\"%s\"][%s:%s:b] %s [-:-:-][\"\"] ", region, textColor, bgColor, title)
+		}
 
 		regions = append(regions, region)
 	}
@@ -237,12 +486,19 @@ func adapterEvent(signal *dbus.Signal, signalData interface{}) {
 			return
 		}
 
-		if adapter.Path != UI.Bluez.GetCurrentAdapter().Path {
-			return
+		setAdapterScanning(adapter)
+
+		if setAdapterPowered(adapter) && adapter.Path == UI.Bluez.GetCurrentAdapter().Path {
+			go connectPreferredAudio()
 		}
 
 		UI.QueueUpdateDraw(func() {
-			updateAdapterStatus(adapter)
+			updateAdapterMenu(adapter)
+			updateAdapterInfo(adapter)
+
+			if adapter.Path == UI.Bluez.GetCurrentAdapter().Path {
+				updateAdapterStatus(adapter)
+			}
 		})
 
 	case "org.freedesktop.DBus.ObjectManager.InterfacesRemoved":
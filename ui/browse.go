@@ -0,0 +1,266 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+	"github.com/godbus/dbus/v5"
+)
+
+// FileBrowser stores an OBEX file transfer session, which is
+// used to browse the filesystem of a device.
+type FileBrowser struct {
+	device  bluez.Device
+	session dbus.ObjectPath
+	folder  string
+
+	// closed is set when the browser modal is closed, and active
+	// is the number of downloads that are still running. The session
+	// is removed only when both the modal is closed and all the
+	// downloads have finished.
+	closed bool
+	active int
+
+	modal *Modal
+	lock  sync.Mutex
+}
+
+// browseSessionTimeout is the duration to wait for the
+// file transfer session to be created.
+const browseSessionTimeout = 30 * time.Second
+
+// browseFiles creates a file transfer session with the device,
+// and displays the files and folders of its root folder.
+func browseFiles(device bluez.Device) {
+	if !cmd.IsPropertyEnabled("obex") {
+		ErrorMessage(errors.New("The OBEX daemon is not available"))
+		return
+	}
+
+	if !device.HaveService(bluez.OBEX_FILETRANS_SVCLASS_ID) {
+		ErrorMessage(errors.New("File transfer (FTP) profile not supported by " + device.Name))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), browseSessionTimeout)
+
+	startOperation(
+		func() {
+			defer cancel()
+
+			InfoMessage("Connecting to the filesystem of "+device.Name, true)
+
+			session, err := UI.Obex.CreateFileTransferSession(ctx, device.Address)
+			if err != nil {
+				if ctx.Err() != context.Canceled {
+					ErrorMessage(err)
+				}
+
+				return
+			}
+
+			entries, err := UI.Obex.ListFolder(session)
+			if err != nil {
+				UI.Obex.RemoveSession(session)
+				ErrorMessage(err)
+
+				return
+			}
+
+			browser := &FileBrowser{
+				device:  device,
+				session: session,
+				folder:  "/",
+			}
+
+			InfoMessage("Browsing the filesystem of "+device.Name, false)
+
+			UI.QueueUpdateDraw(func() {
+				browser.show(entries)
+			})
+		},
+		func() {
+			cancel()
+			InfoMessage("Cancelled connecting to the filesystem of "+device.Name, false)
+		},
+	)
+}
+
+// show displays the file browser modal.
+func (f *FileBrowser) show(entries []bluez.ObexFolderEntry) {
+	f.modal = NewModal("browse", "Files on "+f.device.Name, nil, 40, 100)
+	f.modal.exitFunc = f.close
+	f.modal.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch cmd.KeyOperation(event) {
+		case cmd.KeyClose:
+			f.modal.Exit(false)
+
+		case cmd.KeySelect:
+			f.selectEntry()
+			return nil
+		}
+
+		return ignoreDefaultEvent(event)
+	})
+
+	f.setEntries(entries)
+
+	f.modal.Show()
+}
+
+// setEntries displays the provided entries in the file browser modal.
+// Folders are listed before files.
+func (f *FileBrowser) setEntries(entries []bluez.ObexFolderEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].IsFolder() != entries[j].IsFolder() {
+			return entries[i].IsFolder()
+		}
+
+		return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+	})
+
+	if f.folder != "/" {
+		entries = append([]bluez.ObexFolderEntry{{Name: "..", Type: "folder"}}, entries...)
+	}
+
+	table := f.modal.Table
+	table.Clear()
+
+	for row, entry := range entries {
+		name, size := entry.Name, ""
+		if entry.IsFolder() {
+			name += "/"
+		} else {
+			size = formatSize(int64(entry.Size))
+		}
+
+		table.SetCell(row, 0, tview.NewTableCell(tview.Escape(name)).
+			SetExpansion(1).
+			SetReference(entry).
+			SetAlign(tview.AlignLeft).
+			SetTextColor(theme.GetColor(theme.ThemeText)).
+			SetSelectedStyle(tcell.Style{}.
+				Foreground(theme.GetColor(theme.ThemeText)).
+				Background(theme.BackgroundColor(theme.ThemeText)),
+			),
+		)
+
+		table.SetCell(row, 1, tview.NewTableCell(size).
+			SetAlign(tview.AlignRight).
+			SetTextColor(theme.GetColor(theme.ThemeText)).
+			SetSelectedStyle(tcell.Style{}.
+				Foreground(theme.GetColor(theme.ThemeText)).
+				Background(theme.BackgroundColor(theme.ThemeText)),
+			),
+		)
+	}
+
+	table.Select(0, 0)
+	table.ScrollToBeginning()
+}
+
+// selectEntry changes to the selected folder, or downloads the selected file.
+func (f *FileBrowser) selectEntry() {
+	row, _ := f.modal.Table.GetSelection()
+
+	entry, ok := f.modal.Table.GetCell(row, 0).GetReference().(bluez.ObexFolderEntry)
+	if !ok {
+		return
+	}
+
+	if entry.IsFolder() {
+		go f.changeFolder(entry.Name)
+		return
+	}
+
+	go f.download(entry)
+}
+
+// changeFolder changes the current folder of the session, and displays its contents.
+func (f *FileBrowser) changeFolder(folder string) {
+	if err := UI.Obex.ChangeFolder(f.session, folder); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	entries, err := UI.Obex.ListFolder(f.session)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	f.lock.Lock()
+	if folder == ".." {
+		f.folder = path.Dir(f.folder)
+	} else {
+		f.folder = path.Join(f.folder, folder)
+	}
+	f.lock.Unlock()
+
+	UI.QueueUpdateDraw(func() {
+		f.setEntries(entries)
+	})
+}
+
+// download copies the file from the device to the receive directory,
+// and shows the progress of the transfer.
+func (f *FileBrowser) download(entry bluez.ObexFolderEntry) {
+	receiveDir, err := getReceiveDir()
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	targetPath := filepath.Join(receiveDir, filepath.Base(entry.Name))
+
+	f.lock.Lock()
+	f.active++
+	f.lock.Unlock()
+
+	defer f.finish()
+
+	transferPath, props, err := UI.Obex.GetFile(f.session, targetPath, entry.Name)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Downloading "+entry.Name+" from "+f.device.Name, false)
+
+	StartProgress(transferPath, props, "", targetPath)
+}
+
+// finish marks a download as finished, and removes the session
+// if the browser modal was closed.
+func (f *FileBrowser) finish() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.active--
+	if f.closed && f.active == 0 {
+		UI.Obex.RemoveSession(f.session)
+	}
+}
+
+// close marks the browser modal as closed, and removes the session
+// if there are no running downloads.
+func (f *FileBrowser) close() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.closed = true
+	if f.active == 0 {
+		UI.Obex.RemoveSession(f.session)
+	}
+}
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"context"
+
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// connectionProfiles launches a popup with the connection profiles
+// specified in the configuration.
+func connectionProfiles() {
+	profiles := cmd.GetConnectionProfiles()
+	if profiles == nil {
+		InfoMessage("No connection profiles are configured", false)
+		return
+	}
+
+	setContextMenu(
+		"connectionprofiles",
+		func(profileMenu *tview.Table) {
+			row, _ := profileMenu.GetSelection()
+
+			name, ok := profileMenu.GetCell(row, 0).GetReference().(string)
+			if !ok {
+				return
+			}
+
+			go connectProfile(name)
+		}, nil,
+		func(profileMenu *tview.Table) (int, int) {
+			var width int
+
+			for row, name := range profiles {
+				if len(name) > width {
+					width = len(name)
+				}
+
+				profileMenu.SetCell(row, 0, tview.NewTableCell(name).
+					SetExpansion(1).
+					SetReference(name).
+					SetAlign(tview.AlignLeft).
+					SetTextColor(theme.GetColor(theme.ThemeText)).
+					SetSelectedStyle(tcell.Style{}.
+						Foreground(theme.GetColor(theme.ThemeText)).
+						Background(theme.BackgroundColor(theme.ThemeText)),
+					),
+				)
+			}
+
+			return width, 0
+		},
+	)
+}
+
+// connectProfile sets the discovery filter of the connection profile
+// if it is specified, and connects to the devices of the profile.
+func connectProfile(name string) {
+	devices, err := cmd.GetConnectionProfile(UI.Bluez, name)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	if filter, ok := cmd.GetConnectionProfileFilter(name); ok {
+		if err := UI.Bluez.SetDiscoveryFilter(UI.Bluez.GetCurrentAdapter().Path, filter); err != nil {
+			ErrorMessage(err)
+			return
+		}
+
+		setMenuItemToggle("adapter", cmd.KeyAdapterToggleFilter, true)
+
+		UI.QueueUpdateDraw(func() {
+			updateAdapterStatus(UI.Bluez.GetCurrentAdapter())
+		})
+
+		if len(devices) == 0 {
+			InfoMessage("Discovery filter of connection profile "+name+" set ("+filter.String()+")", false)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	startOperation(
+		func() {
+			var failed bool
+
+			for _, device := range devices {
+				if ctx.Err() != nil {
+					return
+				}
+
+				InfoMessage("Connecting to "+device.Device.Name, true)
+
+				if err := device.Connect(UI.Bluez); err != nil {
+					cmd.AddDeviceConnectFailure(device.Device.Address)
+					setDeviceError(device.Device.Path, err)
+					ErrorMessage(err)
+
+					failed = true
+
+					continue
+				}
+
+				clearDeviceError(device.Device.Path)
+			}
+
+			if !failed {
+				InfoMessage("Connected to the devices of connection profile "+name, false)
+			}
+		},
+		func() {
+			cancel()
+			InfoMessage("Cancelled connecting to connection profile "+name, false)
+		},
+	)
+}
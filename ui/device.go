@@ -1,10 +1,15 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/darkhz/bluetuith/bluez"
 	"github.com/darkhz/bluetuith/cmd"
@@ -12,16 +17,36 @@ import (
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
 	"github.com/godbus/dbus/v5"
+	"github.com/mattn/go-runewidth"
 )
 
+// DeviceErrors stores the most recent error for each device,
+// which is displayed in the device's row in the DeviceTable.
+type DeviceErrors struct {
+	errors map[string]string
+	timers map[string]*time.Timer
+	lock   sync.Mutex
+}
+
+// deviceErrorTimeout is the duration for which a device error is displayed.
+const deviceErrorTimeout = 5 * time.Second
+
 var DeviceTable *tview.Table
 
+var deviceErrors = DeviceErrors{
+	errors: make(map[string]string),
+	timers: make(map[string]*time.Timer),
+}
+
 // deviceTable sets up and returns the DeviceTable.
 func deviceTable() *tview.Table {
 	DeviceTable = tview.NewTable()
 	DeviceTable.SetSelectorWrap(true)
 	DeviceTable.SetSelectable(true, false)
 	DeviceTable.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	onThemeReload(func() {
+		DeviceTable.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	})
 	DeviceTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch cmd.KeyOperation(event) {
 		case cmd.KeyMenu:
@@ -39,6 +64,14 @@ func deviceTable() *tview.Table {
 
 		return ignoreDefaultEvent(event)
 	})
+	DeviceTable.SetSelectionChangedFunc(func(row, col int) {
+		device, ok := DeviceTable.GetCell(row, 0).GetReference().(bluez.Device)
+		if !ok {
+			return
+		}
+
+		go showMediaPlayer(device)
+	})
 	DeviceTable.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
 		if action == tview.MouseRightClick && DeviceTable.HasFocus() {
 			device := getDeviceFromSelection(false)
@@ -55,10 +88,53 @@ func deviceTable() *tview.Table {
 	return DeviceTable
 }
 
+// tagFilter holds the tag to filter the device list by.
+var tagFilter struct {
+	tag  string
+	lock sync.Mutex
+}
+
+// searchFilter holds the text to filter the device list by, which is
+// matched against the names, aliases and addresses of the devices.
+var searchFilter struct {
+	text string
+	lock sync.Mutex
+}
+
+// classFilter holds the device class filter which is applied to the device list
+// and the discovery filter, along with the discovery filter which was set before
+// the class filter was applied.
+var classFilter struct {
+	filter   bluez.ClassFilter
+	enabled  bool
+	previous bluez.DiscoveryFilter
+
+	lock sync.Mutex
+}
+
 // setupDevices initializes the bluez DBus interface, sets up
 // a bluez event listener via watchEvent, and lists the devices.
 func setupDevices() {
+	UI.Bluez.SetConnectQueueHandler(refreshDeviceRow)
+
+	for _, device := range UI.Bluez.GetAllDevices() {
+		cmd.RecordStateChange(device.Address, device.Connected)
+	}
+
+	for _, adapter := range UI.Bluez.GetAdapters() {
+		setAdapterPowered(adapter)
+	}
+
+	for _, device := range UI.Bluez.GetDevices() {
+		if device.Connected {
+			cmd.TrackDeviceConnection(device.Address)
+		}
+
+		setDeviceSeen(device)
+	}
+
 	listDevices()
+	checkIdleDevices()
 	go watchEvent()
 }
 
@@ -74,22 +150,783 @@ func listDevices() {
 	)
 	setMenuBarHeader(theme.ColorWrap(theme.ThemeAdapter, headerText, "::bu"))
 
+	devices := UI.Bluez.GetDevices()
+	sortDevices(devices)
+
 	DeviceTable.Clear()
-	for i, device := range UI.Bluez.GetDevices() {
-		setDeviceTableInfo(i, device)
+	for _, device := range devices {
+		if primary, _, grouped := getDeviceGroup(device); grouped && primary.Path != device.Path {
+			continue
+		}
+
+		if !matchesDeviceFilters(device) {
+			continue
+		}
+
+		setDeviceTableInfo(DeviceTable.GetRowCount(), device)
 	}
 	DeviceTable.Select(0, 0)
 }
 
-// connectDeviceByAddress connects to a device based on the provided address
-// which was parsed from the "connect-bdaddr" command-line option.
+// setTagFilter sets the tag to filter the device list by.
+// An empty tag clears the filter.
+func setTagFilter(tag string) {
+	tagFilter.lock.Lock()
+	defer tagFilter.lock.Unlock()
+
+	tagFilter.tag = tag
+}
+
+// setSearchFilter sets the text to filter the device list by.
+// An empty text clears the filter.
+func setSearchFilter(text string) {
+	searchFilter.lock.Lock()
+	defer searchFilter.lock.Unlock()
+
+	searchFilter.text = strings.ToLower(strings.TrimSpace(text))
+}
+
+// matchesSearchFilter returns if the name, alias or address
+// of the device contains the text of the search filter.
+func matchesSearchFilter(device bluez.Device) bool {
+	searchFilter.lock.Lock()
+	text := searchFilter.text
+	searchFilter.lock.Unlock()
+
+	if text == "" {
+		return true
+	}
+
+	for _, value := range []string{device.Name, device.Alias, device.Address} {
+		if strings.Contains(strings.ToLower(value), text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// startDeviceSearch shows the search input in the status bar, and filters the
+// device list as the search text is typed. The filter is kept when the search
+// is confirmed, and cleared when the search is cancelled.
+func startDeviceSearch() {
+	UI.QueueUpdateDraw(func() {
+		exit := func(clear bool) {
+			UI.Status.InputField.SetChangedFunc(nil)
+
+			if clear {
+				setSearchFilter("")
+				listDevices()
+			}
+
+			UI.Status.SwitchToPage("messages")
+
+			_, item := UI.Pages.GetFrontPage()
+			UI.SetFocus(item)
+		}
+
+		searchFilter.lock.Lock()
+		UI.Status.InputField.SetText(searchFilter.text)
+		searchFilter.lock.Unlock()
+
+		UI.Status.InputField.SetLabel("[::b]Search: ")
+		UI.Status.InputField.SetAcceptanceFunc(nil)
+		UI.Status.InputField.SetChangedFunc(func(text string) {
+			setSearchFilter(text)
+			listDevices()
+		})
+		UI.Status.InputField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch cmd.KeyOperation(event) {
+			case cmd.KeySelect:
+				exit(false)
+				return nil
+
+			case cmd.KeyClose:
+				exit(true)
+				return nil
+			}
+
+			return event
+		})
+
+		UI.Status.SwitchToPage("input")
+		UI.SetFocus(UI.Status.InputField)
+	})
+}
+
+// setClassFilter applies the device class filter with the provided name to the
+// device list and the discovery filter of the adapter. The "all" filter clears
+// the class filter, and restores the previous discovery filter.
+func setClassFilter(adapterPath, name string) error {
+	classFilter.lock.Lock()
+	defer classFilter.lock.Unlock()
+
+	if name == "all" {
+		if !classFilter.enabled {
+			return nil
+		}
+
+		if err := UI.Bluez.SetDiscoveryFilter(adapterPath, classFilter.previous); err != nil {
+			return err
+		}
+
+		classFilter.enabled = false
+
+		return nil
+	}
+
+	filter, ok := bluez.GetClassFilter(name)
+	if !ok {
+		return fmt.Errorf("Unknown device class filter '%s'", name)
+	}
+
+	if !classFilter.enabled {
+		classFilter.previous, _ = UI.Bluez.GetDiscoveryFilter(adapterPath)
+	}
+
+	discoveryFilter := classFilter.previous
+	if len(filter.Services) > 0 {
+		discoveryFilter.UUIDs = filter.UUIDs()
+	}
+
+	if err := UI.Bluez.SetDiscoveryFilter(adapterPath, discoveryFilter); err != nil {
+		return err
+	}
+
+	classFilter.filter = filter
+	classFilter.enabled = true
+
+	return nil
+}
+
+// classFilterSelect shows a popup to select the device class filter.
+func classFilterSelect() {
+	names := []string{"all"}
+	for _, filter := range bluez.ClassFilters {
+		names = append(names, filter.Name)
+	}
+
+	setContextMenu(
+		"device",
+		func(classMenu *tview.Table) {
+			row, _ := classMenu.GetSelection()
+
+			cell := classMenu.GetCell(row, 0)
+			if cell == nil {
+				return
+			}
+
+			name, ok := cell.GetReference().(string)
+			if !ok {
+				return
+			}
+
+			go func() {
+				if err := setClassFilter(UI.Bluez.GetCurrentAdapter().Path, name); err != nil {
+					ErrorMessage(err)
+					return
+				}
+
+				if name == "all" {
+					InfoMessage("Device class filter cleared", false)
+				} else {
+					InfoMessage("Showing "+name+" devices", false)
+				}
+
+				UI.QueueUpdateDraw(func() {
+					listDevices()
+					updateAdapterStatus(UI.Bluez.GetCurrentAdapter())
+				})
+			}()
+		}, nil,
+		func(classMenu *tview.Table) (int, int) {
+			var width int
+
+			for row, name := range names {
+				if len(name) > width {
+					width = len(name)
+				}
+
+				classMenu.SetCell(row, 0, tview.NewTableCell(strings.ToUpper(name[:1])+name[1:]).
+					SetExpansion(1).
+					SetReference(name).
+					SetAlign(tview.AlignLeft).
+					SetTextColor(theme.GetColor(theme.ThemeText)).
+					SetSelectedStyle(tcell.Style{}.
+						Foreground(theme.GetColor(theme.ThemeText)).
+						Background(theme.BackgroundColor(theme.ThemeText)),
+					),
+				)
+			}
+
+			return width, 0
+		},
+	)
+}
+
+// matchesDeviceFilters returns if the device should be listed according to
+// the tag filter and the device class filter.
+func matchesDeviceFilters(device bluez.Device) bool {
+	tagFilter.lock.Lock()
+	tag := tagFilter.tag
+	tagFilter.lock.Unlock()
+
+	if tag != "" && !cmd.HasDeviceTag(device.Address, tag) {
+		return false
+	}
+
+	if !matchesSearchFilter(device) {
+		return false
+	}
+
+	classFilter.lock.Lock()
+	defer classFilter.lock.Unlock()
+
+	return !classFilter.enabled || classFilter.filter.Matches(device)
+}
+
+// getDeviceGroup returns the primary device and its partner, if the provided
+// device is one of a pair of earbuds. The primary device is the one with
+// the lower address, and is the one displayed in the DeviceTable.
+func getDeviceGroup(device bluez.Device) (bluez.Device, bluez.Device, bool) {
+	if cmd.IsPropertyEnabled("no-device-grouping") {
+		return device, bluez.Device{}, false
+	}
+
+	partner, ok := UI.Bluez.GetDevicePartner(device)
+	if !ok {
+		return device, bluez.Device{}, false
+	}
+
+	if partner.Address < device.Address {
+		return partner, device, true
+	}
+
+	return device, partner, true
+}
+
+// setDeviceGroupInfo updates the DeviceTable with the primary device
+// of the provided device's group, and removes the partner device from it.
+func setDeviceGroupInfo(device bluez.Device) {
+	primary, partner, grouped := getDeviceGroup(device)
+	if grouped {
+		if row, ok := checkDeviceTable(partner.Path); ok {
+			DeviceTable.RemoveRow(row)
+		}
+	}
+
+	row, ok := checkDeviceTable(primary.Path)
+	if !ok {
+		if !matchesDeviceFilters(primary) {
+			return
+		}
+
+		row = getDeviceSortedRow(primary)
+		if row < DeviceTable.GetRowCount() {
+			DeviceTable.InsertRow(row)
+		}
+	}
+
+	setDeviceTableInfo(row, primary)
+}
+
+// deviceSortOverride holds the sort order which was selected with the
+// sort toggle keybinding, and overrides the "device-sort" option.
+var deviceSortOverride struct {
+	spec string
+	lock sync.Mutex
+}
+
+// The sort orders which are toggled between with the sort toggle keybinding.
+const (
+	deviceSortName   = "name:asc"
+	deviceSortSignal = "rssi:desc,name:asc"
+)
+
+// getDeviceSort returns the sort order of the device list.
+func getDeviceSort() string {
+	deviceSortOverride.lock.Lock()
+	defer deviceSortOverride.lock.Unlock()
+
+	if deviceSortOverride.spec != "" {
+		return deviceSortOverride.spec
+	}
+
+	return cmd.GetProperty("device-sort")
+}
+
+// toggleDeviceSort toggles the sort order of the device list between the
+// device names and the signal strengths, and returns if the device list is
+// now sorted by the signal strengths.
+func toggleDeviceSort() bool {
+	signal := getDeviceSort() != deviceSortSignal
+
+	deviceSortOverride.lock.Lock()
+	defer deviceSortOverride.lock.Unlock()
+
+	deviceSortOverride.spec = deviceSortName
+	if signal {
+		deviceSortOverride.spec = deviceSortSignal
+	}
+
+	return signal
+}
+
+// sortDevices sorts the devices according to the sort order of the device list.
+func sortDevices(devices []bluez.Device) {
+	if getDeviceSort() == "" {
+		return
+	}
+
+	sort.SliceStable(devices, func(i, j int) bool {
+		return compareDevices(devices[i], devices[j]) < 0
+	})
+}
+
+// moveDeviceRow moves the row of an updated device to its position according
+// to the sort order of the device list, and returns the new row of the device.
+// The selection is moved along with the selected device.
+func moveDeviceRow(row int, device bluez.Device) int {
+	if getDeviceSort() == "" {
+		return row
+	}
+
+	DeviceTable.RemoveRow(row)
+
+	sortedRow := getDeviceSortedRow(device)
+	if sortedRow < DeviceTable.GetRowCount() {
+		DeviceTable.InsertRow(sortedRow)
+	}
+	if sortedRow == row {
+		return row
+	}
+
+	selected, _ := DeviceTable.GetSelection()
+	switch {
+	case selected == row:
+		selected = sortedRow
+
+	case row < selected && sortedRow >= selected:
+		selected--
+
+	case row > selected && sortedRow <= selected:
+		selected++
+
+	default:
+		return sortedRow
+	}
+	DeviceTable.Select(selected, 0)
+
+	return sortedRow
+}
+
+// getDeviceSortedRow returns the DeviceTable row where the device
+// should be inserted, according to the sort order of the device list.
+func getDeviceSortedRow(device bluez.Device) int {
+	rows := DeviceTable.GetRowCount()
+	if getDeviceSort() == "" {
+		return rows
+	}
+
+	for row := 0; row < rows; row++ {
+		ref, ok := DeviceTable.GetCell(row, 0).GetReference().(bluez.Device)
+		if !ok {
+			continue
+		}
+
+		if compareDevices(device, ref) < 0 {
+			return row
+		}
+	}
+
+	return rows
+}
+
+// compareDevices compares two devices according to the sort order of the device list.
+// It returns a negative value if the first device should be listed before
+// the second, a positive value if it should be listed after, and zero otherwise.
+//
+//gocyclo:ignore
+func compareDevices(a, b bluez.Device) int {
+	compareBool := func(x, y bool) int {
+		switch {
+		case x == y:
+			return 0
+
+		case !x:
+			return -1
+		}
+
+		return 1
+	}
+
+	compareInt := func(x, y int) int {
+		switch {
+		case x < y:
+			return -1
+
+		case x > y:
+			return 1
+		}
+
+		return 0
+	}
+
+	deviceName := func(device bluez.Device) string {
+		if device.Name == "" {
+			return device.Address
+		}
+
+		return device.Name
+	}
+
+	for _, keyOrder := range strings.Split(getDeviceSort(), ",") {
+		var result int
+
+		key, order, _ := strings.Cut(keyOrder, ":")
+
+		switch key {
+		case "favorite":
+			result = compareBool(
+				cmd.IsDevicePropertyEnabled(a.Address, "favorite"),
+				cmd.IsDevicePropertyEnabled(b.Address, "favorite"),
+			)
+
+		case "connected":
+			result = compareBool(a.Connected, b.Connected)
+
+		case "paired":
+			result = compareBool(a.Paired, b.Paired)
+
+		case "trusted":
+			result = compareBool(a.Trusted, b.Trusted)
+
+		case "blocked":
+			result = compareBool(a.Blocked, b.Blocked)
+
+		case "name":
+			result = strings.Compare(strings.ToLower(deviceName(a)), strings.ToLower(deviceName(b)))
+
+		case "alias":
+			result = strings.Compare(strings.ToLower(a.Alias), strings.ToLower(b.Alias))
+
+		case "address":
+			result = strings.Compare(a.Address, b.Address)
+
+		case "type":
+			result = strings.Compare(a.Type, b.Type)
+
+		case "battery":
+			result = compareInt(a.BatteryPercentage, b.BatteryPercentage)
+
+		case "rssi":
+			// Devices which are not advertising are always listed last.
+			if a.RSSI >= 0 || b.RSSI >= 0 {
+				if result = compareBool(a.RSSI >= 0, b.RSSI >= 0); result != 0 {
+					return result
+				}
+
+				continue
+			}
+
+			result = compareInt(int(a.RSSI), int(b.RSSI))
+		}
+
+		if order == "desc" {
+			result = -result
+		}
+
+		if result != 0 {
+			return result
+		}
+	}
+
+	return 0
+}
+
+// connectDeviceByAddress connects to the devices based on the provided addresses
+// which were parsed from the "connect-bdaddr" command-line option.
 func connectDeviceByAddress() {
-	address := cmd.GetProperty("connect-bdaddr")
-	if address == "" || UI.Bluez == nil {
+	addresses := cmd.GetProperty("connect-bdaddr")
+	if addresses == "" || UI.Bluez == nil {
+		return
+	}
+
+	if !strings.Contains(addresses, ",") && len(cmd.GetExitErrors()) == 0 {
+		go connect(addresses)
+		return
+	}
+
+	go connectDevicesByAddress(strings.Split(addresses, ","))
+}
+
+// connectDevicesByAddress connects to the devices with the provided addresses in sequence.
+// If a device cannot be connected to, the remaining devices are still connected to, and
+// the devices which could not be found or connected to are reported after all the
+// connections. These errors are also printed when the application is closed, and
+// cause it to exit with a non-zero status.
+func connectDevicesByAddress(addresses []string) {
+	var connected int
+
+	for i, address := range addresses {
+		var device bluez.Device
+		for _, d := range UI.Bluez.GetDevices() {
+			if d.Address == address {
+				device = d
+				break
+			}
+		}
+		if device.Path == "" {
+			cmd.AddExitError(fmt.Sprintf("Device '%s' was not found on the current adapter.", address))
+			continue
+		}
+		if device.Connected {
+			connected++
+			continue
+		}
+
+		InfoMessage(fmt.Sprintf("Connecting to %s (%d of %d)", device.Name, i+1, len(addresses)), true)
+
+		if err := connectDevice(context.Background(), getPreferredAdapterDevice(device)); err != nil {
+			cmd.AddDeviceConnectFailure(device.Address)
+			cmd.AddExitError(fmt.Sprintf("Could not connect to device '%s' (%s): %s", device.Name, device.Address, err.Error()))
+			setDeviceError(device.Path, err)
+
+			continue
+		}
+
+		clearDeviceError(device.Path)
+		cmd.SetManualDisconnect(device.Address, false)
+		connected++
+	}
+
+	if errors := cmd.GetExitErrors(); len(errors) > 0 {
+		ErrorMessage(
+			fmt.Errorf(
+				"Connected to %d of %d devices: %s",
+				connected, connected+len(errors), strings.Join(errors, " "),
+			),
+		)
+
+		return
+	}
+
+	InfoMessage(fmt.Sprintf("Connected to %d devices", connected), false)
+}
+
+// autoConnecting holds the devices which are being connected to
+// automatically by the "auto-connect" option.
+var autoConnecting struct {
+	devices map[string]struct{}
+	lock    sync.Mutex
+}
+
+// reconnectTimeout is the duration after which a reconnection
+// attempt by the "auto-reconnect" option is cancelled.
+const reconnectTimeout = 15 * time.Second
+
+// reconnectDevices reconnects to the devices which were connected when the application
+// was last closed, if the "auto-reconnect" option is enabled. Devices which are not
+// present on the current adapter are skipped, and failed reconnections are reported
+// without stopping the reconnections of the other devices.
+func reconnectDevices() {
+	if !cmd.IsPropertyEnabled("auto-reconnect") || cmd.GetProperty("connect-bdaddr") != "" {
+		return
+	}
+
+	var reconnected int
+	var failed []string
+
+	for _, address := range cmd.GetReconnectDevices() {
+		var device bluez.Device
+		for _, d := range UI.Bluez.GetDevices() {
+			if d.Address == address {
+				device = d
+				break
+			}
+		}
+		if device.Path == "" || device.Connected || device.Blocked || cmd.IsAutoConnectSuppressed(device.Address) ||
+			cmd.GetDeviceConnectDirection(device.Address) == "incoming" {
+			continue
+		}
+
+		// The audio devices are connected by connectPreferredAudio,
+		// so that only the preferred audio device is connected.
+		if isAudioSink(device) && cmd.IsPropertyEnabled("prefer-last-audio") {
+			continue
+		}
+
+		InfoMessage("Reconnecting to "+device.Name, true)
+
+		if err := reconnectDevice(device); err != nil {
+			cmd.AddDeviceConnectFailure(device.Address)
+			setDeviceError(device.Path, err)
+			failed = append(failed, device.Name)
+
+			continue
+		}
+
+		clearDeviceError(device.Path)
+		reconnected++
+	}
+
+	switch {
+	case failed != nil:
+		ErrorMessage(fmt.Errorf("Could not reconnect to %s", strings.Join(failed, ", ")))
+
+	case reconnected > 0:
+		InfoMessage(fmt.Sprintf("Reconnected to %d device(s)", reconnected), false)
+	}
+}
+
+// reconnectDevice connects to the device using its preferred adapter, and cancels
+// the connection if it does not complete within the reconnect timeout. Discovery
+// is paused while the device is connecting.
+func reconnectDevice(device bluez.Device) error {
+	device = getPreferredAdapterDevice(device)
+	if device.Connected {
+		return nil
+	}
+
+	resumeDiscovery := pauseDiscovery(device.Adapter)
+	defer resumeDiscovery()
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconnectTimeout)
+	defer cancel()
+
+	err := connectDevice(ctx, device)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("Timed out while reconnecting to %s", device.Name)
+	}
+
+	return err
+}
+
+// SaveConnectedDevices saves the addresses of the currently connected devices,
+// to reconnect to them on the next startup.
+func SaveConnectedDevices() {
+	if UI.Bluez == nil {
+		return
+	}
+
+	var addresses []string
+	for _, device := range UI.Bluez.GetAllDevices() {
+		if device.Connected {
+			addresses = append(addresses, device.Address)
+		}
+	}
+
+	cmd.SetReconnectDevices(addresses)
+}
+
+// connectPreferredAudio connects to the most recently manually connected audio device,
+// if the "prefer-last-audio" option is enabled and no other trusted audio device is
+// connected. If it is unavailable, the other trusted audio devices are tried in the
+// order they were last connected.
+func connectPreferredAudio() {
+	if !cmd.IsPropertyEnabled("prefer-last-audio") || cmd.GetProperty("connect-bdaddr") != "" {
+		return
+	}
+
+	lastAudioDevice := cmd.GetLastAudioDevice()
+
+	var preferred bluez.Device
+	var others []bluez.Device
+
+	for _, device := range UI.Bluez.GetDevices() {
+		if !isAudioSink(device) || device.Blocked || cmd.IsAutoConnectSuppressed(device.Address) {
+			continue
+		}
+
+		if device.Address == lastAudioDevice {
+			preferred = device
+			continue
+		}
+
+		if device.Trusted && device.Paired {
+			others = append(others, device)
+		}
+	}
+
+	if preferred.Path != "" && preferred.Connected {
 		return
 	}
 
-	go connect(address)
+	sort.SliceStable(others, func(i, j int) bool {
+		a, _ := cmd.GetDeviceStats(others[i].Address)
+		b, _ := cmd.GetDeviceStats(others[j].Address)
+
+		return a.LastConnected.After(b.LastConnected)
+	})
+
+	for _, device := range others {
+		if device.Connected {
+			return
+		}
+	}
+
+	if preferred.Path != "" {
+		if err := reconnectDevice(preferred); err == nil {
+			InfoMessage("Connected to the preferred audio device "+preferred.Name, false)
+			return
+		}
+	}
+
+	for _, device := range others {
+		if err := reconnectDevice(device); err == nil {
+			InfoMessage("Connected to the audio device "+device.Name, false)
+			return
+		}
+	}
+}
+
+// autoConnectDevice connects to the device when it is seen on the current adapter, if it
+// is listed in the "auto-connect" option, paired, not blocked and not already connected. After a
+// failed connection, the device is not connected to again until the backoff elapses.
+func autoConnectDevice(device bluez.Device) {
+	if device.Adapter != UI.Bluez.GetCurrentAdapter().Path ||
+		device.Connected || !device.Paired || device.Blocked || device.RSSI >= 0 ||
+		!cmd.IsAutoConnectDevice(device.Address) || !cmd.CanAutoConnect(device.Address) ||
+		cmd.IsAutoConnectSuppressed(device.Address) {
+		return
+	}
+
+	autoConnecting.lock.Lock()
+	defer autoConnecting.lock.Unlock()
+
+	if autoConnecting.devices == nil {
+		autoConnecting.devices = make(map[string]struct{})
+	}
+	if _, ok := autoConnecting.devices[device.Path]; ok {
+		return
+	}
+	autoConnecting.devices[device.Path] = struct{}{}
+
+	go func() {
+		defer func() {
+			autoConnecting.lock.Lock()
+			delete(autoConnecting.devices, device.Path)
+			autoConnecting.lock.Unlock()
+		}()
+
+		InfoMessage("Automatically connecting to "+device.Name, true)
+
+		if err := reconnectDevice(device); err != nil {
+			delay := cmd.SetAutoConnectResult(device.Address, true)
+			cmd.AddDeviceConnectFailure(device.Address)
+
+			ErrorMessage(fmt.Errorf("Could not automatically connect to %s, retrying after %s: %w", device.Name, delay, err))
+			return
+		}
+
+		cmd.SetAutoConnectResult(device.Address, false)
+		InfoMessage("Automatically connected to "+device.Name, false)
+	}()
+}
+
+// isAudioSink returns if the device is an audio sink.
+func isAudioSink(device bluez.Device) bool {
+	return device.HaveService(bluez.AUDIO_SINK_SVCLASS_ID)
 }
 
 // checkDeviceTable iterates through the DeviceTable and checks
@@ -121,6 +958,69 @@ func getDeviceInfo() {
 		return
 	}
 
+	infoModal := NewModal("info", "Device Information", nil, 40, 100)
+	infoModal.Table.SetSelectionChangedFunc(func(row, col int) {
+		_, _, _, height := infoModal.Table.GetRect()
+		infoModal.Table.SetOffset(row-((height-1)/2), 0)
+	})
+
+	setDeviceInfo(infoModal.Table, device)
+
+	infoModal.Height = infoModal.Table.GetRowCount() + 4
+	if infoModal.Height > 60 {
+		infoModal.Height = 60
+	}
+
+	infoModal.Show()
+}
+
+// getServiceRecords shows the service records of a device. If the service records
+// could not be queried, the advertised service UUIDs are shown instead.
+func getServiceRecords(device bluez.Device, records []string, recordsErr error) {
+	recordsModal := NewModal("servicerecords", "Service Records ("+device.Name+")", nil, 40, 100)
+	recordsModal.Table.SetSelectionChangedFunc(func(row, col int) {
+		_, _, _, height := recordsModal.Table.GetRect()
+		recordsModal.Table.SetOffset(row-((height-1)/2), 0)
+	})
+
+	if recordsErr != nil {
+		records = []string{
+			"[::b]" + tview.Escape(recordsErr.Error()),
+			"[::b]Advertised services:",
+		}
+
+		for _, serviceUUID := range device.UUIDs {
+			records = append(records, "  "+bluez.ServiceType(serviceUUID)+" ("+serviceUUID+")")
+		}
+	} else {
+		for i, record := range records {
+			records[i] = tview.Escape(record)
+		}
+	}
+
+	for row, record := range records {
+		recordsModal.Table.SetCell(row, 0, tview.NewTableCell(record).
+			SetExpansion(1).
+			SetAlign(tview.AlignLeft).
+			SetTextColor(theme.GetColor(theme.ThemeText)).
+			SetSelectedStyle(tcell.Style{}.
+				Bold(true),
+			),
+		)
+	}
+
+	recordsModal.Height = recordsModal.Table.GetRowCount() + 4
+	if recordsModal.Height > 60 {
+		recordsModal.Height = 60
+	}
+
+	recordsModal.Show()
+}
+
+// setDeviceInfo writes device information into the provided table.
+//
+//gocyclo:ignore
+func setDeviceInfo(table *tview.Table, device bluez.Device) {
 	yesno := func(val bool) string {
 		if !val {
 			return "no"
@@ -142,16 +1042,62 @@ func getDeviceInfo() {
 		{"Blocked", yesno(device.Blocked)},
 		{"LegacyPairing", yesno(device.LegacyPairing)},
 	}
+	if manufacturer, ok := device.Manufacturer(); ok {
+		props = append(props, []string{"Manufacturer", manufacturer})
+	}
 	if device.Modalias != "" {
 		props = append(props, []string{"Modalias", device.Modalias})
 	}
+	if appearance, ok := device.AppearanceName(); ok {
+		props = append(props, []string{"Appearance", appearance})
+	}
+	if device.Icon != "" {
+		props = append(props, []string{"Icon", device.Icon})
+	}
+	if device.BatteryPercentage >= 0 {
+		props = append(props, []string{"Battery", strconv.Itoa(device.BatteryPercentage) + "%"})
+	}
+	if device.Connected {
+		role, _ := bluez.GetLinkRole(device.Adapter, device.Address)
+		props = append(props, []string{"LinkRole", role})
+
+		if codec, ok := getDeviceCodec(device); ok {
+			props = append(props, []string{"Codec", codec})
+		}
+	}
+	if change, ok := cmd.GetStateChange(device.Address); ok {
+		props = append(props, []string{"StateChange", formatStateChange(change, true)})
+	}
+	if tags := cmd.GetDeviceTags(device.Address); tags != nil {
+		props = append(props, []string{"Tags", strings.Join(tags, ", ")})
+	}
+	if stats, ok := cmd.GetDeviceStats(device.Address); ok {
+		props = append(props, []string{"Statistics", formatDeviceStats(stats)})
+	}
 	props = append(props, []string{"UUIDs", ""})
 
-	infoModal := NewModal("info", "Device Information", nil, 40, 100)
-	infoModal.Table.SetSelectionChangedFunc(func(row, col int) {
-		_, _, _, height := infoModal.Table.GetRect()
-		infoModal.Table.SetOffset(row-((height-1)/2), 0)
-	})
+	table.Clear()
+
+	setInfoCell := func(row, col int, text string, expansion int) {
+		table.SetCell(row, col, tview.NewTableCell(text).
+			SetExpansion(expansion).
+			SetAlign(tview.AlignLeft).
+			SetTextColor(theme.GetColor(theme.ThemeText)),
+		)
+	}
+
+	setInfoHeader := func(row int, text string) {
+		table.SetCell(row, 0, tview.NewTableCell("[::b]"+text+":").
+			SetExpansion(1).
+			SetReference(device).
+			SetAlign(tview.AlignLeft).
+			SetTextColor(theme.GetColor(theme.ThemeText)).
+			SetSelectedStyle(tcell.Style{}.
+				Bold(true).
+				Underline(true),
+			),
+		)
+	}
 
 	for i, prop := range props {
 		propName := prop[0]
@@ -163,48 +1109,249 @@ func getDeviceInfo() {
 
 		case "Class":
 			propValue += " (" + device.Type + ")"
-		}
 
-		infoModal.Table.SetCell(i, 0, tview.NewTableCell("[::b]"+propName+":").
-			SetExpansion(1).
-			SetAlign(tview.AlignLeft).
-			SetTextColor(theme.GetColor(theme.ThemeText)).
-			SetSelectedStyle(tcell.Style{}.
-				Bold(true).
-				Underline(true),
-			),
-		)
+		case "Modalias":
+			if modalias, ok := bluez.ParseModalias(propValue); ok {
+				propValue += " (" + modalias.String() + ")"
+			}
+		}
 
-		infoModal.Table.SetCell(i, 1, tview.NewTableCell(propValue).
-			SetExpansion(1).
-			SetAlign(tview.AlignLeft).
-			SetTextColor(theme.GetColor(theme.ThemeText)),
-		)
+		setInfoHeader(i, propName)
+		setInfoCell(i, 1, propValue, 1)
 	}
 
-	rows := infoModal.Table.GetRowCount() - 1
+	rows := table.GetRowCount() - 1
 	for i, serviceUUID := range device.UUIDs {
-		serviceType := bluez.ServiceType(serviceUUID)
-		serviceUUID = "(" + serviceUUID + ")"
+		serviceName := bluez.ServiceName(serviceUUID)
+		if serviceName == serviceUUID {
+			setInfoCell(rows+i, 1, serviceUUID, 1)
+			continue
+		}
 
-		infoModal.Table.SetCell(rows+i, 1, tview.NewTableCell(serviceType).
-			SetExpansion(1).
-			SetAlign(tview.AlignLeft).
-			SetTextColor(theme.GetColor(theme.ThemeText)),
-		)
+		setInfoCell(rows+i, 1, serviceName, 1)
+		setInfoCell(rows+i, 2, "("+serviceUUID+")", 0)
+	}
 
-		infoModal.Table.SetCell(rows+i, 2, tview.NewTableCell(serviceUUID).
-			SetExpansion(0).
-			SetTextColor(theme.GetColor(theme.ThemeText)),
-		)
+	if len(device.ManufacturerData) > 0 {
+		rows = table.GetRowCount()
+		setInfoHeader(rows, "ManufacturerData")
+
+		companyIDs := make([]int, 0, len(device.ManufacturerData))
+		for companyID := range device.ManufacturerData {
+			companyIDs = append(companyIDs, int(companyID))
+		}
+		sort.Ints(companyIDs)
+
+		for _, companyID := range companyIDs {
+			data := device.ManufacturerData[uint16(companyID)]
+
+			company := fmt.Sprintf("0x%04X", companyID)
+			if name, ok := bluez.CompanyName(uint16(companyID)); ok {
+				company += " " + name
+			}
+
+			for i, line := range bluez.FormatAdvertisementLines(data) {
+				setInfoCell(rows, 1, line, 1)
+				if i == 0 {
+					setInfoCell(rows, 2, "("+company+")", 0)
+				}
+				rows++
+			}
+
+			if decoded := bluez.DecodeManufacturerData(uint16(companyID), data); decoded != "" {
+				setInfoCell(rows, 1, decoded, 1)
+				rows++
+			}
+		}
 	}
 
-	infoModal.Height = infoModal.Table.GetRowCount() + 4
-	if infoModal.Height > 60 {
-		infoModal.Height = 60
+	if len(device.ServiceData) > 0 {
+		rows = table.GetRowCount()
+		setInfoHeader(rows, "ServiceData")
+
+		serviceUUIDs := make([]string, 0, len(device.ServiceData))
+		for serviceUUID := range device.ServiceData {
+			serviceUUIDs = append(serviceUUIDs, serviceUUID)
+		}
+		sort.Strings(serviceUUIDs)
+
+		for _, serviceUUID := range serviceUUIDs {
+			data := device.ServiceData[serviceUUID]
+
+			for i, line := range bluez.FormatAdvertisementLines(data) {
+				setInfoCell(rows, 1, line, 1)
+				if i == 0 {
+					setInfoCell(rows, 2, "("+serviceUUID+")", 0)
+				}
+				rows++
+			}
+
+			if decoded := bluez.DecodeServiceData(serviceUUID, data); decoded != "" {
+				setInfoCell(rows, 1, decoded, 1)
+				rows++
+			}
+		}
 	}
+}
 
-	infoModal.Show()
+// getDeviceCodec returns the audio codecs in use by the media transports of the device.
+// If the device supports audio but the codec is not known, "unknown" is returned.
+func getDeviceCodec(device bluez.Device) (string, bool) {
+	var codecs []string
+
+	transports := UI.Bluez.GetTransports(device.Path)
+	for _, transport := range transports {
+		codec := transport.CodecName()
+		if codec == "unknown" {
+			continue
+		}
+
+		var exists bool
+		for _, c := range codecs {
+			if c == codec {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			codecs = append(codecs, codec)
+		}
+	}
+
+	if codecs != nil {
+		sort.Strings(codecs)
+		return strings.Join(codecs, ", "), true
+	}
+
+	if transports != nil ||
+		device.HaveService(bluez.AUDIO_SINK_SVCLASS_ID) ||
+		device.HaveService(bluez.AUDIO_SOURCE_SVCLASS_ID) {
+		return "unknown", true
+	}
+
+	return "", false
+}
+
+// updateDeviceInfo refreshes the device information modal,
+// if it is currently displaying the provided device.
+func updateDeviceInfo(device bluez.Device) {
+	infoModal, ok := ModalExists("info")
+	if !ok || infoModal.Table == nil {
+		return
+	}
+
+	ref, ok := infoModal.Table.GetCell(0, 0).GetReference().(bluez.Device)
+	if !ok || ref.Path != device.Path {
+		return
+	}
+
+	row, _ := infoModal.Table.GetSelection()
+	setDeviceInfo(infoModal.Table, device)
+	infoModal.Table.Select(row, 0)
+}
+
+// formatDeviceStats returns the connection statistics of a device
+// in a human-readable form.
+func formatDeviceStats(stats cmd.DeviceStats) string {
+	lastConnected := "never connected"
+	if !stats.LastConnected.IsZero() {
+		lastConnected = "last connected " + formatElapsed(time.Since(stats.LastConnected)) + " ago"
+	}
+
+	return fmt.Sprintf(
+		"connected %d times, %d failures, %s, connected for %s in total",
+		stats.Connects, stats.Failures, lastConnected, formatElapsed(stats.ConnectedTime),
+	)
+}
+
+// formatStateChange returns the connection state change of a device, with
+// the time since the change. If absolute is set, the time of the change is added.
+func formatStateChange(change cmd.StateChange, absolute bool) string {
+	text := "Disconnected "
+	if change.Connected {
+		text = "Connected "
+	}
+
+	text += formatElapsed(time.Since(change.Time)) + " ago"
+	if absolute {
+		text += " (" + change.Time.Format("2006-01-02 15:04:05") + ")"
+	}
+
+	return text
+}
+
+// formatElapsed returns the duration rounded to its largest unit.
+func formatElapsed(duration time.Duration) string {
+	switch {
+	case duration >= 24*time.Hour:
+		return strconv.Itoa(int(duration/(24*time.Hour))) + "d"
+
+	case duration >= time.Hour:
+		return strconv.Itoa(int(duration/time.Hour)) + "h"
+
+	case duration >= time.Minute:
+		return strconv.Itoa(int(duration/time.Minute)) + "m"
+	}
+
+	return strconv.Itoa(int(duration/time.Second)) + "s"
+}
+
+// getPreferredAdapterDevice returns the device as known by its preferred adapter,
+// if a preferred adapter is configured for it. If the preferred adapter is not
+// available, a warning is shown and the provided device is returned.
+func getPreferredAdapterDevice(device bluez.Device) bluez.Device {
+	preferredAdapter := cmd.GetDeviceProperty(device.Address, "preferred-adapter")
+	if device.Path == "" || preferredAdapter == "" || bluez.GetAdapterID(device.Adapter) == preferredAdapter {
+		return device
+	}
+
+	for _, d := range UI.Bluez.GetAllDevices() {
+		if d.Address == device.Address && bluez.GetAdapterID(d.Adapter) == preferredAdapter {
+			return d
+		}
+	}
+
+	InfoMessage(
+		fmt.Sprintf(
+			"Preferred adapter %s is not available for %s, using %s",
+			preferredAdapter, device.Name, bluez.GetAdapterID(device.Adapter),
+		), false,
+	)
+
+	return device
+}
+
+// getFallbackDevices returns the device as known by each of its fallback adapters,
+// in the configured order. Adapters which are not powered, or on which the device
+// is out of range, are skipped.
+func getFallbackDevices(device bluez.Device) []bluez.Device {
+	var devices []bluez.Device
+
+	fallback := cmd.GetDeviceAdapterFallback(device.Address)
+	if fallback == nil {
+		return nil
+	}
+
+	adapters := make(map[string]bluez.Adapter)
+	for _, adapter := range UI.Bluez.GetAdapters() {
+		adapters[bluez.GetAdapterID(adapter.Path)] = adapter
+	}
+
+	for _, adapterID := range fallback {
+		adapter, ok := adapters[adapterID]
+		if !ok || !adapter.Powered || adapter.Path == device.Adapter {
+			continue
+		}
+
+		for _, d := range UI.Bluez.GetAllDevices() {
+			if d.Address == device.Address && d.Adapter == adapter.Path && !isDeviceAway(d) {
+				devices = append(devices, d)
+				break
+			}
+		}
+	}
+
+	return devices
 }
 
 // getDeviceFromSelection retrieves device information from
@@ -241,6 +1388,22 @@ func getDeviceFromSelection(lock bool) bluez.Device {
 	return device
 }
 
+// formatTransport returns the display name of the device transport.
+func formatTransport(transport string) string {
+	switch transport {
+	case "le":
+		return "LE"
+
+	case "bredr":
+		return "BR/EDR"
+
+	case "dual":
+		return "Dual"
+	}
+
+	return ""
+}
+
 // setDeviceTableInfo writes device information into the
 // specified row of the DeviceTable.
 func setDeviceTableInfo(row int, device bluez.Device) {
@@ -249,6 +1412,9 @@ func setDeviceTableInfo(row int, device bluez.Device) {
 	data := []string{
 		theme.ColorWrap(theme.ThemeDeviceType, device.Type),
 	}
+	if transport := formatTransport(device.Transport()); transport != "" {
+		data = append(data, theme.ColorWrap(theme.ThemeDeviceType, transport))
+	}
 	name := device.Name
 	if name == "" {
 		name = device.Address
@@ -264,26 +1430,43 @@ func setDeviceTableInfo(row int, device bluez.Device) {
 	nameColor := theme.ThemeDevice
 	propColor := theme.ThemeDeviceProperty
 
-	if device.Connected {
+	_, partner, grouped := getDeviceGroup(device)
+
+	if device.Connected || (grouped && partner.Connected) {
 		props += "Connected"
 
 		nameColor = theme.ThemeDeviceConnected
 		propColor = theme.ThemeDevicePropertyConnected
 
 		if device.RSSI < 0 {
-			rssi := strconv.FormatInt(int64(device.RSSI), 10)
-			props += "[" + rssi + "[]"
+			props += "[" + formatRSSI(device.RSSI) + "[]"
+		}
+
+		switch {
+		case grouped:
+			props += getDeviceGroupBattery(device, partner)
+
+		case device.BatteryPercentage >= 0:
+			props += ", Battery " + strconv.Itoa(device.BatteryPercentage) + "%"
 		}
 
-		if device.Percentage > 0 {
-			props += ", Battery " + strconv.Itoa(device.Percentage) + "%"
+		if UI.Bluez.IsMicrophoneActive(device.Path) || (grouped && UI.Bluez.IsMicrophoneActive(partner.Path)) {
+			props += ", Mic Active"
 		}
 
 		props += ", "
 	}
 
+	if UI.Bluez.GetQueuedOperation(device.Path) == "connect" {
+		props += "Queued to connect, "
+	}
+
+	if isDeviceAway(device) {
+		props += theme.ColorWrap(theme.ThemeDevicePropertyAway, "Out of range") + ", "
+	}
+
 	if device.Trusted {
-		props += "Trusted, "
+		props += theme.ColorWrap(theme.ThemeDevicePropertyTrusted, "Trusted") + ", "
 	}
 	if device.Blocked {
 		props += "Blocked, "
@@ -293,6 +1476,11 @@ func setDeviceTableInfo(row int, device bluez.Device) {
 	} else if !device.Bonded && device.Paired {
 		props += "Paired, "
 	}
+	if cmd.IsPropertyEnabled("show-state-changes") {
+		if change, ok := cmd.GetStateChange(device.Address); ok {
+			props += formatStateChange(change, false) + ", "
+		}
+	}
 
 	if props != "" {
 		props = "(" + strings.TrimRight(props, ", ") + ")"
@@ -302,13 +1490,19 @@ func setDeviceTableInfo(row int, device bluez.Device) {
 		propColor = theme.ThemeDevicePropertyDiscovered
 	}
 
+	if deviceError := getDeviceError(device.Path); deviceError != "" {
+		props = theme.ColorWrap(
+			theme.ThemeDevicePropertyError,
+			"Error: "+tview.Escape(deviceError),
+		) + " " + props
+	}
+
 	DeviceTable.SetCell(
 		row, 0, tview.NewTableCell(name).
 			SetExpansion(1).
 			SetReference(device).
 			SetAlign(tview.AlignLeft).
-			SetAttributes(tcell.AttrBold).
-			SetTextColor(theme.GetColor(nameColor)).
+			SetStyle(theme.GetStyle(nameColor, tcell.AttrBold)).
 			SetSelectedStyle(tcell.Style{}.
 				Foreground(theme.GetColor(nameColor)).
 				Background(theme.BackgroundColor(nameColor)),
@@ -318,13 +1512,137 @@ func setDeviceTableInfo(row int, device bluez.Device) {
 		row, 1, tview.NewTableCell(props).
 			SetExpansion(1).
 			SetAlign(tview.AlignRight).
-			SetTextColor(theme.GetColor(propColor)).
+			SetStyle(theme.GetStyle(propColor, tcell.AttrNone)).
 			SetSelectedStyle(tcell.Style{}.
 				Bold(true),
 			),
 	)
 }
 
+// formatRSSI returns the signal strength according to the "rssi-format" option.
+func formatRSSI(rssi int16) string {
+	if cmd.GetProperty("rssi-format") != "bars" {
+		return strconv.FormatInt(int64(rssi), 10)
+	}
+
+	bars := cmd.GetRSSIBars(rssi)
+
+	return strings.Repeat("▮", bars) + strings.Repeat("▯", 4-bars)
+}
+
+// setDeviceError sets the error for the device and displays it in the
+// DeviceTable, until the error is cleared or the error timeout elapses.
+func setDeviceError(devicePath string, err error) {
+	text := runewidth.Truncate(err.Error(), 43, "...")
+
+	deviceErrors.lock.Lock()
+	if timer, ok := deviceErrors.timers[devicePath]; ok {
+		timer.Stop()
+	}
+	deviceErrors.errors[devicePath] = text
+	deviceErrors.timers[devicePath] = time.AfterFunc(deviceErrorTimeout, func() {
+		clearDeviceError(devicePath)
+	})
+	deviceErrors.lock.Unlock()
+
+	refreshDeviceRow(devicePath)
+}
+
+// clearDeviceError clears the error for the device.
+func clearDeviceError(devicePath string) {
+	deviceErrors.lock.Lock()
+	if timer, ok := deviceErrors.timers[devicePath]; ok {
+		timer.Stop()
+	}
+	_, ok := deviceErrors.errors[devicePath]
+	delete(deviceErrors.errors, devicePath)
+	delete(deviceErrors.timers, devicePath)
+	deviceErrors.lock.Unlock()
+
+	if ok {
+		refreshDeviceRow(devicePath)
+	}
+}
+
+// getDeviceError returns the error for the device.
+func getDeviceError(devicePath string) string {
+	deviceErrors.lock.Lock()
+	defer deviceErrors.lock.Unlock()
+
+	return deviceErrors.errors[devicePath]
+}
+
+// refreshDeviceRow redraws the DeviceTable row of the device.
+func refreshDeviceRow(devicePath string) {
+	UI.QueueUpdateDraw(func() {
+		device := UI.Bluez.GetDevice(devicePath)
+		if device.Path == "" {
+			return
+		}
+
+		if primary, _, grouped := getDeviceGroup(device); grouped {
+			device = primary
+		}
+
+		if row, ok := checkDeviceTable(device.Path); ok {
+			setDeviceTableInfo(row, device)
+		}
+	})
+}
+
+// refreshDeviceRows redraws all the rows of the DeviceTable.
+func refreshDeviceRows() {
+	for row := 0; row < DeviceTable.GetRowCount(); row++ {
+		cell := DeviceTable.GetCell(row, 0)
+		if cell == nil {
+			continue
+		}
+
+		device, ok := cell.GetReference().(bluez.Device)
+		if !ok {
+			continue
+		}
+
+		setDeviceTableInfo(row, UI.Bluez.GetDevice(device.Path))
+	}
+}
+
+// getDeviceGroupBattery returns the combined battery levels of a pair of earbuds.
+func getDeviceGroupBattery(device, partner bluez.Device) string {
+	var levels []string
+
+	battery, ok := bluez.DecodeEarbudsBattery(device.ManufacturerData)
+	if !ok {
+		battery, ok = bluez.DecodeEarbudsBattery(partner.ManufacturerData)
+	}
+	if ok {
+		for _, level := range []struct {
+			name  string
+			value int
+		}{
+			{"L", battery.Left},
+			{"R", battery.Right},
+			{"Case", battery.Case},
+		} {
+			if level.value >= 0 {
+				levels = append(levels, level.name+" "+strconv.Itoa(level.value)+"%")
+			}
+		}
+	} else {
+		for _, bud := range []bluez.Device{device, partner} {
+			if bud.BatteryPercentage >= 0 {
+				levels = append(levels, strconv.Itoa(bud.BatteryPercentage)+"%")
+			}
+		}
+	}
+
+	if levels == nil {
+		return ""
+	}
+
+	return ", Battery " + strings.Join(levels, " ")
+}
+
 // deviceEvent handles device-specific events.
 func deviceEvent(signal *dbus.Signal, signalData interface{}) {
 	switch signal.Name {
@@ -334,11 +1652,34 @@ func deviceEvent(signal *dbus.Signal, signalData interface{}) {
 			return
 		}
 
+		cmd.UpdateDeviceConnection(device.Address, device.Connected)
+		cmd.RecordStateChange(device.Address, device.Connected)
+		setDeviceSeen(device)
+		autoConnectDevice(device)
+
 		UI.QueueUpdateDraw(func() {
+			primary, partner, grouped := getDeviceGroup(device)
+			if grouped {
+				if row, ok := checkDeviceTable(partner.Path); ok {
+					DeviceTable.RemoveRow(row)
+				}
+
+				device = primary
+			}
+
 			row, ok := checkDeviceTable(device.Path)
-			if ok {
-				setDeviceTableInfo(row, device)
+			switch {
+			case ok && !matchesDeviceFilters(device):
+				DeviceTable.RemoveRow(row)
+
+			case ok:
+				setDeviceTableInfo(moveDeviceRow(row, device), device)
+
+			case device.Adapter == UI.Bluez.GetCurrentAdapter().Path && matchesDeviceFilters(device):
+				setDeviceGroupInfo(device)
 			}
+
+			updateDeviceInfo(device)
 		})
 
 	case "org.freedesktop.DBus.ObjectManager.InterfacesAdded":
@@ -347,26 +1688,37 @@ func deviceEvent(signal *dbus.Signal, signalData interface{}) {
 			return
 		}
 
-		for devicePath, devices := range deviceMap {
+		for _, devices := range deviceMap {
 			for _, device := range devices {
 				if device.Adapter != UI.Bluez.GetCurrentAdapter().Path {
 					continue
 				}
 
-				UI.QueueUpdateDraw(func() {
-					deviceRow := DeviceTable.GetRowCount()
-
-					row, ok := checkDeviceTable(devicePath)
-					if ok {
-						deviceRow = row
-					}
+				setDeviceSeen(device)
+				autoConnectDevice(device)
 
-					setDeviceTableInfo(deviceRow, device)
+				device := device
+				UI.QueueUpdateDraw(func() {
+					setDeviceGroupInfo(device)
+					updateDeviceInfo(device)
 				})
 			}
 		}
 
 	case "org.freedesktop.DBus.ObjectManager.InterfacesRemoved":
+		if device, ok := signalData.(bluez.Device); ok {
+			if device.Adapter != UI.Bluez.GetCurrentAdapter().Path {
+				return
+			}
+
+			UI.QueueUpdateDraw(func() {
+				setDeviceGroupInfo(device)
+				updateDeviceInfo(device)
+			})
+
+			return
+		}
+
 		devicePath, ok := signalData.(string)
 		if !ok {
 			return
@@ -377,6 +1729,26 @@ func deviceEvent(signal *dbus.Signal, signalData interface{}) {
 			if ok {
 				DeviceTable.RemoveRow(row)
 			}
+
+			for _, device := range UI.Bluez.GetDevices() {
+				if _, ok := checkDeviceTable(device.Path); ok {
+					continue
+				}
+
+				if primary, _, grouped := getDeviceGroup(device); grouped && primary.Path != device.Path {
+					if _, ok := checkDeviceTable(primary.Path); ok {
+						setDeviceGroupInfo(primary)
+					}
+
+					continue
+				}
+
+				if !matchesDeviceFilters(device) {
+					continue
+				}
+
+				setDeviceTableInfo(DeviceTable.GetRowCount(), device)
+			}
 		})
 	}
 }
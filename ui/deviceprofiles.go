@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// deviceProfiles shows a popup with the bluetooth profiles of the device,
+// and marks the profiles which are connected.
+func deviceProfiles() {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return
+	}
+
+	profiles := UI.Bluez.GetDeviceProfiles(device)
+	if profiles == nil {
+		InfoMessage("No profiles found for "+device.Name, false)
+		return
+	}
+
+	setContextMenu(
+		"device",
+		func(profileMenu *tview.Table) {
+			row, _ := profileMenu.GetSelection()
+
+			toggleDeviceProfile(profileMenu, device, row)
+		}, nil,
+		func(profileMenu *tview.Table) (int, int) {
+			var width int
+
+			profileMenu.SetSelectorWrap(true)
+
+			for row, profile := range profiles {
+				var connectedIndicator string
+
+				if profile.Connected {
+					connectedIndicator = string('•')
+				}
+
+				if len(profile.Name) > width {
+					width = len(profile.Name)
+				}
+
+				profileMenu.SetCell(row, 0, tview.NewTableCell(connectedIndicator).
+					SetSelectable(false).
+					SetTextColor(theme.GetColor(theme.ThemeText)),
+				)
+
+				profileMenu.SetCell(row, 1, tview.NewTableCell(profile.Name).
+					SetExpansion(1).
+					SetReference(profile).
+					SetAlign(tview.AlignLeft).
+					SetOnClickedFunc(func(profileMenu *tview.Table, row, column int) {
+						toggleDeviceProfile(profileMenu, device, row)
+					}).
+					SetTextColor(theme.GetColor(theme.ThemeText)).
+					SetSelectedStyle(tcell.Style{}.
+						Foreground(theme.GetColor(theme.ThemeText)).
+						Background(theme.BackgroundColor(theme.ThemeText)),
+					),
+				)
+			}
+
+			return width - 16, 0
+		},
+	)
+}
+
+// toggleDeviceProfile connects or disconnects the selected profile of the device.
+func toggleDeviceProfile(profileMenu *tview.Table, device bluez.Device, row int) {
+	cell := profileMenu.GetCell(row, 1)
+	if cell == nil {
+		return
+	}
+
+	profile, ok := cell.GetReference().(bluez.DeviceProfile)
+	if !ok {
+		return
+	}
+
+	startOperation(
+		func() {
+			if profile.Connected {
+				InfoMessage("Disconnecting "+profile.Name+" from "+device.Name, true)
+				if err := UI.Bluez.DisconnectProfile(device.Path, profile.UUID); err != nil {
+					ErrorMessage(err)
+					return
+				}
+
+				InfoMessage("Disconnected "+profile.Name+" from "+device.Name, false)
+
+				return
+			}
+
+			InfoMessage("Connecting "+profile.Name+" to "+device.Name, true)
+			if err := UI.Bluez.ConnectProfile(device.Path, profile.UUID); err != nil {
+				ErrorMessage(err)
+				return
+			}
+
+			InfoMessage("Connected "+profile.Name+" to "+device.Name, false)
+		},
+		func() {
+			if profile.Connected {
+				return
+			}
+
+			if err := UI.Bluez.DisconnectProfile(device.Path, profile.UUID); err != nil {
+				ErrorMessage(err)
+				return
+			}
+
+			InfoMessage("Cancelled connecting "+profile.Name+" to "+device.Name, false)
+		},
+	)
+}
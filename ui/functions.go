@@ -3,6 +3,8 @@ package ui
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/darkhz/bluetuith/bluez"
 	"github.com/darkhz/bluetuith/cmd"
@@ -21,37 +23,66 @@ const (
 
 var functions = map[FunctionContext]map[cmd.Key]func(set ...string) bool{
 	FunctionClick: {
-		cmd.KeyAdapterTogglePower:        power,
-		cmd.KeyAdapterToggleDiscoverable: discoverable,
-		cmd.KeyAdapterTogglePairable:     pairable,
-		cmd.KeyAdapterToggleScan:         scan,
-		cmd.KeyAdapterChange:             change,
-		cmd.KeyDeviceConnect:             connect,
-		cmd.KeyDevicePair:                pair,
-		cmd.KeyDeviceTrust:               trust,
-		cmd.KeyDeviceBlock:               block,
-		cmd.KeyDeviceSendFiles:           send,
-		cmd.KeyDeviceNetwork:             networkAP,
-		cmd.KeyDeviceAudioProfiles:       profiles,
-		cmd.KeyPlayerShow:                showplayer,
-		cmd.KeyDeviceInfo:                info,
-		cmd.KeyDeviceRemove:              remove,
-		cmd.KeyProgressView:              progress,
-		cmd.KeyPlayerHide:                hideplayer,
-		cmd.KeyQuit:                      quit,
+		cmd.KeyAdapterTogglePower:         power,
+		cmd.KeyAdapterToggleDiscoverable:  discoverable,
+		cmd.KeyAdapterTogglePairable:      pairable,
+		cmd.KeyAdapterToggleScan:          scan,
+		cmd.KeyAdapterToggleAirplane:      airplane,
+		cmd.KeyAdapterToggleFilter:        discoveryFilter,
+		cmd.KeyAdapterToggleTransport:     discoveryTransport,
+		cmd.KeyAdapterDisconnectAll:       disconnectAll,
+		cmd.KeyAdapterRemoveUnpaired:      removeUnpaired,
+		cmd.KeyAdapterDiscoverablePreset1: discoverablePreset(0),
+		cmd.KeyAdapterDiscoverablePreset2: discoverablePreset(1),
+		cmd.KeyAdapterDiscoverablePreset3: discoverablePreset(2),
+		cmd.KeyAdapterChange:              change,
+		cmd.KeyAdapterInfo:                adapterInfo,
+		cmd.KeyConnectionProfiles:         connProfiles,
+		cmd.KeyToggleRSSIFormat:           rssiFormat,
+		cmd.KeyDeviceConnect:              connect,
+		cmd.KeyDevicePair:                 pair,
+		cmd.KeyDeviceTrust:                trust,
+		cmd.KeyDeviceBlock:                block,
+		cmd.KeyDeviceSendFiles:            send,
+		cmd.KeyDeviceBrowseFiles:          browse,
+		cmd.KeyDeviceNetwork:              networkAP,
+		cmd.KeyDeviceTether:               tether,
+		cmd.KeyDeviceAudioProfiles:        profiles,
+		cmd.KeyDeviceProfiles:             btProfiles,
+		cmd.KeyDeviceGatt:                 gatt,
+		cmd.KeyPlayerShow:                 showplayer,
+		cmd.KeyDeviceInfo:                 info,
+		cmd.KeyDeviceServiceRecords:       serviceRecords,
+		cmd.KeyDeviceRemove:               remove,
+		cmd.KeyDeviceRename:               rename,
+		cmd.KeyDeviceEditTags:             editTags,
+		cmd.KeyDeviceFilterTag:            filterTag,
+		cmd.KeyDeviceSearch:               searchDevices,
+		cmd.KeyDeviceFilterClass:          filterClass,
+		cmd.KeyDeviceToggleSort:           toggleSort,
+		cmd.KeyProgressView:               progress,
+		cmd.KeyPlayerHide:                 hideplayer,
+		cmd.KeyQuit:                       quit,
 	},
 	FunctionCreate: {
 		cmd.KeyAdapterTogglePower:        createPower,
 		cmd.KeyAdapterToggleDiscoverable: createDiscoverable,
 		cmd.KeyAdapterTogglePairable:     createPairable,
+		cmd.KeyAdapterToggleAirplane:     createAirplane,
+		cmd.KeyAdapterToggleFilter:       createDiscoveryFilter,
+		cmd.KeyToggleRSSIFormat:          createRSSIFormat,
 		cmd.KeyDeviceConnect:             createConnect,
 		cmd.KeyDeviceTrust:               createTrust,
 		cmd.KeyDeviceBlock:               createBlock,
 	},
 	FunctionVisible: {
 		cmd.KeyDeviceSendFiles:     visibleSend,
+		cmd.KeyDeviceBrowseFiles:   visibleBrowse,
 		cmd.KeyDeviceNetwork:       visibleNetwork,
+		cmd.KeyDeviceTether:        visibleTether,
 		cmd.KeyDeviceAudioProfiles: visibleProfile,
+		cmd.KeyDeviceProfiles:      visibleBtProfile,
+		cmd.KeyDeviceGatt:          visibleGatt,
 		cmd.KeyPlayerShow:          visiblePlayer,
 	},
 }
@@ -167,6 +198,37 @@ func discoverable(set ...string) bool {
 	return true
 }
 
+// discoverablePreset returns a handler that makes the adapter discoverable
+// for the duration of the discoverable preset at the provided index.
+func discoverablePreset(index int) func(set ...string) bool {
+	return func(set ...string) bool {
+		adapterPath := UI.Bluez.GetCurrentAdapter().Path
+		adapterID := bluez.GetAdapterID(adapterPath)
+
+		duration := cmd.GetDiscoverablePreset(index)
+
+		if err := UI.Bluez.SetAdapterProperty(adapterPath, "DiscoverableTimeout", uint32(duration.Seconds())); err != nil {
+			ErrorMessage(err)
+			return false
+		}
+
+		if err := UI.Bluez.SetAdapterProperty(adapterPath, "Discoverable", true); err != nil {
+			ErrorMessage(err)
+			return false
+		}
+
+		if duration == 0 {
+			InfoMessage(adapterID+" is discoverable", false)
+		} else {
+			InfoMessage(adapterID+" is discoverable for "+duration.String(), false)
+		}
+
+		setMenuItemToggle("adapter", cmd.KeyAdapterToggleDiscoverable, true)
+
+		return true
+	}
+}
+
 // pairable checks and toggles the adapter's pairable state.
 func pairable(set ...string) bool {
 	var pairableText string
@@ -215,7 +277,17 @@ func pairable(set ...string) bool {
 
 // scan checks the current adapter's state and starts/stops discovery.
 func scan(set ...string) bool {
-	adapterPath := UI.Bluez.GetCurrentAdapter().Path
+	return scanAdapter(UI.Bluez.GetCurrentAdapter().Path, set...)
+}
+
+// scanAdapter checks the adapter's state and starts/stops discovery on it.
+func scanAdapter(adapterPath string, set ...string) bool {
+	var adapterID string
+
+	current := adapterPath == UI.Bluez.GetCurrentAdapter().Path
+	if !current {
+		adapterID = " on " + bluez.GetAdapterID(adapterPath)
+	}
 
 	props, err := UI.Bluez.GetAdapterProperties(adapterPath)
 	if err != nil {
@@ -239,20 +311,237 @@ func scan(set ...string) bool {
 	}
 
 	if !discover {
+		if _, ok := UI.Bluez.GetDiscoveryFilter(adapterPath); !ok {
+			if filter := cmd.GetDiscoveryFilter(); !filter.IsEmpty() {
+				if err := UI.Bluez.SetDiscoveryFilter(adapterPath, filter); err != nil {
+					ErrorMessage(err)
+					return false
+				}
+			}
+		}
+
 		if err := UI.Bluez.StartDiscovery(adapterPath); err != nil {
 			ErrorMessage(err)
 			return false
 		}
-		InfoMessage("Scanning for devices...", true)
+		InfoMessage("Scanning for devices"+adapterID+"...", current)
 	} else {
 		if err := UI.Bluez.StopDiscovery(adapterPath); err != nil {
 			ErrorMessage(err)
 			return false
 		}
-		InfoMessage("Scanning stopped", false)
+		InfoMessage("Scanning stopped"+adapterID, false)
+	}
+
+	if current {
+		setMenuItemToggle("adapter", cmd.KeyAdapterToggleScan, !discover)
+	}
+
+	return true
+}
+
+// discoveryFilter clears the discovery filter of the current adapter if one is set,
+// or applies the filter from the "discovery-filter" option otherwise.
+func discoveryFilter(set ...string) bool {
+	adapterPath := UI.Bluez.GetCurrentAdapter().Path
+
+	filter, _ := UI.Bluez.GetDiscoveryFilter(adapterPath)
+	if filter.IsEmpty() {
+		filter = cmd.GetDiscoveryFilter()
+		if filter.IsEmpty() {
+			InfoMessage("No discovery filter is configured", false)
+			return false
+		}
+	} else {
+		filter = bluez.DiscoveryFilter{}
+	}
+
+	if err := UI.Bluez.SetDiscoveryFilter(adapterPath, filter); err != nil {
+		ErrorMessage(err)
+		return false
+	}
+
+	if filter.IsEmpty() {
+		InfoMessage("Discovery filter cleared", false)
+	} else {
+		InfoMessage("Discovery filter set ("+filter.String()+")", false)
+	}
+
+	setMenuItemToggle("adapter", cmd.KeyAdapterToggleFilter, !filter.IsEmpty())
+
+	UI.QueueUpdateDraw(func() {
+		updateAdapterStatus(UI.Bluez.GetCurrentAdapter())
+	})
+
+	return true
+}
+
+// discoveryTransport cycles the transport of the discovery filter
+// between auto, LE and BR/EDR, keeping the other filter parameters.
+func discoveryTransport(set ...string) bool {
+	adapterPath := UI.Bluez.GetCurrentAdapter().Path
+
+	filter, _ := UI.Bluez.GetDiscoveryFilter(adapterPath)
+	filter = filter.NextTransport()
+
+	if err := UI.Bluez.SetDiscoveryFilter(adapterPath, filter); err != nil {
+		ErrorMessage(err)
+		return false
+	}
+
+	transport := filter.Transport
+	if transport == "" {
+		transport = "auto"
+	}
+	InfoMessage("Scan transport set to "+transport, false)
+
+	setMenuItemToggle("adapter", cmd.KeyAdapterToggleFilter, !filter.IsEmpty())
+
+	UI.QueueUpdateDraw(func() {
+		updateAdapterStatus(UI.Bluez.GetCurrentAdapter())
+	})
+
+	return true
+}
+
+// disconnectAll disconnects all the connected devices of the current adapter, and
+// reports the devices which could not be disconnected. If the "confirm-on-quit"
+// option is set, confirmation is asked before disconnecting the devices.
+func disconnectAll(set ...string) bool {
+	var connected []bluez.Device
+
+	for _, device := range UI.Bluez.GetDevices() {
+		if device.Connected {
+			connected = append(connected, device)
+		}
+	}
+	if connected == nil {
+		InfoMessage("No devices are connected", false)
+		return false
+	}
+
+	if cmd.IsPropertyEnabled("confirm-on-quit") &&
+		SetInput(fmt.Sprintf("Disconnect %d device(s) (y/n)?", len(connected))) != "y" {
+		return false
+	}
+
+	var failed []string
+
+	for i, device := range connected {
+		InfoMessage(fmt.Sprintf("Disconnecting from %s (%d of %d)", device.Name, i+1, len(connected)), true)
+
+		cmd.SetManualDisconnect(device.Address, true)
+		disconnectNetwork(device)
+
+		if err := UI.Bluez.Disconnect(device.Path); err != nil {
+			setDeviceError(device.Path, err)
+			failed = append(failed, device.Name)
+
+			continue
+		}
+		clearDeviceError(device.Path)
+	}
+
+	if failed != nil {
+		ErrorMessage(fmt.Errorf("Could not disconnect from %s", strings.Join(failed, ", ")))
+		return true
+	}
+
+	InfoMessage(fmt.Sprintf("Disconnected from %d device(s)", len(connected)), false)
+
+	return true
+}
+
+// removeUnpaired removes all the discovered devices of the current adapter which
+// are not paired. Trusted, favorited and connected devices are always preserved.
+func removeUnpaired(set ...string) bool {
+	var unpaired []bluez.Device
+
+	for _, device := range UI.Bluez.GetDevices() {
+		if device.Paired || device.Trusted || device.Connected ||
+			cmd.IsDevicePropertyEnabled(device.Address, "favorite") {
+			continue
+		}
+
+		unpaired = append(unpaired, device)
+	}
+	if unpaired == nil {
+		InfoMessage("No unpaired devices to remove", false)
+		return false
+	}
+
+	if txt := SetInput(fmt.Sprintf("Remove %d unpaired device(s) (y/n)?", len(unpaired))); txt != "y" {
+		return false
+	}
+
+	var failed []string
+
+	for i, device := range unpaired {
+		InfoMessage(fmt.Sprintf("Removing %s (%d of %d)", device.Name, i+1, len(unpaired)), true)
+
+		if err := UI.Bluez.RemoveDevice(device.Path); err != nil {
+			failed = append(failed, device.Name)
+		}
+	}
+
+	if failed != nil {
+		ErrorMessage(fmt.Errorf("Could not remove %s", strings.Join(failed, ", ")))
+		return true
+	}
+
+	InfoMessage(fmt.Sprintf("Removed %d unpaired device(s)", len(unpaired)), false)
+
+	return true
+}
+
+// airplane toggles the airplane mode, which powers off all adapters.
+func airplane(set ...string) bool {
+	enable := !cmd.IsAirplaneModeEnabled()
+
+	if enable {
+		InfoMessage("Enabling airplane mode", true)
+	} else {
+		InfoMessage("Disabling airplane mode", true)
+	}
+
+	err := cmd.SetAirplaneMode(UI.Bluez, enable)
+	if err != nil {
+		ErrorMessage(err)
+	} else if enable {
+		InfoMessage("Airplane mode is on", false)
+	} else {
+		InfoMessage("Airplane mode is off", false)
+	}
+
+	setMenuItemToggle("adapter", cmd.KeyAdapterToggleAirplane, cmd.IsAirplaneModeEnabled())
+
+	UI.QueueUpdateDraw(func() {
+		updateAdapterStatus(UI.Bluez.GetCurrentAdapter())
+	})
+
+	return err == nil
+}
+
+// rssiFormat toggles the signal strength display between dBm and bars.
+func rssiFormat(set ...string) bool {
+	enable := cmd.GetProperty("rssi-format") != "bars"
+
+	format := "dbm"
+	if enable {
+		format = "bars"
 	}
+	cmd.SetRuntimeProperty("rssi-format", format)
+
+	UI.QueueUpdateDraw(refreshDeviceRows)
+
+	return enable
+}
 
-	setMenuItemToggle("adapter", cmd.KeyAdapterToggleScan, !discover)
+// adapterInfo shows information about the current adapter.
+func adapterInfo(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		getAdapterInfo()
+	})
 
 	return true
 }
@@ -310,6 +599,23 @@ func createPower(set ...string) bool {
 	return powered
 }
 
+// createAirplane sets the oncreate handler for the airplane mode submenu option.
+func createAirplane(set ...string) bool {
+	return cmd.IsAirplaneModeEnabled()
+}
+
+// createDiscoveryFilter sets the oncreate handler for the discovery filter submenu option.
+func createDiscoveryFilter(set ...string) bool {
+	filter, _ := UI.Bluez.GetDiscoveryFilter(UI.Bluez.GetCurrentAdapter().Path)
+
+	return !filter.IsEmpty()
+}
+
+// createRSSIFormat sets the oncreate handler for the signal bars submenu option.
+func createRSSIFormat(set ...string) bool {
+	return cmd.GetProperty("rssi-format") == "bars"
+}
+
 // createDiscoverable sets the oncreate handler for the discoverable submenu option
 func createDiscoverable(set ...string) bool {
 	adapterPath := UI.Bluez.GetCurrentAdapter().Path
@@ -385,6 +691,27 @@ func visibleSend(set ...string) bool {
 		device.HaveService(bluez.OBEX_OBJPUSH_SVCLASS_ID)
 }
 
+// visibleBrowse sets the visible handler for the browse files submenu option.
+func visibleBrowse(set ...string) bool {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return false
+	}
+
+	return cmd.IsPropertyEnabled("obex") &&
+		device.HaveService(bluez.OBEX_FILETRANS_SVCLASS_ID)
+}
+
+// visibleTether sets the visible handler for the tethering submenu option.
+func visibleTether(set ...string) bool {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return false
+	}
+
+	return device.HaveService(bluez.NAP_SVCLASS_ID)
+}
+
 // visibleNetwork sets the visible handler for the network submenu option.
 func visibleNetwork(set ...string) bool {
 	device := getDeviceFromSelection(false)
@@ -409,6 +736,26 @@ func visibleProfile(set ...string) bool {
 		device.HaveService(bluez.AUDIO_SINK_SVCLASS_ID)
 }
 
+// visibleBtProfile sets the visible handler for the bluetooth profiles submenu option.
+func visibleBtProfile(set ...string) bool {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return false
+	}
+
+	return device.Paired && device.UUIDs != nil
+}
+
+// visibleGatt sets the visible handler for the GATT characteristics submenu option.
+func visibleGatt(set ...string) bool {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" || !device.Connected {
+		return false
+	}
+
+	return UI.Bluez.HasGattServices(device.Path)
+}
+
 // visiblePlayer sets the visible handler for the media player submenu option.
 func visiblePlayer(set ...string) bool {
 	device := getDeviceFromSelection(false)
@@ -439,20 +786,96 @@ func connect(set ...string) bool {
 		}
 	}
 
+	device = getPreferredAdapterDevice(device)
+
+	devices := []bluez.Device{device}
+	candidates := [][]bluez.Device{devices}
+
+	if _, partner, grouped := getDeviceGroup(device); grouped {
+		devices = append(devices, partner)
+		candidates = [][]bluez.Device{devices}
+		device.Connected = device.Connected || partner.Connected
+	} else {
+		for _, d := range getFallbackDevices(device) {
+			candidates = append(candidates, []bluez.Device{d})
+		}
+	}
+
 	disconnectFunc := func() {
-		if err := UI.Bluez.Disconnect(device.Path); err != nil {
-			ErrorMessage(err)
+		var disconnectErr error
+
+		// Disconnect all the devices of a group before reporting
+		// an error, so that no device is left connected.
+		for _, d := range devices {
+			disconnectNetwork(d)
+
+			if err := UI.Bluez.Disconnect(d.Path); err != nil && disconnectErr == nil {
+				disconnectErr = err
+			}
+		}
+		if disconnectErr != nil {
+			setDeviceError(device.Path, disconnectErr)
+			ErrorMessage(disconnectErr)
 			return
 		}
+		clearDeviceError(device.Path)
+	}
+
+	connectCandidate := func(candidate []bluez.Device) error {
+		resumeDiscovery := pauseDiscovery(candidate[0].Adapter)
+		defer resumeDiscovery()
+
+		for _, d := range candidate {
+			if err := connectDevice(context.Background(), d); err != nil {
+				cmd.AddDeviceConnectFailure(d.Address)
+				return err
+			}
+		}
+
+		return nil
 	}
 
 	connectFunc := func() {
+		var err error
+
 		InfoMessage("Connecting to "+device.Name, true)
-		if err := UI.Bluez.Connect(device.Path); err != nil {
+		for i, candidate := range candidates {
+			if i > 0 {
+				InfoMessage(
+					fmt.Sprintf(
+						"Connecting to %s using %s (fallback %d of %d)",
+						device.Name, bluez.GetAdapterID(candidate[0].Adapter), i, len(candidates)-1,
+					), true,
+				)
+			}
+
+			devices = candidate
+			if err = connectCandidate(candidate); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			setDeviceError(device.Path, err)
 			ErrorMessage(err)
 			return
 		}
-		InfoMessage("Connected to "+device.Name, false)
+		clearDeviceError(device.Path)
+		cmd.SetManualDisconnect(device.Address, false)
+
+		if len(candidates) > 1 {
+			InfoMessage("Connected to "+device.Name+" using "+bluez.GetAdapterID(devices[0].Adapter), false)
+		} else {
+			InfoMessage("Connected to "+device.Name, false)
+		}
+
+		if isAudioSink(device) {
+			cmd.SetLastAudioDevice(device.Address)
+		}
+	}
+
+	if !device.Connected && cmd.GetDeviceConnectDirection(device.Address) == "incoming" {
+		InfoMessage(device.Name+" only accepts incoming connections, and must initiate the connection itself", false)
+		return false
 	}
 
 	if !device.Connected {
@@ -464,6 +887,8 @@ func connect(set ...string) bool {
 			},
 		)
 	} else {
+		cmd.SetManualDisconnect(device.Address, true)
+
 		InfoMessage("Disconnecting from "+device.Name, true)
 		disconnectFunc()
 		InfoMessage("Disconnected from "+device.Name, false)
@@ -474,6 +899,33 @@ func connect(set ...string) bool {
 	return true
 }
 
+// connectDevice connects to the device. If a profile order is specified
+// for the device, each profile is connected in sequence instead. If the
+// context is cancelled, the pending connection is cancelled.
+func connectDevice(ctx context.Context, device bluez.Device) error {
+	profileOrder := cmd.GetDeviceProfileOrder(device.Address)
+	if profileOrder == nil {
+		return UI.Bluez.ConnectContext(ctx, device.Path)
+	}
+
+	for i, profileUUID := range profileOrder {
+		InfoMessage(
+			fmt.Sprintf("Connecting %s to %s (%d/%d)",
+				bluez.ServiceType(profileUUID), device.Name, i+1, len(profileOrder),
+			), true,
+		)
+
+		if err := UI.Bluez.ConnectProfileContext(ctx, device.Path, profileUUID); err != nil {
+			return fmt.Errorf(
+				"Could not connect %s (step %d of %d): %w",
+				bluez.ServiceType(profileUUID), i+1, len(profileOrder), err,
+			)
+		}
+	}
+
+	return nil
+}
+
 // pair retrieves the selected device, and attempts to pair with it.
 func pair(set ...string) bool {
 	device := getDeviceFromSelection(true)
@@ -489,9 +941,11 @@ func pair(set ...string) bool {
 		func() {
 			InfoMessage("Pairing with "+device.Name, true)
 			if err := UI.Bluez.Pair(device.Path); err != nil {
+				setDeviceError(device.Path, err)
 				ErrorMessage(err)
 				return
 			}
+			clearDeviceError(device.Path)
 			InfoMessage("Paired with "+device.Name, false)
 		},
 		func() {
@@ -513,10 +967,12 @@ func trust(set ...string) bool {
 		return false
 	}
 
-	if err := UI.Bluez.SetDeviceProperty(device.Path, "Trusted", !device.Trusted); err != nil {
+	if err := UI.Bluez.SetTrusted(device.Path, !device.Trusted); err != nil {
+		setDeviceError(device.Path, err)
 		ErrorMessage(errors.New("Cannot set trusted property for " + device.Name))
 		return false
 	}
+	clearDeviceError(device.Path)
 
 	setMenuItemToggle("device", cmd.KeyDeviceTrust, !device.Trusted)
 
@@ -530,66 +986,39 @@ func block(set ...string) bool {
 		return false
 	}
 
-	if err := UI.Bluez.SetDeviceProperty(device.Path, "Blocked", !device.Blocked); err != nil {
-		ErrorMessage(errors.New("Cannot set trusted property for " + device.Name))
+	setBlocked := UI.Bluez.BlockDevice
+	if device.Blocked {
+		setBlocked = UI.Bluez.UnblockDevice
+	}
+
+	if err := setBlocked(device.Path); err != nil {
+		setDeviceError(device.Path, err)
+		ErrorMessage(errors.New("Cannot set blocked property for " + device.Name))
 		return false
 	}
+	clearDeviceError(device.Path)
 
 	setMenuItemToggle("device", cmd.KeyDeviceBlock, !device.Blocked)
 
 	return true
 }
 
-// send gets a file list from the file picker and sends all selected files
-// to the target device.
+// send gets a file list from the file picker and adds all selected files
+// to the transfer queue of the target device.
 func send(set ...string) bool {
-	adapter := UI.Bluez.GetCurrentAdapter()
-	if !adapter.Lock.TryAcquire(1) {
-		return false
-	}
-	defer adapter.Lock.Release(1)
-
 	device := getDeviceFromSelection(true)
 	if !device.Paired || !device.Connected {
 		ErrorMessage(errors.New(device.Name + " is not paired and/or connected"))
 		return false
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-
-	startOperation(
-		func() {
-			InfoMessage("Initializing OBEX session..", true)
-
-			sessionPath, err := UI.Obex.CreateSession(ctx, device.Address)
-			if err != nil {
-				ErrorMessage(err)
-				return
-			}
-
-			cancelOperation(false)
-
-			InfoMessage("Created OBEX session", false)
-
-			for _, file := range filePicker() {
-				transferPath, transferProps, err := UI.Obex.SendFile(sessionPath, file)
-				if err != nil {
-					ErrorMessage(err)
-					continue
-				}
-
-				if !StartProgress(transferPath, transferProps) {
-					break
-				}
-			}
+	files := filePicker()
+	if files == nil {
+		return false
+	}
 
-			UI.Obex.RemoveSession(sessionPath)
-		},
-		func() {
-			cancel()
-			InfoMessage("Cancelled OBEX session creation", false)
-		},
-	)
+	QueueTransfers(device.Address, files)
+	InfoMessage(fmt.Sprintf("Queued %d file(s) to send to %s", len(files), device.Name), false)
 
 	return true
 }
@@ -612,6 +1041,31 @@ func profiles(set ...string) bool {
 	return true
 }
 
+// btProfiles launches a popup with the bluetooth profiles of the device.
+func btProfiles(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		deviceProfiles()
+	})
+
+	return true
+}
+
+// gatt shows the GATT characteristics of the device.
+func gatt(set ...string) bool {
+	showGattCharacteristics()
+
+	return true
+}
+
+// connProfiles launches a popup with the connection profiles.
+func connProfiles(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		connectionProfiles()
+	})
+
+	return true
+}
+
 // showplayer starts the media player.
 func showplayer(set ...string) bool {
 	StartMediaPlayer()
@@ -635,6 +1089,190 @@ func info(set ...string) bool {
 	return true
 }
 
+// serviceRecords retrieves the selected device, and shows its service records.
+func serviceRecords(set ...string) bool {
+	device := getDeviceFromSelection(true)
+	if device.Path == "" {
+		return false
+	}
+
+	InfoMessage("Querying service records of "+device.Name, true)
+
+	records, err := bluez.GetServiceRecords(device.Address)
+
+	UI.QueueUpdateDraw(func() {
+		getServiceRecords(device, records, err)
+	})
+
+	if err != nil {
+		InfoMessage("Service records are not available, showing advertised services", false)
+	} else {
+		InfoMessage("Queried service records of "+device.Name, false)
+	}
+
+	return true
+}
+
+// tether toggles the PAN network connection with the device.
+func tether(set ...string) bool {
+	device := getDeviceFromSelection(true)
+	if device.Path == "" {
+		return false
+	}
+
+	tetherDevice(device)
+
+	return true
+}
+
+// browse browses the filesystem of the device.
+func browse(set ...string) bool {
+	device := getDeviceFromSelection(true)
+	if device.Path == "" {
+		return false
+	}
+
+	browseFiles(device)
+
+	return true
+}
+
+// rename retrieves the selected device, and sets its alias.
+func rename(set ...string) bool {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return false
+	}
+
+	input := SetInput("Rename "+device.Alias+" (- to reset to "+device.Name+"):", struct{}{})
+	if input == "" {
+		return false
+	}
+
+	alias := strings.TrimSpace(input)
+	if alias == "-" {
+		alias = ""
+	}
+
+	if err := UI.Bluez.SetAlias(device.Path, alias); err != nil {
+		ErrorMessage(err)
+		return false
+	}
+
+	if alias == "" {
+		InfoMessage("Reset the alias of "+device.Name, false)
+	} else {
+		InfoMessage("Renamed "+device.Name+" to "+alias, false)
+	}
+
+	return true
+}
+
+// editTags retrieves the selected device, and sets its tags.
+func editTags(set ...string) bool {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return false
+	}
+
+	label := "Tags for " + device.Name + " (comma-separated, - to clear"
+	if tags := cmd.GetDeviceTags(device.Address); tags != nil {
+		label += ", currently " + strings.Join(tags, ", ")
+	}
+	label += "):"
+
+	input := SetInput(label, struct{}{})
+	if input == "" {
+		return false
+	}
+
+	var tags []string
+	if input != "-" {
+		for _, tag := range strings.Split(input, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	if err := cmd.SetDeviceTags(device.Address, tags); err != nil {
+		ErrorMessage(err)
+		return false
+	}
+
+	if tags == nil {
+		InfoMessage("Removed tags from "+device.Name, false)
+	} else {
+		InfoMessage("Tagged "+device.Name+" with "+strings.Join(tags, ", "), false)
+	}
+
+	UI.QueueUpdateDraw(func() {
+		listDevices()
+	})
+
+	return true
+}
+
+// filterClass shows a popup to select the device class filter.
+func filterClass(set ...string) bool {
+	UI.QueueUpdateDraw(func() {
+		classFilterSelect()
+	})
+
+	return true
+}
+
+// filterTag sets the tag to filter the device list by.
+func filterTag(set ...string) bool {
+	input := SetInput("Filter devices by tag (empty to clear):", struct{}{})
+
+	tag := strings.TrimSpace(input)
+	if tag != "" {
+		if err := cmd.ValidateTag(tag); err != nil {
+			ErrorMessage(err)
+			return false
+		}
+	}
+
+	setTagFilter(tag)
+
+	if tag == "" {
+		InfoMessage("Tag filter cleared", false)
+	} else {
+		InfoMessage("Showing devices tagged "+tag, false)
+	}
+
+	UI.QueueUpdateDraw(func() {
+		listDevices()
+	})
+
+	return true
+}
+
+// searchDevices filters the device list incrementally
+// by the name, alias or address of the devices.
+func searchDevices(set ...string) bool {
+	startDeviceSearch()
+
+	return true
+}
+
+// toggleSort toggles the sort order of the device list between
+// the device names and the signal strengths.
+func toggleSort(set ...string) bool {
+	if toggleDeviceSort() {
+		InfoMessage("Sorting devices by signal strength", false)
+	} else {
+		InfoMessage("Sorting devices by name", false)
+	}
+
+	UI.QueueUpdateDraw(func() {
+		listDevices()
+	})
+
+	return true
+}
+
 // remove retrieves the selected device, and removes it from the adapter.
 func remove(set ...string) bool {
 	device := getDeviceFromSelection(true)
@@ -642,7 +1280,30 @@ func remove(set ...string) bool {
 		return false
 	}
 
-	if txt := SetInput("Remove " + device.Name + " (y/n)?"); txt != "y" {
+	var protected []string
+	if device.Trusted {
+		protected = append(protected, "trusted")
+	}
+	if cmd.IsDevicePropertyEnabled(device.Address, "favorite") {
+		protected = append(protected, "favorited")
+	}
+
+	if protected != nil && !cmd.IsPropertyEnabled("force") {
+		state := strings.Join(protected, " and ")
+
+		if cmd.GetProperty("remove-protection") == "block" {
+			ErrorMessage(errors.New(device.Name + " is " + state + ", start with '--force' to remove it"))
+			return false
+		}
+
+		if txt := SetInput(device.Name + " is " + state + ". Remove it (y/n)?"); txt != "y" {
+			return false
+		}
+
+		if txt := SetInput("Confirm removal of " + state + " device " + device.Name + " (y/n)?"); txt != "y" {
+			return false
+		}
+	} else if txt := SetInput("Remove " + device.Name + " (y/n)?"); txt != "y" {
 		return false
 	}
 
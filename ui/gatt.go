@@ -0,0 +1,310 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// GattView describes the view of the GATT services
+// and characteristics of a device.
+type GattView struct {
+	modal *Modal
+
+	rows       map[string]int
+	subscribed map[string]bool
+
+	stop      chan struct{}
+	closeOnce sync.Once
+	lock      sync.Mutex
+}
+
+// showGattCharacteristics shows the GATT services and characteristics of the
+// selected device, and updates the values of the characteristics as they change.
+func showGattCharacteristics() {
+	device := getDeviceFromSelection(false)
+	if device.Path == "" {
+		return
+	}
+
+	InfoMessage("Getting GATT services of "+device.Name, true)
+
+	services, err := UI.Bluez.GetGattServices(device.Path)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+	if services == nil {
+		InfoMessage("No GATT services found for "+device.Name, false)
+		return
+	}
+
+	InfoMessage("Showing GATT services of "+device.Name, false)
+
+	view := &GattView{
+		rows:       make(map[string]int),
+		subscribed: make(map[string]bool),
+		stop:       make(chan struct{}),
+	}
+
+	UI.QueueUpdateDraw(func() {
+		view.modal = NewModal("gatt", "GATT Characteristics ("+device.Name+")", nil, 40, 100)
+		view.modal.Table.SetInputCapture(view.keyEvents)
+		view.modal.Table.SetSelectionChangedFunc(func(row, col int) {
+			_, _, _, height := view.modal.Table.GetRect()
+			view.modal.Table.SetOffset(row-((height-1)/2), 0)
+		})
+		view.modal.exitFunc = view.close
+
+		view.setServices(services)
+
+		view.modal.Height = view.modal.Table.GetRowCount() + 4
+		if view.modal.Height > 60 {
+			view.modal.Height = 60
+		}
+
+		view.modal.Show()
+	})
+
+	go view.watchValues()
+}
+
+// keyEvents handles the key events of the GATT view.
+func (g *GattView) keyEvents(event *tcell.EventKey) *tcell.EventKey {
+	switch cmd.KeyOperation(event, cmd.KeyContextGatt) {
+	case cmd.KeyGattRead:
+		if characteristic, ok := g.selected(); ok {
+			go g.read(characteristic)
+		}
+
+	case cmd.KeyGattWrite:
+		if characteristic, ok := g.selected(); ok {
+			go g.write(characteristic)
+		}
+
+	case cmd.KeyGattToggleNotify:
+		if characteristic, ok := g.selected(); ok {
+			go g.toggleNotify(characteristic)
+		}
+
+	case cmd.KeyClose:
+		g.modal.Exit(false)
+	}
+
+	return ignoreDefaultEvent(event)
+}
+
+// setServices lists the GATT services and their characteristics.
+func (g *GattView) setServices(services []bluez.GattService) {
+	table := g.modal.Table
+
+	for _, service := range services {
+		row := table.GetRowCount()
+
+		table.SetCell(row, 0, tview.NewTableCell("[::b]"+tview.Escape(bluez.ServiceName(service.UUID))).
+			SetExpansion(1).
+			SetSelectable(false).
+			SetAlign(tview.AlignLeft).
+			SetTextColor(theme.GetColor(theme.ThemeText)),
+		)
+
+		for _, characteristic := range service.Characteristics {
+			row = table.GetRowCount()
+			g.rows[characteristic.Path] = row
+
+			table.SetCell(row, 0, tview.NewTableCell("  "+tview.Escape(bluez.ServiceName(characteristic.UUID))).
+				SetExpansion(1).
+				SetReference(characteristic).
+				SetAlign(tview.AlignLeft).
+				SetTextColor(theme.GetColor(theme.ThemeText)).
+				SetSelectedStyle(tcell.Style{}.
+					Bold(true),
+				),
+			)
+
+			g.setCharacteristic(row, characteristic)
+		}
+	}
+}
+
+// setCharacteristic shows the flags and the value of the characteristic in the row.
+func (g *GattView) setCharacteristic(row int, characteristic bluez.GattCharacteristic) {
+	flags := strings.Join(characteristic.Flags, ", ")
+	if characteristic.Notifying {
+		flags += " (notifying)"
+	}
+
+	g.modal.Table.GetCell(row, 0).SetReference(characteristic)
+
+	g.modal.Table.SetCell(row, 1, tview.NewTableCell(flags).
+		SetSelectable(false).
+		SetAlign(tview.AlignLeft).
+		SetTextColor(theme.GetColor(theme.ThemeText)),
+	)
+
+	g.modal.Table.SetCell(row, 2, tview.NewTableCell(bluez.FormatHexValue(characteristic.Value)).
+		SetSelectable(false).
+		SetAlign(tview.AlignRight).
+		SetTextColor(theme.GetColor(theme.ThemeText)),
+	)
+}
+
+// updateCharacteristic updates the characteristic with the changed properties.
+func (g *GattView) updateCharacteristic(change bluez.GattCharacteristicChange) {
+	row, ok := g.rows[change.Path]
+	if !ok {
+		return
+	}
+
+	characteristic, ok := g.modal.Table.GetCell(row, 0).GetReference().(bluez.GattCharacteristic)
+	if !ok {
+		return
+	}
+
+	if change.ValueChanged {
+		characteristic.Value = change.Value
+	}
+	if change.NotifyingChanged {
+		characteristic.Notifying = change.Notifying
+	}
+
+	g.setCharacteristic(row, characteristic)
+}
+
+// selected returns the selected characteristic.
+func (g *GattView) selected() (bluez.GattCharacteristic, bool) {
+	row, _ := g.modal.Table.GetSelection()
+
+	characteristic, ok := g.modal.Table.GetCell(row, 0).GetReference().(bluez.GattCharacteristic)
+
+	return characteristic, ok
+}
+
+// read reads the value of the characteristic.
+func (g *GattView) read(characteristic bluez.GattCharacteristic) {
+	if !characteristic.HasFlag("read") {
+		InfoMessage("The characteristic cannot be read", false)
+		return
+	}
+
+	value, err := UI.Bluez.ReadCharacteristic(characteristic.Path)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	UI.QueueUpdateDraw(func() {
+		g.updateCharacteristic(bluez.GattCharacteristicChange{
+			Path:         characteristic.Path,
+			Value:        value,
+			ValueChanged: true,
+		})
+	})
+}
+
+// write writes a hexadecimal value to the characteristic.
+func (g *GattView) write(characteristic bluez.GattCharacteristic) {
+	if !characteristic.CanWrite() {
+		InfoMessage("The characteristic cannot be written to", false)
+		return
+	}
+
+	input := SetInput("Value (hex):", struct{}{})
+	if input == "" {
+		return
+	}
+
+	value, err := bluez.ParseHexValue(input)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	if err := UI.Bluez.WriteCharacteristic(characteristic.Path, value); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Wrote "+bluez.FormatHexValue(value)+" to "+bluez.ServiceName(characteristic.UUID), false)
+
+	if characteristic.HasFlag("read") {
+		g.read(characteristic)
+	}
+}
+
+// toggleNotify subscribes to or unsubscribes from the value changes of the characteristic.
+func (g *GattView) toggleNotify(characteristic bluez.GattCharacteristic) {
+	if !characteristic.CanNotify() {
+		InfoMessage("The characteristic does not support notifications", false)
+		return
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if characteristic.Notifying {
+		if err := UI.Bluez.StopNotify(characteristic.Path); err != nil {
+			ErrorMessage(err)
+			return
+		}
+
+		delete(g.subscribed, characteristic.Path)
+		InfoMessage("Stopped notifications from "+bluez.ServiceName(characteristic.UUID), false)
+
+		return
+	}
+
+	if err := UI.Bluez.StartNotify(characteristic.Path); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	g.subscribed[characteristic.Path] = true
+	InfoMessage("Started notifications from "+bluez.ServiceName(characteristic.UUID), false)
+}
+
+// watchValues updates the characteristics when their values change.
+func (g *GattView) watchValues() {
+	signals := UI.Bluez.WatchSignal()
+	defer UI.Bluez.Conn().RemoveSignal(signals)
+
+	for {
+		select {
+		case <-g.stop:
+			return
+
+		case signal, ok := <-signals:
+			if !ok {
+				return
+			}
+
+			change, ok := UI.Bluez.ParseSignalData(signal).(bluez.GattCharacteristicChange)
+			if !ok {
+				continue
+			}
+
+			UI.QueueUpdateDraw(func() {
+				g.updateCharacteristic(change)
+			})
+		}
+	}
+}
+
+// close stops the notifications which were started from the view.
+func (g *GattView) close() {
+	g.closeOnce.Do(func() {
+		close(g.stop)
+
+		g.lock.Lock()
+		defer g.lock.Unlock()
+
+		for path := range g.subscribed {
+			UI.Bluez.StopNotify(path)
+		}
+	})
+}
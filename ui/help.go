@@ -32,18 +32,39 @@ var (
 			{"Navigation", "Navigate between devices/options", []cmd.Key{cmd.KeyNavigateUp, cmd.KeyNavigateDown}, true},
 			{"Power", "Toggle adapter power state", []cmd.Key{cmd.KeyAdapterTogglePower}, true},
 			{"Discoverable", "Toggle discoverable state", []cmd.Key{cmd.KeyAdapterToggleDiscoverable}, false},
+			{"Discoverable Presets", "Set discoverable state with a preset duration", []cmd.Key{cmd.KeyAdapterDiscoverablePreset1, cmd.KeyAdapterDiscoverablePreset2, cmd.KeyAdapterDiscoverablePreset3}, false},
 			{"Pairable", "Toggle pairable state", []cmd.Key{cmd.KeyAdapterTogglePairable}, false},
 			{"Scan", "Toggle scan (discovery state)", []cmd.Key{cmd.KeyAdapterToggleScan}, true},
+			{"Discovery Filter", "Clear or reapply the discovery filter", []cmd.Key{cmd.KeyAdapterToggleFilter}, false},
+			{"Scan Transport", "Cycle the scan transport between auto, LE and BR/EDR", []cmd.Key{cmd.KeyAdapterToggleTransport}, false},
+			{"Airplane Mode", "Toggle power on all adapters", []cmd.Key{cmd.KeyAdapterToggleAirplane}, false},
+			{"Signal Bars", "Toggle signal strength display between dBm and bars", []cmd.Key{cmd.KeyToggleRSSIFormat}, false},
 			{"Adapter", "Change adapter", []cmd.Key{cmd.KeyAdapterChange}, true},
+			{"Adapter Info", "Show adapter information", []cmd.Key{cmd.KeyAdapterInfo}, false},
+			{"Adapter Scan", "Toggle scan on the highlighted adapter in the adapter menu", []cmd.Key{cmd.KeyAdapterToggleScan}, false},
 			{"Send", "Send files", []cmd.Key{cmd.KeyDeviceSendFiles}, true},
+			{"Browse Files", "Browse and download files from the selected device", []cmd.Key{cmd.KeyDeviceBrowseFiles}, false},
 			{"Network", "Connect to network", []cmd.Key{cmd.KeyDeviceNetwork}, false},
+			{"Tethering", "Toggle the PAN network connection with the selected device", []cmd.Key{cmd.KeyDeviceTether}, false},
 			{"Progress", "Progress view", []cmd.Key{cmd.KeyProgressView}, false},
 			{"Player", "Show/Hide player", []cmd.Key{cmd.KeyPlayerShow, cmd.KeyPlayerHide}, false},
 			{"Device Info", "Show device information", []cmd.Key{cmd.KeyDeviceInfo}, false},
+			{"Service Records", "Show device service records", []cmd.Key{cmd.KeyDeviceServiceRecords}, false},
 			{"Connect", "Toggle connection with selected device", []cmd.Key{cmd.KeyDeviceConnect}, true},
+			{"Disconnect All", "Disconnect all the connected devices of the adapter", []cmd.Key{cmd.KeyAdapterDisconnectAll}, false},
+			{"Connection Profiles", "Connect to the devices of a connection profile", []cmd.Key{cmd.KeyConnectionProfiles}, false},
+			{"Bluetooth Profiles", "Connect or disconnect a single profile of the selected device", []cmd.Key{cmd.KeyDeviceProfiles}, false},
+			{"GATT", "Show the GATT characteristics of the selected device", []cmd.Key{cmd.KeyDeviceGatt}, false},
 			{"Pair", "Toggle pair with selected device", []cmd.Key{cmd.KeyDevicePair}, true},
 			{"Trust", "Toggle trust with selected device", []cmd.Key{cmd.KeyDeviceTrust}, false},
 			{"Remove", "Remove device from adapter", []cmd.Key{cmd.KeyDeviceRemove}, false},
+			{"Remove Unpaired", "Remove all unpaired devices from adapter", []cmd.Key{cmd.KeyAdapterRemoveUnpaired}, false},
+			{"Rename", "Set the alias of the selected device", []cmd.Key{cmd.KeyDeviceRename}, false},
+			{"Tags", "Edit the tags of the selected device", []cmd.Key{cmd.KeyDeviceEditTags}, false},
+			{"Search", "Filter the devices by name, alias or address as you type, Escape clears the filter", []cmd.Key{cmd.KeyDeviceSearch}, true},
+			{"Tag Filter", "Only show devices with a tag", []cmd.Key{cmd.KeyDeviceFilterTag}, false},
+			{"Class Filter", "Only show and scan for devices of a class (audio, peripheral, phone, computer)", []cmd.Key{cmd.KeyDeviceFilterClass}, false},
+			{"Toggle Sort", "Sort the devices by name or by signal strength", []cmd.Key{cmd.KeyDeviceToggleSort}, false},
 			{"Cancel", "Cancel operation", []cmd.Key{cmd.KeyCancel}, false},
 			{"Help", "Show help", []cmd.Key{cmd.KeyHelp}, true},
 			{"Quit", "Quit", []cmd.Key{cmd.KeyQuit}, false},
@@ -64,8 +85,19 @@ var (
 			{"Suspend", "Suspend transfer", []cmd.Key{cmd.KeyProgressTransferSuspend}, true},
 			{"Resume", "Resume transfer", []cmd.Key{cmd.KeyProgressTransferResume}, true},
 			{"Cancel", "Cancel transfer", []cmd.Key{cmd.KeyProgressTransferCancel}, true},
+			{"Retry", "Retry failed or cancelled transfer", []cmd.Key{cmd.KeyProgressTransferRetry}, true},
+			{"Move", "Move queued transfer up/down", []cmd.Key{cmd.KeyProgressTransferMoveUp, cmd.KeyProgressTransferMoveDown}, false},
+			{"Remove", "Remove queued transfer", []cmd.Key{cmd.KeyProgressTransferRemove}, false},
+			{"Preview", "Preview received file", []cmd.Key{cmd.KeyProgressTransferPreview}, true},
 			{"Exit", "Exit", []cmd.Key{cmd.KeyClose}, true},
 		},
+		"GATT Characteristics": {
+			{"Navigation", "Navigate between characteristics", []cmd.Key{cmd.KeyNavigateUp, cmd.KeyNavigateDown}, false},
+			{"Read", "Read the value of the characteristic", []cmd.Key{cmd.KeyGattRead}, false},
+			{"Write", "Write a hexadecimal value to the characteristic", []cmd.Key{cmd.KeyGattWrite}, false},
+			{"Notify", "Toggle notifications from the characteristic", []cmd.Key{cmd.KeyGattToggleNotify}, false},
+			{"Exit", "Exit", []cmd.Key{cmd.KeyClose}, false},
+		},
 		"Media Player": {
 			{"Play/Pause", "Toggle play/pause", []cmd.Key{cmd.KeyNavigateUp, cmd.KeyNavigateDown}, false},
 			{"Next", "Next", []cmd.Key{cmd.KeyPlayerNext}, false},
@@ -73,6 +105,7 @@ var (
 			{"Rewind", "Rewind", []cmd.Key{cmd.KeyPlayerSeekBackward}, false},
 			{"Forward", "Fast forward", []cmd.Key{cmd.KeyPlayerSeekForward}, false},
 			{"Stop", "Stop", []cmd.Key{cmd.KeyPlayerStop}, false},
+			{"Volume", "Raise/lower volume", []cmd.Key{cmd.KeyPlayerVolumeUp, cmd.KeyPlayerVolumeDown}, false},
 		},
 	}
 )
@@ -132,7 +165,7 @@ func showStatusHelp(page string) {
 			case cmd.KeyFilebrowserSelect, cmd.KeyFilebrowserInvertSelection, cmd.KeyFilebrowserSelectAll:
 				group = "Select"
 
-			case cmd.KeyProgressTransferSuspend, cmd.KeyProgressTransferResume, cmd.KeyProgressTransferCancel:
+			case cmd.KeyProgressTransferSuspend, cmd.KeyProgressTransferResume, cmd.KeyProgressTransferCancel, cmd.KeyProgressTransferRetry:
 				group = "Transfer"
 
 			case cmd.KeyDeviceConnect, cmd.KeyDevicePair, cmd.KeyAdapterToggleScan, cmd.KeyAdapterTogglePower:
@@ -0,0 +1,329 @@
+package ui
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+)
+
+// ipcResponse describes the response to an IPC command. Devices is
+// only set for the "list-devices" command, even if no devices are found.
+type ipcResponse struct {
+	OK      bool        `json:"ok"`
+	Error   string      `json:"error,omitempty"`
+	Devices interface{} `json:"devices,omitempty"`
+}
+
+// ipcDevice describes a device in the response to the "list-devices" IPC command.
+type ipcDevice struct {
+	Name      string `json:"name"`
+	Alias     string `json:"alias"`
+	Address   string `json:"address"`
+	Adapter   string `json:"adapter"`
+	Paired    bool   `json:"paired"`
+	Connected bool   `json:"connected"`
+	Trusted   bool   `json:"trusted"`
+	Blocked   bool   `json:"blocked"`
+	RSSI      int16  `json:"rssi,omitempty"`
+}
+
+// ipcCommands lists the IPC commands with their usage.
+var ipcCommands = []string{
+	"connect <address>",
+	"disconnect <address>",
+	"scan <on|off>",
+	"power <on|off>",
+	"discoverable <seconds>",
+	"list-devices",
+}
+
+// ipcLock serialises the IPC commands from all connections.
+var ipcLock sync.Mutex
+
+// ipcDiscoverable holds the discoverable window started by the
+// "discoverable" IPC command, and the timer which ends it.
+var ipcDiscoverable struct {
+	window cmd.DiscoverableWindow
+	timer  *time.Timer
+
+	lock sync.Mutex
+}
+
+var ipcListener net.Listener
+
+// startIPC listens for commands on the socket from the "ipc-socket" option.
+func startIPC() {
+	socketPath := cmd.GetProperty("ipc-socket")
+	if socketPath == "" {
+		return
+	}
+
+	// Create the socket with owner-only permissions, so that it
+	// is never accessible to other users, even briefly.
+	mask := syscall.Umask(0177)
+	listener, err := net.Listen("unix", socketPath)
+	syscall.Umask(mask)
+
+	if err != nil {
+		ErrorMessage(fmt.Errorf("Could not listen on the IPC socket: %w", err))
+		return
+	}
+
+	ipcListener = listener
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go handleIPCConnection(conn)
+		}
+	}()
+}
+
+// stopIPC stops listening for commands, removes the socket, and
+// ends the discoverable window if it is active.
+func stopIPC() {
+	if ipcListener != nil {
+		ipcListener.Close()
+	}
+
+	ipcDiscoverable.lock.Lock()
+	defer ipcDiscoverable.lock.Unlock()
+
+	stopIPCDiscoverable()
+}
+
+// handleIPCConnection reads commands from the connection, one per line,
+// and replies to each command with a JSON response. The commands are run
+// one at a time on the connection's goroutine, since they make blocking
+// D-Bus calls, and the UI is only updated with UI.QueueUpdateDraw.
+// If the "control-socket-token" option is set, each command must start
+// with the token, otherwise the command is rejected.
+func handleIPCConnection(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+
+	token := cmd.GetProperty("control-socket-token")
+
+	for scanner.Scan() {
+		args := strings.Fields(scanner.Text())
+		if args == nil {
+			continue
+		}
+
+		var response ipcResponse
+
+		switch {
+		case token != "" && subtle.ConstantTimeCompare([]byte(args[0]), []byte(token)) != 1:
+			response = ipcError("Invalid or missing token")
+
+		case token != "" && len(args) == 1:
+			response = ipcError("No command was provided after the token")
+
+		default:
+			if token != "" {
+				args = args[1:]
+			}
+
+			ipcLock.Lock()
+			response = ipcCommand(args)
+			ipcLock.Unlock()
+		}
+
+		if err := encoder.Encode(response); err != nil {
+			return
+		}
+	}
+}
+
+// ipcCommand runs the IPC command, and returns its response.
+func ipcCommand(args []string) ipcResponse {
+	switch args[0] {
+	case "connect", "disconnect":
+		if len(args) != 2 {
+			return ipcError("Usage: %s <address>", args[0])
+		}
+
+		return ipcConnect(args[0], args[1])
+
+	case "scan":
+		return ipcToggle(args, scan, "Discovering")
+
+	case "power":
+		return ipcToggle(args, power, "Powered")
+
+	case "discoverable":
+		if len(args) != 2 {
+			return ipcError("Usage: discoverable <seconds>")
+		}
+
+		return ipcDiscoverableWindow(args[1])
+
+	case "list-devices":
+		return ipcListDevices()
+	}
+
+	return ipcError("Unknown command '%s'. Valid commands are '%s'", args[0], strings.Join(ipcCommands, "', '"))
+}
+
+// ipcConnect connects to or disconnects from the device with the provided address,
+// on the current adapter. Connections are started in the background.
+func ipcConnect(command, address string) ipcResponse {
+	var device bluez.Device
+
+	connectDevice := command == "connect"
+
+	for _, d := range UI.Bluez.GetDevices() {
+		if strings.EqualFold(d.Address, address) {
+			device = d
+			break
+		}
+	}
+
+	switch {
+	case device.Path == "":
+		return ipcError("Device %s was not found on the current adapter", address)
+
+	case connectDevice && device.Connected:
+		return ipcError("Device %s is already connected", address)
+
+	case !connectDevice && !device.Connected:
+		return ipcError("Device %s is not connected", address)
+	}
+
+	if !connect(device.Address) {
+		return ipcError("Could not %s device %s", command, address)
+	}
+
+	return ipcResponse{OK: true}
+}
+
+// ipcToggle sets the state of the adapter property using the provided toggle function,
+// and checks whether the state of the property was changed.
+func ipcToggle(args []string, toggle func(set ...string) bool, property string) ipcResponse {
+	if len(args) != 2 || (args[1] != "on" && args[1] != "off") {
+		return ipcError("Usage: %s <on|off>", args[0])
+	}
+
+	state := args[1] == "on"
+	if state {
+		toggle("yes")
+	} else {
+		toggle("no")
+	}
+
+	props, err := UI.Bluez.GetAdapterProperties(UI.Bluez.GetCurrentAdapter().Path)
+	if err != nil {
+		return ipcError("%s", err.Error())
+	}
+
+	if current, _ := props[property].Value().(bool); current != state {
+		return ipcError("Could not turn %s %s", args[0], args[1])
+	}
+
+	return ipcResponse{OK: true}
+}
+
+// ipcDiscoverableWindow makes the current adapter discoverable and pairable for
+// the provided number of seconds. If a window is already active, it is ended
+// before the new window is started, so that the states of the adapter from
+// before the first window are restored.
+func ipcDiscoverableWindow(duration string) ipcResponse {
+	seconds, err := strconv.ParseUint(duration, 10, 32)
+	if err != nil || seconds == 0 {
+		return ipcError("The duration must be a number of seconds greater than 0")
+	}
+
+	ipcDiscoverable.lock.Lock()
+	defer ipcDiscoverable.lock.Unlock()
+
+	stopIPCDiscoverable()
+
+	window, err := cmd.StartDiscoverableWindow(
+		UI.Bluez, UI.Bluez.GetCurrentAdapter().Path,
+		time.Duration(seconds)*time.Second,
+	)
+	if err != nil {
+		return ipcError("Could not make the adapter discoverable: %s", err.Error())
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Duration(seconds)*time.Second, func() {
+		ipcDiscoverable.lock.Lock()
+		defer ipcDiscoverable.lock.Unlock()
+
+		if ipcDiscoverable.timer == timer {
+			stopIPCDiscoverable()
+		}
+	})
+
+	ipcDiscoverable.window = window
+	ipcDiscoverable.timer = timer
+
+	return ipcResponse{OK: true}
+}
+
+// stopIPCDiscoverable ends the discoverable window, if it is active.
+// The lock of the window must be held by the caller.
+func stopIPCDiscoverable() {
+	if ipcDiscoverable.timer == nil {
+		return
+	}
+
+	ipcDiscoverable.timer.Stop()
+	ipcDiscoverable.timer = nil
+
+	if err := ipcDiscoverable.window.Stop(UI.Bluez); err != nil {
+		ErrorMessage(fmt.Errorf("Could not restore the adapter states: %w", err))
+	}
+}
+
+// ipcListDevices returns the devices of all adapters, sorted by their adapters and addresses.
+func ipcListDevices() ipcResponse {
+	devices := []ipcDevice{}
+
+	for _, device := range UI.Bluez.GetAllDevices() {
+		devices = append(devices, ipcDevice{
+			Name:      device.Name,
+			Alias:     device.Alias,
+			Address:   device.Address,
+			Adapter:   bluez.GetAdapterID(device.Adapter),
+			Paired:    device.Paired,
+			Connected: device.Connected,
+			Trusted:   device.Trusted,
+			Blocked:   device.Blocked,
+			RSSI:      device.RSSI,
+		})
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].Adapter != devices[j].Adapter {
+			return devices[i].Adapter < devices[j].Adapter
+		}
+
+		return devices[i].Address < devices[j].Address
+	})
+
+	return ipcResponse{OK: true, Devices: devices}
+}
+
+// ipcError returns an IPC response with the provided error.
+func ipcError(format string, v ...interface{}) ipcResponse {
+	return ipcResponse{Error: fmt.Sprintf(format, v...)}
+}
@@ -55,6 +55,18 @@ var (
 				OnClick:  true,
 				OnCreate: true,
 			},
+			{
+				Key:     cmd.KeyAdapterDiscoverablePreset1,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyAdapterDiscoverablePreset2,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyAdapterDiscoverablePreset3,
+				OnClick: true,
+			},
 			{
 				Key:      cmd.KeyAdapterTogglePairable,
 				Enabled:  "On",
@@ -67,10 +79,51 @@ var (
 				Disabled: "Stop Scan",
 				OnClick:  true,
 			},
+			{
+				Key:      cmd.KeyAdapterToggleFilter,
+				Enabled:  "On",
+				Disabled: "Off",
+				OnClick:  true,
+				OnCreate: true,
+			},
+			{
+				Key:     cmd.KeyAdapterToggleTransport,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyAdapterDisconnectAll,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyAdapterRemoveUnpaired,
+				OnClick: true,
+			},
+			{
+				Key:      cmd.KeyAdapterToggleAirplane,
+				Enabled:  "On",
+				Disabled: "Off",
+				OnClick:  true,
+				OnCreate: true,
+			},
+			{
+				Key:      cmd.KeyToggleRSSIFormat,
+				Enabled:  "On",
+				Disabled: "Off",
+				OnClick:  true,
+				OnCreate: true,
+			},
 			{
 				Key:     cmd.KeyAdapterChange,
 				OnClick: true,
 			},
+			{
+				Key:     cmd.KeyAdapterInfo,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyConnectionProfiles,
+				OnClick: true,
+			},
 			{
 				Key:     cmd.KeyProgressView,
 				OnClick: true,
@@ -112,16 +165,36 @@ var (
 				OnClick: true,
 				Visible: true,
 			},
+			{
+				Key:     cmd.KeyDeviceBrowseFiles,
+				OnClick: true,
+				Visible: true,
+			},
 			{
 				Key:     cmd.KeyDeviceNetwork,
 				OnClick: true,
 				Visible: true,
 			},
+			{
+				Key:     cmd.KeyDeviceTether,
+				OnClick: true,
+				Visible: true,
+			},
 			{
 				Key:     cmd.KeyDeviceAudioProfiles,
 				OnClick: true,
 				Visible: true,
 			},
+			{
+				Key:     cmd.KeyDeviceProfiles,
+				OnClick: true,
+				Visible: true,
+			},
+			{
+				Key:     cmd.KeyDeviceGatt,
+				OnClick: true,
+				Visible: true,
+			},
 			{
 				Key:     cmd.KeyPlayerShow,
 				OnClick: true,
@@ -131,10 +204,38 @@ var (
 				Key:     cmd.KeyDeviceInfo,
 				OnClick: true,
 			},
+			{
+				Key:     cmd.KeyDeviceServiceRecords,
+				OnClick: true,
+			},
 			{
 				Key:     cmd.KeyDeviceRemove,
 				OnClick: true,
 			},
+			{
+				Key:     cmd.KeyDeviceRename,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyDeviceEditTags,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyDeviceSearch,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyDeviceFilterTag,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyDeviceFilterClass,
+				OnClick: true,
+			},
+			{
+				Key:     cmd.KeyDeviceToggleSort,
+				OnClick: true,
+			},
 		},
 	}
 )
@@ -147,6 +248,9 @@ func menuBar() *tview.TextView {
 	menu.bar.SetRegions(true)
 	menu.bar.SetDynamicColors(true)
 	menu.bar.SetBackgroundColor(theme.GetColor(theme.ThemeMenuBar))
+	onThemeReload(func() {
+		menu.bar.SetBackgroundColor(theme.GetColor(theme.ThemeMenuBar))
+	})
 	menu.bar.SetHighlightedFunc(func(added, removed, remaining []string) {
 		if added == nil {
 			return
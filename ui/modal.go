@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+
 	"github.com/darkhz/bluetuith/cmd"
 	"github.com/darkhz/bluetuith/theme"
 	"github.com/darkhz/tview"
@@ -22,6 +24,8 @@ type Modal struct {
 	y      *tview.Flex
 	x      *tview.Flex
 	button *tview.TextView
+
+	exitFunc func()
 }
 
 var modals []*Modal
@@ -158,7 +162,9 @@ func NewDisplayModal(name, title, message string) {
 }
 
 // NewConfirmModal displays a modal, shows a message and asks for confirmation.
-func NewConfirmModal(name, title, message string) string {
+// If the context is done before a reply is given, the modal is closed and
+// the confirmation is cancelled.
+func NewConfirmModal(ctx context.Context, name, title, message string) string {
 	var modal *Modal
 
 	message += "\n\nPress y/n to Confirm/Cancel, click the required button or click the 'X' button to close this dialog."
@@ -236,7 +242,105 @@ func NewConfirmModal(name, title, message string) string {
 		modal.Show()
 	})
 
-	return <-reply
+	select {
+	case r := <-reply:
+		return r
+
+	case <-ctx.Done():
+		UI.QueueUpdateDraw(func() {
+			modal.Exit(false)
+		})
+	}
+
+	return "n"
+}
+
+// NewInputModal displays a modal, shows a message and asks for text input.
+// If the input is cancelled, or the context is done before the input is
+// entered, the modal is closed and false is returned.
+func NewInputModal(ctx context.Context, name, title, message string) (string, bool) {
+	var modal *Modal
+
+	message += "\n\nPress Enter to confirm the input, or Escape or click the 'X' button to cancel."
+
+	reply := make(chan bool, 1)
+
+	send := func(entered bool) {
+		select {
+		case reply <- entered:
+		default:
+		}
+	}
+
+	width, height := getModalDimensions(message, "")
+
+	textview := tview.NewTextView()
+	textview.SetText(message)
+	textview.SetDynamicColors(true)
+	textview.SetTextAlign(tview.AlignCenter)
+	textview.SetTextColor(theme.GetColor(theme.ThemeText))
+	textview.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
+	input := tview.NewInputField()
+	input.SetFieldTextColor(theme.GetColor(theme.ThemeText))
+	input.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	input.SetFieldBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch cmd.KeyOperation(event) {
+		case cmd.KeySelect:
+			send(true)
+			modal.Exit(false)
+
+			return nil
+
+		case cmd.KeyClose:
+			send(false)
+			modal.Exit(false)
+
+			return nil
+		}
+
+		return event
+	})
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(textview, 0, 1, false).
+		AddItem(input, 1, 0, true)
+
+	modal = NewModal(name, title, flex, height+1, width)
+	modal.exitFunc = func() {
+		send(false)
+	}
+
+	go UI.QueueUpdateDraw(func() {
+		if m, ok := ModalExists(name); ok {
+			m.Exit(false)
+		}
+
+		modal.Show()
+	})
+
+	select {
+	case entered := <-reply:
+		if !entered {
+			return "", false
+		}
+
+		var text string
+		UI.QueueUpdateDraw(func() {
+			text = input.GetText()
+		})
+
+		return text, true
+
+	case <-ctx.Done():
+		UI.QueueUpdateDraw(func() {
+			modal.Exit(false)
+		})
+	}
+
+	return "", false
 }
 
 // Show shows the modal.
@@ -294,6 +398,10 @@ func (m *Modal) Exit(focusInput bool) {
 
 	UI.Pages.RemovePage(m.Name)
 
+	if m.exitFunc != nil {
+		go m.exitFunc()
+	}
+
 	for i, modal := range modals {
 		if modal == m {
 			modals[i] = modals[len(modals)-1]
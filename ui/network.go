@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -117,3 +118,57 @@ func networkConnect(device bluez.Device, connType string) {
 		},
 	)
 }
+
+// tetherDevice toggles the PAN network connection with the device, where the
+// device is used as a network access point (NAP). This uses the bluez network
+// service directly, and does not require NetworkManager.
+func tetherDevice(device bluez.Device) {
+	if !device.HaveService(bluez.NAP_SVCLASS_ID) {
+		ErrorMessage(errors.New("No network access point (PAN) service available on " + device.Name))
+		return
+	}
+
+	connection, err := UI.Bluez.GetNetworkConnection(device.Path)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	if connection.Connected {
+		InfoMessage("Disconnecting from the network of "+device.Name, true)
+		if err := UI.Bluez.DisconnectNetwork(device.Path); err != nil {
+			ErrorMessage(err)
+			return
+		}
+		InfoMessage("Disconnected from the network of "+device.Name+" ("+connection.Interface+")", false)
+
+		return
+	}
+
+	startOperation(
+		func() {
+			InfoMessage("Connecting to the network of "+device.Name, true)
+			iface, err := UI.Bluez.ConnectNetwork(device.Path, "nap")
+			if err != nil {
+				ErrorMessage(err)
+				return
+			}
+			InfoMessage("Connected to the network of "+device.Name+" on interface "+iface, false)
+		},
+		func() {
+			UI.Bluez.DisconnectNetwork(device.Path)
+			InfoMessage("Cancelled connection to the network of "+device.Name, false)
+		},
+	)
+}
+
+// disconnectNetwork disconnects the PAN network connection with the device, if it exists.
+func disconnectNetwork(device bluez.Device) {
+	if !device.HaveService(bluez.NAP_SVCLASS_ID) {
+		return
+	}
+
+	if connection, err := UI.Bluez.GetNetworkConnection(device.Path); err == nil && connection.Connected {
+		UI.Bluez.DisconnectNetwork(device.Path)
+	}
+}
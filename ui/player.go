@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -13,7 +15,10 @@ import (
 )
 
 type MediaPlayer struct {
-	skip bool
+	skip   bool
+	device string
+
+	cancelSeek context.CancelFunc
 
 	keyEvent               chan string
 	stopEvent, buttonEvent chan struct{}
@@ -22,24 +27,33 @@ type MediaPlayer struct {
 	lock       sync.Mutex
 }
 
+// playerVolumeStep is the amount by which the volume is raised or lowered.
+const playerVolumeStep = 8
+
 const mediaButtons = `["rewind"][::b][<<][""] ["prev"][::b][<][""] ["play"][::b][|>][""] ["next"][::b][>][""] ["fastforward"][::b][>>][""]`
 
 var mediaplayer MediaPlayer
 
 // StartMediaPlayer shows the media player.
 func StartMediaPlayer() {
-	mediaplayer.lock.Lock()
-	defer mediaplayer.lock.Unlock()
-
 	device := getDeviceFromSelection(false)
 	if device.Path == "" {
 		return
 	}
 
+	if err := startMediaPlayer(device); err != nil {
+		ErrorMessage(err)
+	}
+}
+
+// startMediaPlayer shows the media player for the provided device.
+func startMediaPlayer(device bluez.Device) error {
+	mediaplayer.lock.Lock()
+	defer mediaplayer.lock.Unlock()
+
 	err := UI.Bluez.InitMediaPlayer(device.Path)
 	if err != nil {
-		ErrorMessage(err)
-		return
+		return err
 	}
 
 	if mediaplayer.keyEvent == nil {
@@ -50,7 +64,47 @@ func StartMediaPlayer() {
 		mediaplayer.playerLock = semaphore.NewWeighted(1)
 	}
 
-	go mediaPlayerLoop(device.Name)
+	go mediaPlayerLoop(device)
+
+	return nil
+}
+
+// showMediaPlayer shows the media player if the device is the selected
+// device and has a connected media player, and no media player is shown.
+func showMediaPlayer(device bluez.Device) {
+	if device.Path == "" || getMediaPlayerDevice() != "" {
+		return
+	}
+
+	if !UI.Bluez.HasMediaPlayer(device.Path) {
+		return
+	}
+
+	if getDeviceFromSelection(true).Path != device.Path {
+		return
+	}
+
+	startMediaPlayer(device)
+}
+
+// playerEvent handles the media control events of the devices, and shows or
+// hides the media player when a device's media player appears or goes away.
+func playerEvent(signalData interface{}) {
+	control, ok := signalData.(bluez.MediaControl)
+	if !ok {
+		return
+	}
+
+	if control.Connected && control.Player != "" {
+		device := UI.Bluez.GetDevice(control.Device)
+		showMediaPlayer(device)
+
+		return
+	}
+
+	if getMediaPlayerDevice() == control.Device {
+		StopMediaPlayer()
+	}
 }
 
 // StopMediaPlayer closes the media player.
@@ -64,19 +118,26 @@ func StopMediaPlayer() {
 	}
 }
 
-// mediaPlayerLoop updates the media player.
-func mediaPlayerLoop(deviceName string) {
+// mediaPlayerLoop updates the media player. The position is polled
+// only while the media is playing, otherwise the media player is
+// updated on player signals and key events.
+func mediaPlayerLoop(device bluez.Device) {
 	if !mediaplayer.playerLock.TryAcquire(1) {
 		return
 	}
 	defer mediaplayer.playerLock.Release(1)
 
-	player, views := setupMediaPlayer(deviceName)
+	setMediaPlayerDevice(device.Path)
+	defer setMediaPlayerDevice("")
+	defer stopPlayerSeek()
+
+	player, views := setupMediaPlayer(device.Name)
 	playerInfo := views[0]
 	playerTitle := views[1]
 	playerProgress := views[2]
 	playerTrack := views[3]
 	playerButtons := views[4]
+	playerDevice := views[5]
 
 	UI.QueueUpdateDraw(func() {
 		statusHelpArea(false)
@@ -98,16 +159,24 @@ PlayerLoop:
 
 		_, _, width, _ := UI.Pages.GetRect()
 		title, buttons, tracknum, progress := getProgress(media, mediaButtons, width, isPlayerSkip())
+		deviceInfo := getPlayerVolume(device)
 
 		UI.QueueUpdateDraw(func() {
-			playerInfo.SetText(media.Track.Artist + " - " + media.Track.Album)
+			playerInfo.SetText(getTrackInfo(media.Track))
 
 			playerTitle.SetText(title)
 			playerTrack.SetText(tracknum)
 			playerButtons.SetText(buttons)
 			playerProgress.SetText(progress)
+			playerDevice.SetText(deviceInfo)
 		})
 
+		if isPlayerActive(media) {
+			t.Reset(1 * time.Second)
+		} else {
+			t.Stop()
+		}
+
 		select {
 		case <-mediaplayer.stopEvent:
 			break PlayerLoop
@@ -118,23 +187,26 @@ PlayerLoop:
 			}
 
 			playerButtons.Highlight(highlight)
-			t.Reset(1 * time.Second)
 
 		case <-mediaplayer.buttonEvent:
-			t.Reset(1 * time.Second)
 
 		case signal, ok := <-mediaSignal:
 			if !ok {
 				break PlayerLoop
 			}
 
-			_, ok = UI.Bluez.ParseSignalData(signal).(bluez.MediaProperties)
-			if !ok {
+			switch data := UI.Bluez.ParseSignalData(signal).(type) {
+			case bluez.MediaProperties:
+
+			case bluez.MediaTransport:
+				if data.Device != device.Path {
+					continue PlayerLoop
+				}
+
+			default:
 				continue PlayerLoop
 			}
 
-			t.Reset(1 * time.Second)
-
 		case <-t.C:
 		}
 	}
@@ -244,7 +316,7 @@ func setupMediaPlayer(deviceName string) (*tview.Flex, []*tview.TextView) {
 		SetDirection(tview.FlexRow)
 	player.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
 
-	return player, []*tview.TextView{info, title, progress, track, buttons}
+	return player, []*tview.TextView{info, title, progress, track, buttons, device}
 }
 
 // playerEvents handles the media player events.
@@ -260,15 +332,11 @@ func playerEvents(event *tcell.EventKey, button bool) {
 
 	switch operation {
 	case cmd.KeyPlayerSeekForward:
-		UI.Bluez.FastForward()
-
-		setPlayerSkip(true)
+		seekPlayer(true)
 		highlight = "fastforward"
 
 	case cmd.KeyPlayerSeekBackward:
-		UI.Bluez.Rewind()
-
-		setPlayerSkip(true)
+		seekPlayer(false)
 		highlight = "rewind"
 
 	case cmd.KeyPlayerPrevious:
@@ -280,8 +348,15 @@ func playerEvents(event *tcell.EventKey, button bool) {
 	case cmd.KeyPlayerStop:
 		UI.Bluez.Stop()
 
+	case cmd.KeyPlayerVolumeUp:
+		setPlayerVolume(playerVolumeStep)
+
+	case cmd.KeyPlayerVolumeDown:
+		setPlayerVolume(-playerVolumeStep)
+
 	case cmd.KeyPlayerTogglePlay:
 		if isPlayerSkip() {
+			stopPlayerSeek()
 			UI.Bluez.Play()
 			setPlayerSkip(false)
 
@@ -313,6 +388,148 @@ func playerEvents(event *tcell.EventKey, button bool) {
 	}
 }
 
+// seekPlayer fast-forwards or rewinds the current track. If the "player-seek-step"
+// option is set, playback is resumed once the position has changed by the step,
+// or once the step has elapsed, otherwise playback is resumed with the play key.
+func seekPlayer(forward bool) {
+	stopPlayerSeek()
+
+	media, err := UI.Bluez.GetMediaProperties()
+	if err != nil {
+		return
+	}
+
+	if forward {
+		err = UI.Bluez.FastForward()
+	} else {
+		err = UI.Bluez.Rewind()
+	}
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	setPlayerSkip(true)
+
+	step, _ := time.ParseDuration(cmd.GetProperty("player-seek-step"))
+	if step <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mediaplayer.lock.Lock()
+	mediaplayer.cancelSeek = cancel
+	mediaplayer.lock.Unlock()
+
+	go finishPlayerSeek(ctx, media, forward, step)
+}
+
+// finishPlayerSeek waits until the position of the track has changed by the step,
+// or until the step has elapsed, and then restores the previous playback status.
+func finishPlayerSeek(ctx context.Context, media bluez.MediaProperties, forward bool, step time.Duration) {
+	target := int64(media.Position) - step.Milliseconds()
+	if forward {
+		target = int64(media.Position) + step.Milliseconds()
+	}
+
+	timeout := time.NewTimer(step)
+	defer timeout.Stop()
+
+	t := time.NewTicker(100 * time.Millisecond)
+	defer t.Stop()
+
+SeekLoop:
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-timeout.C:
+			break SeekLoop
+
+		case <-t.C:
+			current, err := UI.Bluez.GetMediaProperties()
+			if err != nil {
+				return
+			}
+
+			position := int64(current.Position)
+			if (forward && position >= target) || (!forward && position <= target) {
+				break SeekLoop
+			}
+		}
+	}
+
+	mediaplayer.lock.Lock()
+	if ctx.Err() != nil {
+		mediaplayer.lock.Unlock()
+		return
+	}
+
+	mediaplayer.skip = false
+	mediaplayer.cancelSeek = nil
+	mediaplayer.lock.Unlock()
+
+	if media.Status == "paused" {
+		UI.Bluez.Pause()
+	} else {
+		UI.Bluez.Play()
+	}
+
+	select {
+	case mediaplayer.buttonEvent <- struct{}{}:
+
+	default:
+	}
+}
+
+// stopPlayerSeek stops waiting for a seek with the "player-seek-step" option to finish.
+func stopPlayerSeek() {
+	mediaplayer.lock.Lock()
+	defer mediaplayer.lock.Unlock()
+
+	if mediaplayer.cancelSeek != nil {
+		mediaplayer.cancelSeek()
+		mediaplayer.cancelSeek = nil
+	}
+}
+
+// setPlayerVolume changes the absolute volume of the device whose media player is shown.
+func setPlayerVolume(step int) {
+	transport, ok := UI.Bluez.GetVolumeTransport(getMediaPlayerDevice())
+	if !ok {
+		InfoMessage("Volume control is not supported by this device", false)
+		return
+	}
+
+	if err := UI.Bluez.SetTransportVolume(transport.Path, int(transport.Volume)+step); err != nil {
+		ErrorMessage(err)
+	}
+}
+
+// getPlayerVolume returns the name of the device, along with its absolute volume
+// if the device supports volume control.
+func getPlayerVolume(device bluez.Device) string {
+	transport, ok := UI.Bluez.GetVolumeTransport(device.Path)
+	if !ok {
+		return device.Name
+	}
+
+	return fmt.Sprintf("Vol %d%% | %s", transport.VolumePercent(), device.Name)
+}
+
+// isPlayerActive returns if the playback position is changing, in which
+// case the media player is polled to update the position.
+func isPlayerActive(media bluez.MediaProperties) bool {
+	switch media.Status {
+	case "playing", "forward-seek", "reverse-seek":
+		return true
+	}
+
+	return isPlayerSkip()
+}
+
 func isPlayerSkip() bool {
 	mediaplayer.lock.Lock()
 	defer mediaplayer.lock.Unlock()
@@ -326,3 +543,19 @@ func setPlayerSkip(skip bool) {
 
 	mediaplayer.skip = skip
 }
+
+// getMediaPlayerDevice returns the path of the device whose media player is shown.
+func getMediaPlayerDevice() string {
+	mediaplayer.lock.Lock()
+	defer mediaplayer.lock.Unlock()
+
+	return mediaplayer.device
+}
+
+// setMediaPlayerDevice sets the path of the device whose media player is shown.
+func setMediaPlayerDevice(devicePath string) {
+	mediaplayer.lock.Lock()
+	defer mediaplayer.lock.Unlock()
+
+	mediaplayer.device = devicePath
+}
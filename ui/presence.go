@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+)
+
+// presenceScanWindow is the minimum duration for which an adapter must
+// have been scanning, before paired devices which were not seen during
+// the scan are considered to be out of range.
+const presenceScanWindow = 15 * time.Second
+
+// devicePresence holds the times at which the devices were last seen,
+// and the times at which the adapters last started and stopped scanning.
+type devicePresence struct {
+	seen  map[string]time.Time
+	scans map[string]presenceScan
+
+	lock sync.Mutex
+}
+
+// presenceScan describes the duration of a scan on an adapter.
+type presenceScan struct {
+	start, end time.Time
+}
+
+var presence devicePresence
+
+// startPresenceCheck periodically scans on the current adapter for a short
+// duration, if the "presence-check-interval" option is set and the adapter
+// is not already scanning, to detect whether paired devices are in range.
+func startPresenceCheck() {
+	interval, err := time.ParseDuration(cmd.GetProperty("presence-check-interval"))
+	if err != nil || interval <= 0 {
+		return
+	}
+
+	go func() {
+		for range time.Tick(interval) {
+			adapter := UI.Bluez.GetAdapter(UI.Bluez.GetCurrentAdapter().Path)
+			if !adapter.Powered || adapter.Discovering || isDiscoveryPaused(adapter.Path) {
+				continue
+			}
+
+			if err := UI.Bluez.StartDiscovery(adapter.Path); err != nil {
+				continue
+			}
+
+			time.Sleep(presenceScanWindow)
+
+			UI.Bluez.StopDiscovery(adapter.Path)
+		}
+	}()
+}
+
+// startScanRefresh periodically checks the device list against the devices
+// managed by bluez while the current adapter is scanning, if the
+// "scan-refresh-interval" option is set. Devices which have disappeared are
+// removed from the list, and only the devices which have changed are redrawn.
+func startScanRefresh() {
+	interval, err := strconv.Atoi(cmd.GetProperty("scan-refresh-interval"))
+	if err != nil || interval <= 0 {
+		return
+	}
+
+	go func() {
+		for range time.Tick(time.Duration(interval) * time.Second) {
+			adapter := UI.Bluez.GetAdapter(UI.Bluez.GetCurrentAdapter().Path)
+			if !adapter.Discovering {
+				continue
+			}
+
+			changed, removed, err := UI.Bluez.ReconcileDevices(adapter.Path)
+			if err != nil || (changed == nil && removed == nil) {
+				continue
+			}
+
+			UI.QueueUpdateDraw(func() {
+				for _, devicePath := range removed {
+					if row, ok := checkDeviceTable(devicePath); ok {
+						DeviceTable.RemoveRow(row)
+					}
+				}
+
+				for _, device := range changed {
+					primary, _, _ := getDeviceGroup(device)
+					if row, ok := checkDeviceTable(primary.Path); ok {
+						setDeviceTableInfo(moveDeviceRow(row, primary), primary)
+						continue
+					}
+
+					setDeviceGroupInfo(device)
+				}
+			})
+		}
+	}()
+}
+
+// setDeviceSeen records that the device is in range, if it is
+// connected or was found during a scan.
+func setDeviceSeen(device bluez.Device) {
+	if !device.Connected && device.RSSI >= 0 {
+		return
+	}
+
+	presence.lock.Lock()
+	defer presence.lock.Unlock()
+
+	if presence.seen == nil {
+		presence.seen = make(map[string]time.Time)
+	}
+
+	presence.seen[device.Path] = time.Now()
+}
+
+// setAdapterScanning records the start or end of a scan on the adapter. Once the scan
+// has been running for the presence scan window, the device list is refreshed to
+// show the devices which are out of range.
+func setAdapterScanning(adapter bluez.Adapter) {
+	presence.lock.Lock()
+	defer presence.lock.Unlock()
+
+	if presence.scans == nil {
+		presence.scans = make(map[string]presenceScan)
+	}
+
+	scan, ok := presence.scans[adapter.Path]
+	scanning := ok && scan.end.IsZero()
+
+	switch {
+	case adapter.Discovering && !scanning:
+		presence.scans[adapter.Path] = presenceScan{start: time.Now()}
+
+		time.AfterFunc(presenceScanWindow, func() {
+			if adapter.Path == UI.Bluez.GetCurrentAdapter().Path {
+				UI.QueueUpdateDraw(refreshDeviceRows)
+			}
+		})
+
+	case !adapter.Discovering && scanning:
+		scan.end = time.Now()
+		presence.scans[adapter.Path] = scan
+	}
+}
+
+// isDeviceAway returns if a paired device, which is not connected, was not seen
+// during the most recent scan of its adapter which lasted for the presence scan window.
+func isDeviceAway(device bluez.Device) bool {
+	if device.Connected || !device.Paired || device.RSSI < 0 {
+		return false
+	}
+
+	presence.lock.Lock()
+	defer presence.lock.Unlock()
+
+	scan, ok := presence.scans[device.Adapter]
+	if !ok {
+		return false
+	}
+
+	end := scan.end
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if end.Sub(scan.start) < presenceScanWindow {
+		return false
+	}
+
+	return presence.seen[device.Path].Before(scan.start)
+}
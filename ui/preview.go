@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// previewSize is the maximum number of bytes of a file that are previewed.
+const previewSize = 16 * 1024
+
+// previewFile displays the beginning of a file in a modal, as decoded
+// text if it is printable text, or as a hex and ASCII dump otherwise.
+func previewFile(path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, previewSize))
+	if err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	title := filepath.Base(path) + " (" + formatSize(info.Size())
+	if info.Size() > previewSize {
+		title += ", first " + formatSize(previewSize)
+	}
+	title += ")"
+
+	textview := tview.NewTextView()
+	textview.SetText(previewText(data))
+	textview.SetDynamicColors(false)
+	textview.SetScrollable(true)
+	textview.SetTextColor(theme.GetColor(theme.ThemeText))
+	textview.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
+	modal := NewModal("preview", tview.Escape(title), textview, 40, 100)
+	textview.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch cmd.KeyOperation(event) {
+		case cmd.KeyClose:
+			modal.Exit(false)
+		}
+
+		return event
+	})
+
+	if m, ok := ModalExists("preview"); ok {
+		m.Exit(false)
+	}
+
+	modal.Show()
+}
+
+// previewText returns the data as text if it is printable,
+// or as a hex and ASCII dump otherwise.
+func previewText(data []byte) string {
+	if len(data) == 0 {
+		return "(empty file)"
+	}
+
+	text := string(data)
+	if !utf8.ValidString(text) && len(data) == previewSize {
+		// The preview may have cut a multibyte character at the end.
+		for i := 1; i < utf8.UTFMax && !utf8.ValidString(text); i++ {
+			text = string(data[:len(data)-i])
+		}
+	}
+
+	printable := utf8.ValidString(text) && strings.IndexFunc(text, func(r rune) bool {
+		return !unicode.IsPrint(r) && !unicode.IsSpace(r)
+	}) < 0
+	if printable {
+		return text
+	}
+
+	return hex.Dump(data)
+}
@@ -1,8 +1,10 @@
 package ui
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"time"
@@ -32,19 +34,415 @@ type ProgressIndicator struct {
 	desc        *tview.TableCell
 	progress    *tview.TableCell
 	progressBar *progressbar.ProgressBar
+	throughput  *throughput
+	barText     string
 
-	recv   bool
-	status string
+	recv    bool
+	status  string
+	attempt int
+
+	address, file string
+	savedPath     string
+	transferPath  dbus.ObjectPath
+	cancel        context.CancelFunc
 
 	signal chan *dbus.Signal
+	lock   sync.Mutex
+}
+
+// TransferQueue describes a queue of file transfers to be sent.
+type TransferQueue struct {
+	pending []*ProgressIndicator
+	devices map[string]struct{}
+	active  int
+
+	lock sync.Mutex
 }
 
-const progressViewButtonRegion = `["resume"][::b][Resume[][""] ["suspend"][::b][Pause[][""] ["cancel"][::b][Cancel[][""]`
+const progressViewButtonRegion = `["resume"][::b][Resume[][""] ["suspend"][::b][Pause[][""] ["cancel"][::b][Cancel[][""] ["retry"][::b][Retry[][""] ["preview"][::b][Preview[][""]`
 
-var progressUI ProgressUI
+var (
+	progressUI    ProgressUI
+	transferQueue TransferQueue
+)
 
 // NewProgress returns a new Progress.
 func NewProgress(transferPath dbus.ObjectPath, props bluez.ObexTransferProperties, recv bool) *ProgressIndicator {
+	progress := newProgressIndicator(props.Name, recv)
+	progress.start(transferPath, props)
+
+	return progress
+}
+
+// StartProgress creates a new progress indicator, monitors the OBEX DBus interface for transfer events,
+// and displays the progress on the screen. If the optional path parameter is provided, it means that
+// a file is being received, and on transfer completion, the received file should be moved to a user-accessible
+// directory, or to the directory of the device with the provided address if it is not empty.
+func StartProgress(transferPath dbus.ObjectPath, props bluez.ObexTransferProperties, address string, path ...string) bool {
+	progress := NewProgress(transferPath, props, path != nil)
+	progress.address = address
+
+	return progress.monitor(path...)
+}
+
+// QueueTransfers adds the files to the transfer queue, to be sent to the device.
+// The number of transfers that are run simultaneously is set by the
+// "max-concurrent-transfers" option, and files are sent to a device one at a time.
+func QueueTransfers(address string, files []string) {
+	queued := make([]*ProgressIndicator, 0, len(files))
+	for _, file := range files {
+		progress := newProgressIndicator(filepath.Base(file), false)
+		progress.address = address
+		progress.file = file
+
+		queued = append(queued, progress)
+	}
+
+	transferQueue.lock.Lock()
+	transferQueue.pending = append(transferQueue.pending, queued...)
+	transferQueue.lock.Unlock()
+
+	dispatchTransfers()
+}
+
+// dispatchTransfers starts the queued transfers, until the maximum
+// number of concurrent transfers are active.
+func dispatchTransfers() {
+	transferQueue.lock.Lock()
+	defer transferQueue.lock.Unlock()
+
+	maxTransfers, err := strconv.Atoi(cmd.GetProperty("max-concurrent-transfers"))
+	if err != nil || maxTransfers < 1 {
+		maxTransfers = 1
+	}
+
+	if transferQueue.devices == nil {
+		transferQueue.devices = make(map[string]struct{})
+	}
+
+	for transferQueue.active < maxTransfers {
+		progress := transferQueue.next()
+		if progress == nil {
+			break
+		}
+
+		transferQueue.active++
+		transferQueue.devices[progress.address] = struct{}{}
+
+		go func() {
+			progress.sendFile()
+
+			transferQueue.lock.Lock()
+			transferQueue.active--
+			delete(transferQueue.devices, progress.address)
+			transferQueue.lock.Unlock()
+
+			dispatchTransfers()
+		}()
+	}
+}
+
+// next removes and returns the first queued transfer whose device does not
+// have an active transfer. Transfers which are no longer queued are dropped.
+// This must be called with the queue locked.
+func (t *TransferQueue) next() *ProgressIndicator {
+	for i := 0; i < len(t.pending); i++ {
+		progress := t.pending[i]
+
+		if progress.getStatus() != "queued" {
+			t.pending = append(t.pending[:i], t.pending[i+1:]...)
+			i--
+
+			continue
+		}
+
+		if _, ok := t.devices[progress.address]; ok {
+			continue
+		}
+
+		t.pending = append(t.pending[:i], t.pending[i+1:]...)
+
+		return progress
+	}
+
+	return nil
+}
+
+// MoveProgress moves a queued transfer up or down the transfer queue,
+// according to the direction, where a negative direction moves the
+// transfer up.
+func MoveProgress(direction int) {
+	progress := getProgressData()
+	if progress == nil {
+		return
+	}
+
+	if progress.getStatus() != "queued" {
+		InfoMessage("Only queued transfers can be moved", false)
+		return
+	}
+
+	transferQueue.lock.Lock()
+	defer transferQueue.lock.Unlock()
+
+	index := -1
+	for i, queued := range transferQueue.pending {
+		if queued == progress {
+			index = i
+			break
+		}
+	}
+
+	swap := index + direction
+	if index < 0 || swap < 0 || swap >= len(transferQueue.pending) {
+		return
+	}
+
+	other := transferQueue.pending[swap]
+	transferQueue.pending[index], transferQueue.pending[swap] = other, progress
+
+	row, otherRow := getProgressRow(progress), getProgressRow(other)
+	if row < 0 || otherRow < 0 {
+		return
+	}
+
+	setProgressRow(row, other)
+	setProgressRow(otherRow, progress)
+
+	progressUI.view.Select(otherRow, 0)
+}
+
+// RemoveProgress removes a queued transfer from the transfer queue
+// and the progress view.
+func RemoveProgress() {
+	progress := getProgressData()
+	if progress == nil {
+		return
+	}
+
+	if !progress.compareAndSetStatus("queued", "cancelled") {
+		InfoMessage("Only queued transfers can be removed", false)
+		return
+	}
+
+	transferQueue.lock.Lock()
+	for i, queued := range transferQueue.pending {
+		if queued == progress {
+			transferQueue.pending = append(transferQueue.pending[:i], transferQueue.pending[i+1:]...)
+			break
+		}
+	}
+	transferQueue.lock.Unlock()
+
+	row := getProgressRow(progress)
+	if row < 0 {
+		return
+	}
+
+	progressUI.view.RemoveRow(row)
+	progressUI.view.RemoveRow(row - 1)
+
+	for i := 1; i < progressUI.view.GetRowCount(); i += 2 {
+		progressUI.view.GetCell(i, 0).SetText("#" + strconv.Itoa((i+1)/2))
+	}
+
+	if progressUI.view.GetRowCount() == 0 {
+		UI.Pages.SwitchToPage("main")
+	}
+}
+
+// SuspendProgress suspends the transfer.
+// This does not work when a file is being received.
+func SuspendProgress() {
+	progress := getProgressData()
+	if progress == nil || progress.getStatus() != "active" {
+		return
+	}
+
+	if progress.recv {
+		InfoMessage("Cannot resume/suspend receiving transfer", false)
+		return
+	}
+
+	UI.Obex.SuspendTransfer(progress.transferPath)
+}
+
+// ResumeProgress resumes the transfer.
+// This does not work when a file is being received.
+func ResumeProgress() {
+	progress := getProgressData()
+	if progress == nil || progress.getStatus() != "active" {
+		return
+	}
+
+	if progress.recv {
+		InfoMessage("Cannot resume/suspend receiving transfer", false)
+		return
+	}
+
+	UI.Obex.ResumeTransfer(progress.transferPath)
+}
+
+// CancelProgress cancels the transfer. If the transfer is queued,
+// it is removed from the queue.
+// This does not work when a file is being received.
+func CancelProgress() {
+	progress := getProgressData()
+	if progress == nil {
+		return
+	}
+
+	if progress.recv {
+		InfoMessage("Cannot cancel receiving transfer", false)
+		return
+	}
+
+	progress.lock.Lock()
+	defer progress.lock.Unlock()
+
+	switch progress.status {
+	case "queued", "starting", "retrying":
+		progress.status = "cancelled"
+		progress.progress.SetText(progressStatusText(progress.status))
+
+		if progress.cancel != nil {
+			progress.cancel()
+		}
+
+	case "active":
+		progress.status = "cancelled"
+
+		UI.Obex.CancelTransfer(progress.transferPath)
+		UI.Obex.Conn().RemoveSignal(progress.signal)
+
+		close(progress.signal)
+	}
+}
+
+// RetryProgress adds a failed or cancelled transfer back to the
+// transfer queue, to be sent again from the start.
+// This does not work when a file is being received.
+func RetryProgress() {
+	progress := getProgressData()
+	if progress == nil {
+		return
+	}
+
+	if progress.recv || progress.file == "" {
+		InfoMessage("Only sent files can be retried", false)
+		return
+	}
+
+	progress.lock.Lock()
+	switch progress.status {
+	case "error", "cancelled":
+		progress.status = "queued"
+		progress.attempt = 0
+		progress.progress.SetText(progressStatusText(progress.status))
+
+	default:
+		progress.lock.Unlock()
+		InfoMessage("Only failed or cancelled transfers can be retried", false)
+
+		return
+	}
+	progress.lock.Unlock()
+
+	transferQueue.lock.Lock()
+	transferQueue.pending = append(transferQueue.pending, progress)
+	transferQueue.lock.Unlock()
+
+	go dispatchTransfers()
+}
+
+// PreviewProgress displays a preview of the file, if it was received
+// and saved successfully.
+func PreviewProgress() {
+	progress := getProgressData()
+	if progress == nil {
+		return
+	}
+
+	progress.lock.Lock()
+	savedPath := progress.savedPath
+	progress.lock.Unlock()
+
+	if !progress.recv || savedPath == "" {
+		InfoMessage("Only received files can be previewed", false)
+		return
+	}
+
+	previewFile(savedPath)
+}
+
+// FinishProgress marks the progress indicator as finished. If a file was received, as indicated by the path parameter,
+// the file is moved from the "root" (usually the ~/.cache/obexd folder) to the user's home directory.
+func (p *ProgressIndicator) FinishProgress(transferPath dbus.ObjectPath, path ...string) {
+	decProgressCount()
+	UI.Obex.Conn().RemoveSignal(p.signal)
+
+	status := p.getStatus()
+	if status == "error" {
+		cmd.LogWarn("transfer", "%s: Transfer failed", transferPath)
+	} else {
+		cmd.Log("transfer", "%s: Transfer %s", transferPath, status)
+	}
+
+	UI.QueueUpdateDraw(func() {
+		p.progress.SetText(progressStatusText(status))
+
+		if getProgressCount() == 0 {
+			progressUI.status.Clear()
+			UI.Status.SwitchToPage("messages")
+		}
+	})
+
+	if path != nil && status == "complete" {
+		savedPath, err := SaveFile(path[0], p.address)
+		if err != nil {
+			ErrorMessage(err)
+			return
+		}
+
+		p.lock.Lock()
+		p.savedPath = savedPath
+		p.lock.Unlock()
+	}
+}
+
+// Write is used by the progressbar to display the progress on the screen.
+func (p *ProgressIndicator) Write(b []byte) (int, error) {
+	p.lock.Lock()
+	p.barText = string(b)
+	p.lock.Unlock()
+
+	p.setProgressText()
+
+	return 0, nil
+}
+
+// setProgressText displays the progress bar, along with the speed and
+// the estimated time remaining of the transfer.
+func (p *ProgressIndicator) setProgressText() {
+	p.lock.Lock()
+	if p.status != "active" || p.throughput == nil {
+		p.lock.Unlock()
+		return
+	}
+
+	text := p.barText
+	if rate := p.throughput.text(time.Now()); rate != "" {
+		text += " " + rate
+	}
+	p.lock.Unlock()
+
+	UI.QueueUpdateDraw(func() {
+		p.progress.SetText(text)
+	})
+}
+
+// newProgressIndicator returns a new progress indicator, and adds it to the progress view.
+func newProgressIndicator(name string, recv bool) *ProgressIndicator {
 	var progress ProgressIndicator
 	var progressText string
 
@@ -54,10 +452,10 @@ func NewProgress(transferPath dbus.ObjectPath, props bluez.ObexTransferPropertie
 		progressText = "Sending"
 	}
 
-	incProgressCount()
+	title := fmt.Sprintf(" [::b]%s %s[-:-:-]", progressText, tview.Escape(name))
 
-	count := getProgressCount()
-	title := fmt.Sprintf(" [::b]%s %s[-:-:-]", progressText, props.Name)
+	progress.recv = recv
+	progress.status = "queued"
 
 	progress.desc = tview.NewTableCell(title).
 		SetExpansion(1).
@@ -65,36 +463,20 @@ func NewProgress(transferPath dbus.ObjectPath, props bluez.ObexTransferPropertie
 		SetAlign(tview.AlignLeft).
 		SetTextColor(theme.GetColor(theme.ThemeProgressText))
 
-	progress.progress = tview.NewTableCell("").
+	progress.progress = tview.NewTableCell(progressStatusText(progress.status)).
 		SetExpansion(1).
 		SetSelectable(false).
-		SetReference(&progress).
 		SetAlign(tview.AlignRight).
 		SetTextColor(theme.GetColor(theme.ThemeProgressBar))
 
-	progress.progressBar = progressbar.NewOptions64(
-		int64(props.Size),
-		progressbar.OptionSpinnerType(34),
-		progressbar.OptionSetWriter(&progress),
-		progressbar.OptionSetPredictTime(false),
-		progressbar.OptionSetRenderBlankState(true),
-		progressbar.OptionThrottle(200*time.Millisecond),
-	)
-
-	progress.recv = recv
-	progress.signal = UI.Obex.WatchSignal()
-
 	UI.QueueUpdateDraw(func() {
 		progressView(false)
-		statusProgressView(true)
 
 		rows := progressUI.view.GetRowCount()
+		count := (rows + 1) / 2
 
-		progressUI.status.SetCell(0, 0, progress.desc)
-		progressUI.status.SetCell(0, 1, progress.progress)
-
-		progressUI.view.SetCell(rows+1, 0, tview.NewTableCell("#"+strconv.Itoa(count)).
-			SetReference(transferPath).
+		progressUI.view.SetCell(rows+1, 0, tview.NewTableCell("#"+strconv.Itoa(count+1)).
+			SetReference(&progress).
 			SetAlign(tview.AlignCenter),
 		)
 		progressUI.view.SetCell(rows+1, 1, progress.desc)
@@ -104,19 +486,57 @@ func NewProgress(transferPath dbus.ObjectPath, props bluez.ObexTransferPropertie
 	return &progress
 }
 
-// StartProgress creates a new progress indicator, monitors the OBEX DBus interface for transfer events,
-// and displays the progress on the screen. If the optional path parameter is provided, it means that
-// a file is being received, and on transfer completion, the received file should be moved to a user-accessible
-// directory.
-func StartProgress(transferPath dbus.ObjectPath, props bluez.ObexTransferProperties, path ...string) bool {
-	progress := NewProgress(transferPath, props, path != nil)
+// start marks the progress indicator as active, and displays the
+// progress of the transfer.
+func (p *ProgressIndicator) start(transferPath dbus.ObjectPath, props bluez.ObexTransferProperties) {
+	incProgressCount()
+
+	p.lock.Lock()
+	p.status = "active"
+	p.transferPath = transferPath
+	p.signal = UI.Obex.WatchSignal()
+	p.throughput = newThroughput(int64(props.Size), time.Now())
+	p.barText = ""
+	p.lock.Unlock()
+
+	if p.recv {
+		cmd.Log("transfer", "%s: Receiving %s (%d bytes)", transferPath, props.Name, props.Size)
+	} else {
+		cmd.Log("transfer", "%s: Sending %s to %s (%d bytes)", transferPath, props.Name, p.address, props.Size)
+	}
+
+	p.progressBar = progressbar.NewOptions64(
+		int64(props.Size),
+		progressbar.OptionSpinnerType(34),
+		progressbar.OptionSetWriter(p),
+		progressbar.OptionSetPredictTime(false),
+		progressbar.OptionSetRenderBlankState(true),
+		progressbar.OptionThrottle(200*time.Millisecond),
+	)
+
+	UI.QueueUpdateDraw(func() {
+		statusProgressView(true)
+
+		progressUI.status.SetCell(0, 0, p.desc)
+		progressUI.status.SetCell(0, 1, p.progress)
+	})
+}
+
+// monitor monitors the OBEX DBus interface for transfer events, and
+// updates the progress indicator until the transfer is finished.
+func (p *ProgressIndicator) monitor(path ...string) bool {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
 
 	for {
 		select {
-		case signal, ok := <-progress.signal:
+		case <-ticker.C:
+			p.setProgressText()
+
+		case signal, ok := <-p.signal:
 			if !ok {
-				progress.status = "error"
-				progress.FinishProgress(transferPath, path...)
+				p.setStatus("error")
+				p.FinishProgress(p.transferPath, path...)
 				return false
 			}
 
@@ -125,7 +545,7 @@ func StartProgress(transferPath dbus.ObjectPath, props bluez.ObexTransferPropert
 				continue
 			}
 
-			if transferPath != signal.Path {
+			if p.transferPath != signal.Path {
 				continue
 			}
 
@@ -135,113 +555,171 @@ func StartProgress(transferPath dbus.ObjectPath, props bluez.ObexTransferPropert
 				fallthrough
 
 			case "complete":
-				progress.status = props.TransferProperties.Status
-				progress.FinishProgress(transferPath, path...)
+				p.setStatus(props.TransferProperties.Status)
+				p.FinishProgress(p.transferPath, path...)
 				return true
 			}
 
-			progress.progressBar.Set64(int64(props.TransferProperties.Transferred))
+			p.lock.Lock()
+			p.throughput.add(int64(props.TransferProperties.Transferred), time.Now())
+			p.lock.Unlock()
+
+			p.progressBar.Set64(int64(props.TransferProperties.Transferred))
 		}
 	}
 }
 
-// SuspendProgress suspends the transfer.
-// This does not work when a file is being received.
-func SuspendProgress() {
-	transferPath, progress := getProgressData()
-	if transferPath == "" {
+// sendFile sends the queued file to the device. If the transfer fails, it is
+// retried from the start according to the "transfer-retries" option.
+func (p *ProgressIndicator) sendFile() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p.lock.Lock()
+	if p.status != "queued" {
+		p.lock.Unlock()
 		return
 	}
+	p.status = "starting"
+	p.cancel = cancel
+	p.lock.Unlock()
+
+	retries, _ := strconv.Atoi(cmd.GetProperty("transfer-retries"))
+	delay, _ := time.ParseDuration(cmd.GetProperty("transfer-retry-delay"))
+
+	for {
+		p.setStatusText()
+
+		p.sendAttempt(ctx)
+
+		p.lock.Lock()
+		if p.status != "error" || p.attempt >= retries {
+			p.lock.Unlock()
+			return
+		}
+		p.attempt++
+		p.status = "retrying"
+		p.lock.Unlock()
+
+		p.setStatusText()
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-time.After(delay):
+		}
+
+		if !p.compareAndSetStatus("retrying", "starting") {
+			return
+		}
+	}
+}
+
+// sendAttempt creates an OBEX session, and attempts to send the file to the device.
+func (p *ProgressIndicator) sendAttempt(ctx context.Context) {
+	sessionPath, err := UI.Obex.CreateSession(ctx, p.address)
+	if err != nil {
+		p.setStatus("error")
+		p.setStatusText()
+		ErrorMessage(err)
 
-	if progress.recv {
-		InfoMessage("Cannot resume/suspend receiving transfer", false)
 		return
 	}
+	defer UI.Obex.RemoveSession(sessionPath)
 
-	UI.Obex.SuspendTransfer(transferPath)
-}
+	transferPath, transferProps, err := UI.Obex.SendFile(sessionPath, p.file)
+	if err != nil {
+		p.setStatus("error")
+		p.setStatusText()
+		ErrorMessage(err)
 
-// ResumeProgress resumes the transfer.
-// This does not work when a file is being received.
-func ResumeProgress() {
-	transferPath, progress := getProgressData()
-	if transferPath == "" {
 		return
 	}
 
-	if progress.recv {
-		InfoMessage("Cannot resume/suspend receiving transfer", false)
+	if p.getStatus() == "cancelled" {
+		UI.Obex.CancelTransfer(transferPath)
 		return
 	}
 
-	UI.Obex.ResumeTransfer(transferPath)
+	p.start(transferPath, transferProps)
+	p.monitor()
 }
 
-// CancelProgress cancels the transfer.
-// This does not work when a file is being received.
-func CancelProgress() {
-	transferPath, progress := getProgressData()
-	if transferPath == "" {
+// getStatus returns the status of the transfer.
+func (p *ProgressIndicator) getStatus() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.status
+}
+
+// setStatus sets the status of the transfer, if it was not cancelled.
+func (p *ProgressIndicator) setStatus(status string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.status == "cancelled" {
 		return
 	}
 
-	if progress.recv {
-		InfoMessage("Cannot cancel receiving transfer", false)
-		return
+	p.status = status
+}
+
+// compareAndSetStatus sets the status of the transfer, if its current status
+// matches the provided status.
+func (p *ProgressIndicator) compareAndSetStatus(current, status string) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.status != current {
+		return false
 	}
 
-	UI.Obex.CancelTransfer(transferPath)
-	UI.Obex.Conn().RemoveSignal(progress.signal)
+	p.status = status
 
-	close(progress.signal)
+	return true
 }
 
-// FinishProgress removes the progress indicator from view. If a file was received, as indicated by the path parameter,
-// the file is moved from the "root" (usually the ~/.cache/obexd folder) to the user's home directory.
-func (p *ProgressIndicator) FinishProgress(transferPath dbus.ObjectPath, path ...string) {
-	decProgressCount()
-	UI.Obex.Conn().RemoveSignal(p.signal)
+// setStatusText displays the status of the transfer.
+func (p *ProgressIndicator) setStatusText() {
+	p.lock.Lock()
+	status, attempt := p.status, p.attempt
+	p.lock.Unlock()
+
+	text := progressStatusText(status)
+	if attempt > 0 && status != "cancelled" {
+		text += fmt.Sprintf(" (retry %d/%s)", attempt, cmd.GetProperty("transfer-retries"))
+	}
 
 	UI.QueueUpdateDraw(func() {
-		for row := 0; row < progressUI.view.GetRowCount(); row++ {
-			cell := progressUI.view.GetCell(row, 0)
-			if cell == nil {
-				continue
-			}
+		p.progress.SetText(text)
+	})
+}
 
-			path, ok := cell.GetReference().(dbus.ObjectPath)
-			if !ok {
-				continue
-			}
+// progressStatusText returns the text to be displayed for the transfer status.
+func progressStatusText(status string) string {
+	switch status {
+	case "queued":
+		return "Queued"
 
-			if path == transferPath {
-				progressUI.view.RemoveRow(row)
-				progressUI.view.RemoveRow(row - 1)
+	case "starting":
+		return "Starting"
 
-				break
-			}
-		}
+	case "retrying":
+		return "Retrying"
 
-		if getProgressCount() == 0 {
-			progressUI.status.Clear()
-			UI.Status.SwitchToPage("messages")
-		}
-	})
+	case "complete":
+		return "Completed"
 
-	if path != nil && p.status == "complete" {
-		if err := savefile(path[0]); err != nil {
-			ErrorMessage(err)
-		}
-	}
-}
+	case "error":
+		return "Failed"
 
-// Write is used by the progressbar to display the progress on the screen.
-func (p *ProgressIndicator) Write(b []byte) (int, error) {
-	UI.QueueUpdateDraw(func() {
-		p.progress.SetText(string(b))
-	})
+	case "cancelled":
+		return "Cancelled"
+	}
 
-	return 0, nil
+	return ""
 }
 
 // progressView initializes and, if switchToView is set, displays the progress view.
@@ -276,6 +754,21 @@ func progressView(switchToView bool) {
 			case cmd.KeyProgressTransferResume:
 				ResumeProgress()
 
+			case cmd.KeyProgressTransferRetry:
+				RetryProgress()
+
+			case cmd.KeyProgressTransferMoveUp:
+				MoveProgress(-1)
+
+			case cmd.KeyProgressTransferMoveDown:
+				MoveProgress(1)
+
+			case cmd.KeyProgressTransferRemove:
+				RemoveProgress()
+
+			case cmd.KeyProgressTransferPreview:
+				PreviewProgress()
+
 			case cmd.KeyQuit:
 				go quit()
 			}
@@ -305,6 +798,12 @@ func progressView(switchToView bool) {
 
 					case "cancel":
 						CancelProgress()
+
+					case "retry":
+						RetryProgress()
+
+					case "preview":
+						PreviewProgress()
 					}
 
 					progressViewButtons.Highlight("")
@@ -319,6 +818,13 @@ func progressView(switchToView bool) {
 			AddItem(title, 1, 0, false).
 			AddItem(progressUI.view, 0, 10, true).
 			AddItem(progressViewButtons, 2, 0, false)
+
+		onThemeReload(func() {
+			title.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+			title.SetText(theme.ColorWrap(theme.ThemeText, "Progress View", "::bu"))
+			progressUI.view.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+			progressViewButtons.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+		})
 	}
 
 	if switchToView {
@@ -326,8 +832,8 @@ func progressView(switchToView bool) {
 			UI.Status.SwitchToPage("messages")
 		}
 
-		if getProgressCount() == 0 {
-			InfoMessage("No transfers are in progress", false)
+		if progressUI.view.GetRowCount() == 0 {
+			InfoMessage("No transfers are queued or in progress", false)
 			return
 		}
 
@@ -341,6 +847,10 @@ func statusProgressView(switchToView bool) {
 		progressUI.status = tview.NewTable()
 		progressUI.status.SetSelectable(true, true)
 		progressUI.status.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
+		onThemeReload(func() {
+			progressUI.status.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+		})
 	}
 
 	UI.Status.AddPage("progressview", progressUI.status, true, false)
@@ -350,32 +860,34 @@ func statusProgressView(switchToView bool) {
 	}
 }
 
-// getProgressData gets the transfer DBus object path and the progress data
-// from the current selection in the progressUI.view.
-func getProgressData() (dbus.ObjectPath, *ProgressIndicator) {
+// getProgressData gets the progress data from the current selection in the progressUI.view.
+func getProgressData() *ProgressIndicator {
 	row, _ := progressUI.view.GetSelection()
 
-	pathCell := progressUI.view.GetCell(row, 0)
-	if pathCell == nil {
-		return "", nil
+	progress, ok := progressUI.view.GetCell(row, 0).GetReference().(*ProgressIndicator)
+	if !ok {
+		return nil
 	}
 
-	progCell := progressUI.view.GetCell(row, 2)
-	if progCell == nil {
-		return "", nil
-	}
+	return progress
+}
 
-	transferPath, ok := pathCell.GetReference().(dbus.ObjectPath)
-	if !ok {
-		return "", nil
+// getProgressRow returns the row of the progress indicator in the progressUI.view.
+func getProgressRow(progress *ProgressIndicator) int {
+	for row := 0; row < progressUI.view.GetRowCount(); row++ {
+		if progressUI.view.GetCell(row, 0).GetReference() == progress {
+			return row
+		}
 	}
 
-	progress, ok := progCell.GetReference().(*ProgressIndicator)
-	if !ok {
-		return "", nil
-	}
+	return -1
+}
 
-	return transferPath, progress
+// setProgressRow displays the progress indicator in the provided row of the progressUI.view.
+func setProgressRow(row int, progress *ProgressIndicator) {
+	progressUI.view.GetCell(row, 0).SetReference(progress)
+	progressUI.view.SetCell(row, 1, progress.desc)
+	progressUI.view.SetCell(row, 2, progress.progress)
 }
 
 // getProgressCount returns the progress count.
@@ -3,6 +3,8 @@ package ui
 import (
 	"context"
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/darkhz/bluetuith/cmd"
@@ -54,6 +56,16 @@ func statusBar() *tview.Flex {
 	UI.Status.Help.SetDynamicColors(true)
 	UI.Status.Help.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
 
+	onThemeReload(func() {
+		UI.Status.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+		UI.Status.InputField.SetLabelColor(theme.GetColor(theme.ThemeText))
+		UI.Status.InputField.SetFieldTextColor(theme.GetColor(theme.ThemeText))
+		UI.Status.InputField.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+		UI.Status.InputField.SetFieldBackgroundColor(theme.GetColor(theme.ThemeBackground))
+		UI.Status.MessageBox.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+		UI.Status.Help.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	})
+
 	UI.Status.AddPage("input", UI.Status.InputField, true, true)
 	UI.Status.AddPage("messages", UI.Status.MessageBox, true, true)
 	UI.Status.SwitchToPage("messages")
@@ -67,6 +79,10 @@ func statusBar() *tview.Flex {
 		SetDirection(tview.FlexRow).
 		AddItem(UI.Status.Pages, 1, 0, false)
 
+	if segments := statusSegmentsView(); segments != nil {
+		flex.AddItem(segments, 1, 0, false)
+	}
+
 	UI.Status.itemCount = flex.GetItemCount()
 
 	return flex
@@ -166,6 +182,8 @@ func ErrorMessage(err error) {
 		return
 	}
 
+	cmd.LogError("error", "%s", err.Error())
+
 	select {
 	case UI.Status.msgchan <- message{theme.ColorWrap(theme.ThemeStatusError, "Error: "+err.Error()), false}:
 		return
@@ -221,3 +239,117 @@ func startStatus() {
 		}
 	}
 }
+
+// statusSegmentsView sets up and returns the status bar segments display,
+// according to the "statusbar-segments" option. If no segments are specified,
+// nil is returned.
+func statusSegmentsView() *tview.Flex {
+	var left, right []string
+
+	option := cmd.GetProperty("statusbar-segments")
+	if option == "" {
+		return nil
+	}
+
+	for _, segmentAlign := range strings.Split(option, ",") {
+		segment, align, _ := strings.Cut(segmentAlign, ":")
+		if align == "right" {
+			right = append(right, segment)
+		} else {
+			left = append(left, segment)
+		}
+	}
+
+	leftView := tview.NewTextView()
+	leftView.SetDynamicColors(true)
+	leftView.SetTextAlign(tview.AlignLeft)
+	leftView.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
+	rightView := tview.NewTextView()
+	rightView.SetDynamicColors(true)
+	rightView.SetTextAlign(tview.AlignRight)
+	rightView.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
+	segments := tview.NewFlex().
+		SetDirection(tview.FlexColumn).
+		AddItem(leftView, 0, 1, false).
+		AddItem(rightView, 0, 1, false)
+	segments.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
+	onThemeReload(func() {
+		segments.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+		leftView.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+		rightView.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+
+		leftView.SetText(renderStatusSegments(left))
+		rightView.SetText(renderStatusSegments(right))
+	})
+
+	go func() {
+		t := time.NewTicker(time.Second)
+		defer t.Stop()
+
+		for {
+			leftText, rightText := renderStatusSegments(left), renderStatusSegments(right)
+
+			UI.QueueUpdateDraw(func() {
+				leftView.SetText(leftText)
+				rightView.SetText(rightText)
+			})
+
+			select {
+			case <-UI.Status.sctx.Done():
+				return
+
+			case <-t.C:
+			}
+		}
+	}()
+
+	return segments
+}
+
+// renderStatusSegments returns the text of the status bar segments.
+func renderStatusSegments(segments []string) string {
+	if segments == nil || UI.Bluez == nil {
+		return ""
+	}
+
+	texts := make([]string, 0, len(segments))
+	adapter := UI.Bluez.GetAdapter(UI.Bluez.GetCurrentAdapter().Path)
+
+	for _, segment := range segments {
+		var text string
+
+		switch segment {
+		case "adapter-name":
+			text = adapter.Name
+
+		case "adapter-address":
+			text = adapter.Address
+
+		case "clock":
+			text = time.Now().Format("15:04:05")
+
+		case "connected-count":
+			var count int
+			for _, device := range UI.Bluez.GetDevices() {
+				if device.Connected {
+					count++
+				}
+			}
+
+			text = strconv.Itoa(count) + " connected"
+
+		case "scan-state":
+			text = "Not scanning"
+			if adapter.Discovering {
+				text = "Scanning"
+			}
+		}
+
+		texts = append(texts, tview.Escape(text))
+	}
+
+	return theme.ColorWrap(theme.ThemeStatusInfo, strings.Join(texts, " | "))
+}
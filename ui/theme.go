@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"sync"
+
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/darkhz/bluetuith/theme"
+)
+
+// themeHandlers re-apply the theme colors to the widgets which
+// are created only once, when the theme is reloaded.
+var themeHandlers struct {
+	handlers []func()
+
+	lock sync.Mutex
+}
+
+// onThemeReload registers a handler to re-apply the theme colors to a widget.
+func onThemeReload(handler func()) {
+	themeHandlers.lock.Lock()
+	defer themeHandlers.lock.Unlock()
+
+	themeHandlers.handlers = append(themeHandlers.handlers, handler)
+}
+
+// watchTheme reloads the theme when the configuration file changes.
+func watchTheme() {
+	if theme.ColorsDisabled() {
+		return
+	}
+
+	err := cmd.WatchThemeConfig(func(themeConfig map[string]interface{}, err error) {
+		if err != nil {
+			ErrorMessage(err)
+			return
+		}
+
+		UI.QueueUpdateDraw(func() {
+			if err := theme.ReloadThemeConfig(themeConfig); err != nil {
+				ErrorMessage(err)
+				return
+			}
+
+			applyTheme()
+			InfoMessage("Theme reloaded", false)
+		})
+	})
+	if err != nil {
+		ErrorMessage(err)
+	}
+}
+
+// applyTheme redraws the widgets with the current theme colors.
+// Popups and modals use the current theme when they are opened again.
+func applyTheme() {
+	themeHandlers.lock.Lock()
+	handlers := append([]func(){}, themeHandlers.handlers...)
+	themeHandlers.lock.Unlock()
+
+	for _, handler := range handlers {
+		handler()
+	}
+
+	help.page = ""
+	showStatusHelp(UI.page)
+
+	listDevices()
+	updateAdapterStatus(UI.Bluez.GetCurrentAdapter())
+}
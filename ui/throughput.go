@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+)
+
+// throughput computes the speed and the estimated time remaining of a
+// transfer, from a rolling window of transferred byte samples.
+type throughput struct {
+	samples []throughputSample
+
+	total   int64
+	changed time.Time
+}
+
+// throughputSample describes the number of bytes transferred at a point in time.
+type throughputSample struct {
+	at          time.Time
+	transferred int64
+}
+
+const (
+	// throughputWindow is the duration of the samples used to compute the speed.
+	throughputWindow = 5 * time.Second
+
+	// throughputStallTimeout is the duration after which a transfer
+	// without any transferred bytes is marked as stalled.
+	throughputStallTimeout = 5 * time.Second
+)
+
+// newThroughput returns a new throughput for a transfer of the provided size.
+func newThroughput(total int64, now time.Time) *throughput {
+	return &throughput{
+		samples: []throughputSample{{now, 0}},
+		total:   total,
+		changed: now,
+	}
+}
+
+// add adds a sample of the transferred bytes.
+func (t *throughput) add(transferred int64, now time.Time) {
+	if last := t.samples[len(t.samples)-1]; transferred != last.transferred {
+		t.changed = now
+	}
+
+	t.samples = append(t.samples, throughputSample{now, transferred})
+
+	// Keep the newest sample which is older than the window, so that
+	// the speed is always computed over at least the window duration.
+	var start int
+	for i, sample := range t.samples {
+		if now.Sub(sample.at) < throughputWindow {
+			break
+		}
+
+		start = i
+	}
+
+	t.samples = t.samples[start:]
+}
+
+// speed returns the transfer speed in bytes per second.
+func (t *throughput) speed() float64 {
+	first, last := t.samples[0], t.samples[len(t.samples)-1]
+
+	elapsed := last.at.Sub(first.at).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+
+	return float64(last.transferred-first.transferred) / elapsed
+}
+
+// stalled returns if no bytes were transferred for the stall timeout.
+func (t *throughput) stalled(now time.Time) bool {
+	return now.Sub(t.changed) >= throughputStallTimeout
+}
+
+// text returns the speed and the estimated time remaining of the transfer.
+func (t *throughput) text(now time.Time) string {
+	if t.stalled(now) {
+		return "stalled"
+	}
+
+	speed := t.speed()
+	if speed <= 0 {
+		return ""
+	}
+
+	remaining := t.total - t.samples[len(t.samples)-1].transferred
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	eta := time.Duration(float64(remaining) / speed * float64(time.Second))
+
+	return fmt.Sprintf("%s/s, %s left", formatSize(int64(speed)), eta.Round(time.Second))
+}
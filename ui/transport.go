@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"sync"
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+	"github.com/godbus/dbus/v5"
+)
+
+// IdleDisconnect holds the timers used to disconnect
+// idle audio devices.
+type IdleDisconnect struct {
+	timers map[string]*time.Timer
+	lock   sync.Mutex
+}
+
+var idleDisconnect = IdleDisconnect{
+	timers: make(map[string]*time.Timer),
+}
+
+// transportEvent handles media transport events.
+func transportEvent(signal *dbus.Signal, signalData interface{}) {
+	transport, ok := signalData.(bluez.MediaTransport)
+	if !ok || transport.Device == "" {
+		return
+	}
+
+	refreshDeviceRow(transport.Device)
+
+	UI.QueueUpdateDraw(func() {
+		updateDeviceInfo(UI.Bluez.GetDevice(transport.Device))
+	})
+
+	if isDeviceIdle(transport.Device) {
+		startIdleDisconnect(transport.Device)
+		return
+	}
+
+	stopIdleDisconnect(transport.Device)
+}
+
+// checkIdleDevices starts the auto-disconnect timers for
+// the currently connected devices whose media transports are idle.
+func checkIdleDevices() {
+	for _, device := range UI.Bluez.GetDevices() {
+		if !device.Connected {
+			continue
+		}
+
+		if isDeviceIdle(device.Path) {
+			startIdleDisconnect(device.Path)
+		}
+	}
+}
+
+// isDeviceIdle returns whether the device has media transports, and
+// all of them are idle. Devices without any media transports, for example
+// devices whose transports have not appeared yet, are not considered idle.
+func isDeviceIdle(devicePath string) bool {
+	transports := UI.Bluez.GetTransports(devicePath)
+	if transports == nil {
+		return false
+	}
+
+	for _, transport := range transports {
+		if transport.State != "idle" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// startIdleDisconnect starts the auto-disconnect timer for the device,
+// if an auto-disconnect duration is configured for it.
+func startIdleDisconnect(devicePath string) {
+	device := UI.Bluez.GetDevice(devicePath)
+	if device.Path == "" || !device.Connected {
+		return
+	}
+
+	duration, err := time.ParseDuration(cmd.GetDeviceProperty(device.Address, "auto-disconnect"))
+	if err != nil || duration <= 0 {
+		return
+	}
+
+	idleDisconnect.lock.Lock()
+	defer idleDisconnect.lock.Unlock()
+
+	if _, ok := idleDisconnect.timers[devicePath]; ok {
+		return
+	}
+
+	idleDisconnect.timers[devicePath] = time.AfterFunc(duration, func() {
+		idleDisconnect.lock.Lock()
+		delete(idleDisconnect.timers, devicePath)
+		idleDisconnect.lock.Unlock()
+
+		disconnectIdleDevice(devicePath)
+	})
+}
+
+// stopIdleDisconnect stops the auto-disconnect timer for the device.
+func stopIdleDisconnect(devicePath string) {
+	idleDisconnect.lock.Lock()
+	defer idleDisconnect.lock.Unlock()
+
+	timer, ok := idleDisconnect.timers[devicePath]
+	if !ok {
+		return
+	}
+
+	timer.Stop()
+	delete(idleDisconnect.timers, devicePath)
+}
+
+// disconnectIdleDevice disconnects the device if its
+// media transports are still idle.
+func disconnectIdleDevice(devicePath string) {
+	device := UI.Bluez.GetDevice(devicePath)
+	if device.Path == "" || !device.Connected || !isDeviceIdle(devicePath) {
+		return
+	}
+
+	if err := UI.Bluez.Disconnect(devicePath); err != nil {
+		ErrorMessage(err)
+		return
+	}
+
+	InfoMessage("Disconnected idle device "+device.Name, false)
+}
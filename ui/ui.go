@@ -95,6 +95,14 @@ func StartUI() {
 		AddItem(statusBar(), UI.Status.itemCount, 0, false)
 	UI.Layout.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
 
+	onThemeReload(func() {
+		box.SetBackgroundColor(theme.GetColor(theme.ThemeMenuBar))
+		menuArea.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+		flex.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+		UI.Pages.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+		UI.Layout.SetBackgroundColor(theme.GetColor(theme.ThemeBackground))
+	})
+
 	UI.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		operation := cmd.KeyOperation(event)
 
@@ -134,6 +142,14 @@ func StartUI() {
 	updateAdapterStatus(UI.Bluez.GetCurrentAdapter())
 	setAdapterStates()
 	connectDeviceByAddress()
+	go func() {
+		connectPreferredAudio()
+		reconnectDevices()
+	}()
+	startPresenceCheck()
+	startScanRefresh()
+	watchTheme()
+	startIPC()
 
 	InfoMessage("bluetuith is ready.", false)
 
@@ -145,6 +161,7 @@ func StartUI() {
 // StopUI stops the UI.
 func StopUI() {
 	stopStatus()
+	stopIPC()
 
 	UI.Stop()
 }
@@ -18,9 +18,7 @@ import (
 
 // SetTrusted sets the trusted state of a device.
 func SetTrusted(devicePath string, enable bool) error {
-	UI.Bluez.SetDeviceProperty(devicePath, "Trusted", true)
-
-	return nil
+	return UI.Bluez.SetTrusted(devicePath, enable)
 }
 
 // GetDeviceFromPath gets a device from the device path.
@@ -49,28 +47,143 @@ func formatSize(size int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "kMGTPE"[exp])
 }
 
-// savefile moves a file from the obex cache to a specified user-accessible directory.
-// If the directory is not specified, it automatically creates a directory in the
-// user's home path and moves the file there.
-func savefile(path string) error {
-	userpath := cmd.GetProperty("receive-dir")
-	if userpath == "" {
-		homedir, err := os.UserHomeDir()
-		if err != nil {
-			return err
-		}
+// SaveFile moves a file from the obex cache to a specified user-accessible directory,
+// and returns the new path of the file. If the directory is not specified, it automatically
+// creates a directory in the user's home path and moves the file there. If the address of
+// the sending device is provided, the file is saved to the directory of the device. If a file
+// with the same name exists, it is handled according to the "receive-conflict" option.
+func SaveFile(path, address string) (string, error) {
+	userpath, err := GetDeviceReceiveDir(address)
+	if err != nil {
+		return "", err
+	}
+
+	savedPath := filepath.Join(userpath, filepath.Base(path))
+	if savedPath == filepath.Clean(path) {
+		return savedPath, nil
+	}
 
-		userpath = filepath.Join(homedir, "bluetuith")
+	if _, err := os.Stat(savedPath); err == nil {
+		switch cmd.GetProperty("receive-conflict") {
+		case "overwrite":
+			cmd.Log("transfer", "%s: Overwriting the existing file", savedPath)
 
-		if _, err := os.Stat(userpath); err != nil {
-			err = os.Mkdir(userpath, 0700)
+		case "skip":
+			cmd.Log("transfer", "%s: Skipped saving, since the file already exists", savedPath)
+			os.Remove(path)
+
+			return "", fmt.Errorf("Skipped saving %s, since it already exists", filepath.Base(savedPath))
+
+		default:
+			renamedPath, err := getUniquePath(savedPath)
 			if err != nil {
-				return err
+				return "", err
 			}
+
+			cmd.Log("transfer", "%s: File already exists, renamed to %s", savedPath, filepath.Base(renamedPath))
+
+			savedPath = renamedPath
 		}
 	}
 
-	return os.Rename(path, filepath.Join(userpath, filepath.Base(path)))
+	return savedPath, os.Rename(path, savedPath)
+}
+
+// getUniquePath returns the path with a counter appended to the file name,
+// for example "file (1).txt", such that no file exists at the path. An error
+// is returned if a path cannot be checked, or if no unique path is found.
+func getUniquePath(path string) (string, error) {
+	const maxAttempts = 10000
+
+	ext := filepath.Ext(path)
+	name := strings.TrimSuffix(path, ext)
+
+	for i := 1; i <= maxAttempts; i++ {
+		uniquePath := fmt.Sprintf("%s (%d)%s", name, i, ext)
+
+		_, err := os.Stat(uniquePath)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			return uniquePath, nil
+
+		case err != nil:
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("Could not find a unique name for %s", filepath.Base(path))
+}
+
+// GetDeviceReceiveDir returns the directory where files received from the device
+// with the provided address are saved. If the "receive-device-dirs" option is set,
+// this is a subdirectory of the receive directory named after the device, which is
+// created if it does not exist.
+func GetDeviceReceiveDir(address string) (string, error) {
+	userpath, err := getReceiveDir()
+	if err != nil || address == "" || !cmd.IsPropertyEnabled("receive-device-dirs") {
+		return userpath, err
+	}
+
+	name := address
+	for _, device := range UI.Bluez.GetDevices() {
+		if device.Address == address && device.Alias != "" {
+			name = device.Alias
+			break
+		}
+	}
+
+	userpath = filepath.Join(userpath, sanitizeDirName(name, address))
+	if err := os.MkdirAll(userpath, 0700); err != nil {
+		return "", err
+	}
+
+	return userpath, nil
+}
+
+// sanitizeDirName returns the name with all path separators and control
+// characters replaced, so that it can be safely used as a directory name.
+// If no usable name remains, the fallback is returned.
+func sanitizeDirName(name, fallback string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < 0x20 || r == 0x7f {
+			return '_'
+		}
+
+		return r
+	}, name)
+
+	name = strings.Trim(name, ". ")
+	if name == "" {
+		return fallback
+	}
+
+	return name
+}
+
+// getReceiveDir returns the directory where received files are saved.
+// If the "receive-dir" option is not specified, the directory is created
+// in the user's home path.
+func getReceiveDir() (string, error) {
+	userpath := cmd.GetProperty("receive-dir")
+	if userpath != "" {
+		return userpath, nil
+	}
+
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	userpath = filepath.Join(homedir, "bluetuith")
+
+	if _, err := os.Stat(userpath); err != nil {
+		err = os.Mkdir(userpath, 0700)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return userpath, nil
 }
 
 // getSelectionXY gets the coordinates of the current table selection.
@@ -103,6 +216,10 @@ func getProgress(media bluez.MediaProperties, buttons string, width int, skip bo
 	var length int
 
 	title := media.Track.Title
+	if title == "" {
+		title = "<Unknown Title>"
+	}
+
 	position := media.Position
 	duration := media.Track.Duration
 	number := strconv.FormatUint(uint64(media.Track.TrackNumber), 10)
@@ -130,7 +247,7 @@ func getProgress(media bluez.MediaProperties, buttons string, width int, skip bo
 	if position >= math.MaxUint32 {
 		position = 0
 	}
-	if position >= duration {
+	if duration > 0 && position >= duration {
 		position = duration
 	}
 
@@ -145,14 +262,34 @@ func getProgress(media bluez.MediaProperties, buttons string, width int, skip bo
 		endlength = width
 	}
 
-	track := "Track " + number + "/" + total
-	progress := " " + formatDuration(position) +
-		" |" + strings.Repeat("█", length) + strings.Repeat(" ", endlength) + "| " +
-		formatDuration(duration)
+	var track string
+	if media.Track.TrackNumber > 0 {
+		track = "Track " + number + "/" + total
+	}
+
+	progress := " " + formatDuration(position)
+	if duration > 0 {
+		progress += " |" + strings.Repeat("█", length) + strings.Repeat(" ", endlength) + "| " +
+			formatDuration(duration)
+	}
 
 	return title, buttons, track, progress
 }
 
+// getTrackInfo returns the artist and album of the track, leaving out
+// the properties which are not reported by the player.
+func getTrackInfo(track bluez.TrackProperties) string {
+	var info []string
+
+	for _, property := range []string{track.Artist, track.Album} {
+		if property != "" {
+			info = append(info, property)
+		}
+	}
+
+	return strings.Join(info, " - ")
+}
+
 // horizontalLine returns a box with a thick horizontal line.
 func horizontalLine() *tview.Box {
 	return tview.NewBox().
@@ -1,5 +1,16 @@
 package ui
 
+import (
+	"time"
+
+	"github.com/darkhz/bluetuith/bluez"
+	"github.com/darkhz/bluetuith/cmd"
+)
+
+// serviceRestartTimeout is the duration to wait for the adapters
+// to be available after the bluez daemon restarts.
+const serviceRestartTimeout = 10 * time.Second
+
 // watchEvent listens to DBus events and passes them to
 // the event handlers.
 func watchEvent() {
@@ -7,9 +18,72 @@ func watchEvent() {
 	defer UI.Bluez.Conn().RemoveSignal(watchSignal)
 
 	for signal := range watchSignal {
+		if cmd.IsLogLevelEnabled(cmd.LogLevelDebug) {
+			cmd.LogDebug("signal", "%s: %s %v", signal.Path, signal.Name, signal.Body)
+		}
+
 		signalData := UI.Bluez.ParseSignalData(signal)
 
 		adapterEvent(signal, signalData)
 		deviceEvent(signal, signalData)
+		transportEvent(signal, signalData)
+		playerEvent(signalData)
+		serviceEvent(signalData)
+	}
+}
+
+// serviceEvent handles the bluez daemon (bluetoothd) starting or stopping.
+// When the daemon stops, the device list is cleared until it is started again,
+// after which the adapters and devices are refreshed.
+func serviceEvent(signalData interface{}) {
+	state, ok := signalData.(bluez.ServiceState)
+	if !ok {
+		return
+	}
+
+	if !state.Running {
+		UI.Bluez.ClearStore()
+
+		UI.QueueUpdateDraw(func() {
+			DeviceTable.Clear()
+		})
+		InfoMessage("The bluetooth daemon has stopped, reconnecting...", true)
+
+		return
 	}
+
+	go restoreService()
+}
+
+// restoreService refreshes the adapters and devices after the bluez daemon
+// has restarted, and selects the previously selected adapter if it exists.
+func restoreService() {
+	currentAdapter := UI.Bluez.GetCurrentAdapter()
+
+	timeout := time.After(serviceRestartTimeout)
+	for {
+		if err := UI.Bluez.RefreshStore(); err == nil && len(UI.Bluez.GetAdapters()) > 0 {
+			break
+		}
+
+		select {
+		case <-timeout:
+			InfoMessage("The bluetooth daemon has restarted, but no adapters were found", false)
+			return
+
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	if adapter := UI.Bluez.GetAdapter(currentAdapter.Path); adapter.Path != "" {
+		UI.Bluez.SetCurrentAdapter(adapter)
+	} else {
+		UI.Bluez.SetCurrentAdapter()
+	}
+
+	UI.QueueUpdateDraw(func() {
+		listDevices()
+		updateAdapterStatus(UI.Bluez.GetCurrentAdapter())
+	})
+	InfoMessage("Reconnected to the bluetooth daemon", false)
 }